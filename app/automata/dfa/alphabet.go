@@ -0,0 +1,84 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"cmp"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// AlphabetOf returns the [nfa.Alphabet] of d or false if the guard of any of its states has predicates that aren't
+// built from an [nfa.Class] (see [State.GuardBoundaries]).
+func AlphabetOf[S cmp.Ordered, V any](d *Dfa[S, V]) (*nfa.Alphabet[S], bool) {
+	var sets [][]S
+	var classes []nfa.Class[S]
+
+	for _, state := range d.states {
+		symbolsByTarget := make(map[*State[S, V]][]S)
+		var targets []*State[S, V]
+
+		for symbol, target := range state.transitions {
+			if _, ok := symbolsByTarget[target]; !ok {
+				targets = append(targets, target)
+			}
+
+			symbolsByTarget[target] = append(symbolsByTarget[target], symbol)
+		}
+
+		for _, target := range targets {
+			sets = append(sets, symbolsByTarget[target])
+		}
+
+		if state.guard == nil {
+			continue
+		}
+
+		if state.guard.boundaries == nil {
+			return nil, false
+		}
+
+		for _, target := range state.guard.targets {
+			classes = append(classes, guardTarget[S, V]{guard: state.guard, target: target})
+		}
+	}
+
+	return nfa.NewAlphabet(sets, classes), true
+}
+
+// A 'guardTarget' is the [nfa.Class] of the symbols that a guard dispatches to target.
+type guardTarget[S comparable, V any] struct {
+	guard  *guard[S, V]
+	target *State[S, V]
+}
+
+// Contains reports whether the guard dispatches symbol to the target.
+func (class guardTarget[S, V]) Contains(symbol S) bool {
+	return class.guard.outgoingFor(symbol) == class.target
+}
+
+// Boundaries returns the boundaries of the classes of the predicates of the guard.
+func (class guardTarget[S, V]) Boundaries() []S { return class.guard.boundaries }
@@ -0,0 +1,193 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns an 'Nfa' for one or more symbols from 0 up to 399 (accepted by a class transition per decade), and for the
+// concrete sequences 15 and 15 16.
+func newDecadeMachine() *nfa.Nfa[int, int] {
+	machine := nfa.New[int, int]()
+
+	for n := range 40 {
+		state := machine.NewState()
+		machine.AddClassTransition(machine.Start(), state, decade(n))
+		machine.ConnectEpsilon(machine.AddAcceptingEpsilonTransition(state, n), machine.Start())
+	}
+
+	fifteen := machine.Add(machine.Start(), 15)
+	machine.AddAcceptingEpsilonTransition(fifteen, 100)
+	machine.AddAcceptingEpsilonTransition(machine.Add(fifteen, 16), 200)
+
+	return machine
+}
+
+// Returns the longest match of input by following the transitions of machine.
+func longestIntMatch(machine *dfa.Dfa[int, int], input []int) (nfa.Match[int], bool) {
+	var match nfa.Match[int]
+
+	found := false
+	state := machine.Start()
+
+	for idx := 0; state != nil; idx++ {
+		if state.IsAccepting() {
+			match = nfa.Match[int]{Value: state.AcceptValue(), AcceptIdx: state.AcceptIdx(), Length: idx}
+			found = true
+		}
+
+		if idx == len(input) {
+			break
+		}
+
+		state = state.OutgoingFor(input[idx])
+	}
+
+	return match, found
+}
+
+// UT: Convert an 'Nfa' whose symbols share classes to a 'Dfa'.
+func TestFromNfa_SymbolClasses(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	nMachine := nfa.New[int, int]()
+
+	for n := range 40 {
+		state := nMachine.NewState()
+		nMachine.AddClassTransition(nMachine.Start(), state, decade(n))
+		nMachine.ConnectEpsilon(nMachine.AddAcceptingEpsilonTransition(state, n), nMachine.Start())
+	}
+
+	// NOTE: The symbols 20 up to 29 share a class, since they're consumed by the same transitions.
+	shared := nMachine.NewState()
+	nMachine.AddAcceptingEpsilonTransition(shared, 300)
+
+	for symbol := range 10 {
+		nMachine.Connect(nMachine.Start(), shared, 20+symbol)
+	}
+
+	fifteen := nMachine.Add(nMachine.Start(), 15)
+	nMachine.AddAcceptingEpsilonTransition(fifteen, 100)
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(fifteen, 16), 200)
+
+	// Act.
+	dMachine := dfa.FromNfa(nMachine)
+
+	// Assert.
+	symbols := []int{-1, 0, 9, 10, 15, 16, 17, 20, 25, 399, 400}
+	inputs := [][]int{nil}
+
+	for idx := 0; idx < len(inputs); idx++ {
+		if len(inputs[idx]) == 3 {
+			continue
+		}
+
+		for _, symbol := range symbols {
+			inputs = append(inputs, append(append([]int(nil), inputs[idx]...), symbol))
+		}
+	}
+
+	for _, input := range inputs {
+		want, wantOk := nMachine.Match(input)
+		got, gotOk := longestIntMatch(dMachine, input)
+
+		assert.Equalf(t, got == want && gotOk == wantOk, true, "\n\n"+
+			"UT Name:  When converting an 'Nfa' with classes of symbols, the 'Dfa' matches %v like the 'Nfa'.\n"+
+			"\033[32mExpected: %v (%t).\033[0m\n"+
+			"\033[31mActual:   %v (%t).\033[0m\n\n", input, want, wantOk, got, gotOk)
+	}
+}
+
+// UT: Partition the alphabet of a 'Dfa' into equivalence classes.
+func TestAlphabetOf(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	dMachine := dfa.FromNfa(newDecadeMachine())
+
+	// Act.
+	alphabet, ok := dfa.AlphabetOf(dMachine)
+
+	// Assert.
+	assert.Truef(t, ok, "\n\n"+
+		"UT Name:  When the guards are built from classes, the alphabet is partitioned.\n"+
+		"\033[32mExpected: true.\033[0m\n"+
+		"\033[31mActual:   %t.\033[0m\n\n", ok)
+
+	for _, state := range dMachine.States() {
+		for _, pair := range [][2]int{{10, 19}, {11, 14}, {20, 29}, {-5, 400}} {
+			same := alphabet.ClassOf(pair[0]) == alphabet.ClassOf(pair[1])
+			equal := state.OutgoingFor(pair[0]) == state.OutgoingFor(pair[1])
+
+			assert.Truef(t, same && equal, "\n\n"+
+				"UT Name:  When %d and %d share a class, state %d doesn't distinguish them.\n"+
+				"\033[32mExpected: true, true.\033[0m\n"+
+				"\033[31mActual:   %t, %t.\033[0m\n\n", pair[0], pair[1], state.ID(), same, equal)
+		}
+	}
+
+	got := alphabet.ClassOf(15) == alphabet.ClassOf(14)
+
+	assert.Falsef(t, got, "\n\n"+
+		"UT Name:  When a symbol has a concrete transition that another one doesn't have, they're in different classes.\n"+
+		"\033[32mExpected: false.\033[0m\n"+
+		"\033[31mActual:   %t.\033[0m\n\n", got)
+}
+
+var benchmarkClassesOutput bool // Output of the benchmark(s).
+
+// Benchmark(s): Match input against a 'Dfa' with classes of symbols.
+func BenchmarkDfa_Match_Decades(b *testing.B) {
+	dMachine := dfa.FromNfa(newDecadeMachine())
+	input := benchmarkDecades(10_000)
+
+	for b.Loop() {
+		state := dMachine.Start()
+
+		for _, symbol := range input {
+			state = state.OutgoingFor(symbol)
+		}
+
+		benchmarkClassesOutput = state.IsAccepting()
+	}
+}
+
+// Returns count symbols from 0 up to 399.
+func benchmarkDecades(count int) []int {
+	input := make([]int, count)
+
+	for idx := range input {
+		input[idx] = (idx * 37) % 400
+	}
+
+	return input
+}
@@ -0,0 +1,225 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"strconv"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// HasAssertions reports whether the DFA was built from an [nfa.Nfa] with assertion transitions.
+// The start state of such a DFA depends on the symbol before the input (see [Dfa.StartAfter]) and its states may only
+// accept depending on the symbol after the input (see [State.AcceptingBefore]).
+func (d *Dfa[S, V]) HasAssertions() bool { return d.starts != nil }
+
+// StartAfter returns the start state of the DFA for input that follows a symbol of kind prev ([nfa.KindNone] for input
+// that starts at the beginning).
+// For a DFA without assertions, this is always [Dfa.Start].
+func (d *Dfa[S, V]) StartAfter(prev nfa.SymbolKind) *State[S, V] {
+	if d.starts == nil {
+		return d.start
+	}
+
+	return d.starts[prev]
+}
+
+// IsConditional reports whether the acceptance of the state depends on the kind of the next symbol.
+// A conditional state is never accepting by itself (see [State.AcceptingBefore]).
+func (s *State[S, V]) IsConditional() bool { return s.conditions != nil }
+
+// AcceptingBefore returns a state that describes the acceptance of the state when it's followed by a symbol of kind
+// next ([nfa.KindNone] at the end of the input), or nil if the state doesn't accept there.
+//
+// For a state that isn't conditional, this is the state itself if it's accepting. Otherwise, the returned state only
+// carries the acceptance index, the accepting value and the trailing context; it doesn't have any transitions.
+func (s *State[S, V]) AcceptingBefore(next nfa.SymbolKind) *State[S, V] {
+	if s.conditions != nil {
+		return s.conditions[next]
+	}
+
+	if s.IsAccepting() {
+		return s
+	}
+
+	return nil
+}
+
+// Reports whether any of states has an assertion transition.
+func hasAssertions[S comparable, V any](states []*nfa.State[S, V]) bool {
+	for _, state := range states {
+		if len(state.Assertions()) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the key of the subset states, following a symbol of kind prev.
+//
+// Reasoning:
+// The assertion transitions that can be taken from a subset depend on the kind of the symbol before it, so the same
+// subset must be split into one [State] per kind ("look-behind state splitting"). Subsets without assertions don't
+// depend on that kind, so they keep a single [State].
+func (builder *dfaBuilder[S, V]) subsetKey(states []*nfa.State[S, V], prev nfa.SymbolKind) string {
+	sKey := calculateStatesKey(states)
+
+	if !hasAssertions(states) {
+		return sKey
+	}
+
+	return sKey + "|" + strconv.Itoa(int(prev))
+}
+
+// Returns states together with all the [nfa.State]s that are reachable from them through assertion transitions that
+// hold between a symbol of kind prev and a symbol of kind next (and epsilon transitions).
+// If no assertion transition leaves states, states is returned as is.
+func (builder *dfaBuilder[S, V]) resolve(states []*nfa.State[S, V], prev, next nfa.SymbolKind) []*nfa.State[S, V] {
+	if !hasAssertions(states) {
+		return states
+	}
+
+	seen := make(map[int]bool, len(states))
+	resolved := make([]*nfa.State[S, V], 0, len(states))
+
+	for _, state := range states {
+		seen[state.ID()] = true
+		resolved = append(resolved, state)
+	}
+
+	// NOTE: The resolved states grow while they're being traversed, so that the states that are added are resolved too.
+	for idx := 0; idx < len(resolved); idx++ {
+		for _, transition := range resolved[idx].Assertions() {
+			if !transition.Assertion.Holds(prev, next) {
+				continue
+			}
+
+			for _, reachable := range builder.closures.closureOf(transition.EndState) {
+				if !seen[reachable.ID()] {
+					seen[reachable.ID()] = true
+					resolved = append(resolved, reachable)
+				}
+			}
+		}
+	}
+
+	return resolved
+}
+
+// Sets the acceptance of state, which is built from states (following a symbol of kind prev).
+// When the acceptance depends on the kind of the next symbol, state becomes conditional.
+func (builder *dfaBuilder[S, V]) setConditions(state *State[S, V], states []*nfa.State[S, V], prev nfa.SymbolKind) {
+	conditions := make([]*State[S, V], nfa.NumSymbolKinds)
+	conditional := false
+
+	for next := range nfa.SymbolKind(nfa.NumSymbolKinds) {
+		acceptIdx, value, trailing := findAcceptanceIdx(builder.resolve(states, prev, next))
+
+		if acceptIdx > -1 {
+			conditions[next] = &State[S, V]{id: -1, acceptIdx: acceptIdx, value: value, trailing: trailing}
+		}
+
+		if acceptIdxOf(conditions[next]) != acceptIdxOf(conditions[nfa.KindNone]) {
+			conditional = true
+		}
+	}
+
+	if conditional {
+		state.conditions = conditions
+	} else if accepting := conditions[nfa.KindNone]; accepting != nil {
+		state.acceptIdx, state.value, state.trailing = accepting.acceptIdx, accepting.value, accepting.trailing
+	}
+}
+
+// Returns the acceptance index of state or -1 if state is nil.
+func acceptIdxOf[S comparable, V any](state *State[S, V]) int {
+	if state == nil {
+		return -1
+	}
+
+	return state.acceptIdx
+}
+
+// Adds the concrete transitions of from for the symbols that aren't of kind [nfa.KindOther].
+// Those symbols take the assertion transitions that hold before them, so they're expanded separately.
+func (builder *dfaBuilder[S, V]) expandSpecialSymbols(from *State[S, V], current subset[S, V]) {
+	for _, sym := range builder.special {
+		kind := nfa.KindOf(sym)
+		states := builder.resolve(current.states, current.prev, kind)
+		symbolStates := findReachableStatesForSymbol(states, sym)
+
+		for _, transition := range findPredicateTransitions(states) {
+			if transition.Fn(sym) {
+				symbolStates = append(symbolStates, transition.EndState)
+			}
+		}
+
+		if len(symbolStates) > 0 {
+			from.transitions[sym] = builder.ensureState(builder.closures.findPossibleStates(symbolStates...), kind)
+		}
+	}
+}
+
+// Returns the predicate transitions leaving states.
+func findPredicateTransitions[S comparable, V any](states []*nfa.State[S, V]) []nfa.PredicateTransition[S, V] {
+	var transitions []nfa.PredicateTransition[S, V]
+
+	for _, state := range states {
+		transitions = append(transitions, state.Predicates()...)
+	}
+
+	return transitions
+}
+
+// Returns the symbols that aren't of kind [nfa.KindOther] and the boundaries of the symbols that are, for symbols of
+// type rune and byte. For other types, every symbol is of kind [nfa.KindOther], so it returns nil.
+func specialSymbols[S comparable]() ([]S, []S) {
+	var special, boundaries []S
+
+	add := func(symbols *[]S, r rune) {
+		switch any(*new(S)).(type) {
+		case rune:
+			*symbols = append(*symbols, any(r).(S))
+		case byte:
+			*symbols = append(*symbols, any(byte(r)).(S))
+		}
+	}
+
+	// NOTE: The runs of special symbols are: '\n', '0'-'9', 'A'-'Z', '_' and 'a'-'z'.
+	add(&boundaries, 0)
+
+	for _, run := range [][2]rune{{'\n', '\n'}, {'0', '9'}, {'A', 'Z'}, {'_', '_'}, {'a', 'z'}} {
+		for r := run[0]; r <= run[1]; r++ {
+			add(&special, r)
+		}
+
+		add(&boundaries, run[0])
+		add(&boundaries, run[1]+1)
+	}
+
+	return special, boundaries
+}
@@ -0,0 +1,137 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns the longest match of machine in input[start:], taking the symbols around it into account, as an [nfa.Match].
+func longestMatch[V any](machine *dfa.Dfa[rune, V], input []rune, start int) (nfa.Match[V], bool) {
+	var match nfa.Match[V]
+
+	found := false
+	state := machine.StartAfter(nfa.KindAt(input, start-1))
+
+	for idx := start; state != nil; idx++ {
+		if accepting := state.AcceptingBefore(nfa.KindAt(input, idx)); accepting != nil {
+			match = nfa.Match[V]{Value: accepting.AcceptValue(), AcceptIdx: accepting.AcceptIdx(), Length: idx - start}
+			found = true
+		}
+
+		if idx == len(input) {
+			break
+		}
+
+		state = state.OutgoingFor(input[idx])
+	}
+
+	return match, found
+}
+
+// Returns every string of at most n runes of alphabet.
+func allStrings(alphabet string, n int) []string {
+	result := []string{""}
+
+	for idx := 0; idx < len(result); idx++ {
+		if len([]rune(result[idx])) == n {
+			continue
+		}
+
+		for _, r := range alphabet {
+			result = append(result, result[idx]+string(r))
+		}
+	}
+
+	return result
+}
+
+// UT: Convert an 'Nfa' with assertion transitions to a 'Dfa'.
+func TestFromNfa_Assertions(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	// Rule 0: "ab" at the start of a line, followed by a word boundary.
+	// Rule 1: "ab" followed by the end of a line.
+	// Rule 2: a symbol other than 'a', followed by NO word boundary.
+	// Rule 3: a run of 'b's at the start of the input.
+	nMachine := nfa.New[rune, int]()
+	s0 := nMachine.Start()
+
+	lineStart := nMachine.NewState()
+	nMachine.AddAssertion(s0, lineStart, nfa.BeginLine)
+	boundary := nMachine.NewState()
+	nMachine.AddAssertion(nMachine.Add(nMachine.Add(lineStart, 'a'), 'b'), boundary, nfa.WordBoundary)
+	nMachine.AddAcceptingEpsilonTransition(boundary, 0)
+
+	lineEnd := nMachine.NewState()
+	nMachine.AddAssertion(nMachine.Add(nMachine.Add(s0, 'a'), 'b'), lineEnd, nfa.EndLine)
+	nMachine.AddAcceptingEpsilonTransition(lineEnd, 1)
+
+	other, inside := nMachine.NewState(), nMachine.NewState()
+	nMachine.AddPredicateTransition(s0, other, func(r rune) bool { return r != 'a' })
+	nMachine.AddAssertion(other, inside, nfa.NoWordBoundary)
+	nMachine.AddAcceptingEpsilonTransition(inside, 2)
+
+	textStart, bs := nMachine.NewState(), nMachine.NewState()
+	nMachine.AddAssertion(s0, textStart, nfa.BeginText)
+	nMachine.Connect(textStart, bs, 'b')
+	nMachine.Connect(bs, bs, 'b')
+	nMachine.AddAcceptingEpsilonTransition(bs, 3)
+
+	dMachine := dfa.FromNfa(nMachine)
+	minimal, _ := dfa.Minimize(dMachine)
+
+	// Assert.
+	for name, machine := range map[string]*dfa.Dfa[rune, int]{"converted": dMachine, "minimized": minimal} {
+		t.Run("The "+name+" 'Dfa' matches the same input as the 'Nfa'.", func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			assert.Truef(t, machine.HasAssertions(), "\n\n"+
+				"UT Name:  The %s 'Dfa' has assertions.\n"+
+				"\033[32mExpected: true.\033[0m\n"+
+				"\033[31mActual:   false.\033[0m\n\n", name)
+
+			for _, input := range allStrings("ab-\n", 5) {
+				for start := range len(input) + 1 {
+					// Act.
+					want, wantOk := nMachine.MatchAt([]rune(input), start)
+					got, gotOk := longestMatch(machine, []rune(input), start)
+
+					// Assert.
+					assert.Equalf(t, got == want && gotOk == wantOk, true, "\n\n"+
+						"UT Name:  The %s 'Dfa' matches %q at offset %d like the 'Nfa'.\n"+
+						"\033[32mExpected: %v (%t).\033[0m\n"+
+						"\033[31mActual:   %v (%t).\033[0m\n\n", name, input, start, want, wantOk, got, gotOk)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,619 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"slices"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// The magic number at the start of the binary form of a [Dfa].
+var binaryMagic = []byte("RDFA")
+
+// The version of the binary form of a [Dfa].
+// It must be incremented whenever the format changes in a way that isn't backwards compatible.
+//
+// Version 2 adds the guards of the states. Version 3 adds the start states per kind of the previous symbol and the
+// conditions of the states, which a DFA with assertions needs (see [Dfa.HasAssertions]). Data written by an older
+// version can still be read.
+const binaryVersion = 3
+
+// ErrInvalidBinary is returned when data doesn't hold a valid binary form of a [Dfa].
+var ErrInvalidBinary = errors.New("dfa: invalid binary data")
+
+// SetCodecs sets the codecs used to serialize the symbols and the accepting values of the DFA.
+//
+// A nil codec selects the default codec for its type, which is only available for the predeclared integer and string
+// types. The codecs must be set before calling [Dfa.MarshalBinary] or [Dfa.UnmarshalBinary] on a DFA with other types
+// of symbols or values.
+func (d *Dfa[S, V]) SetCodecs(symbols Codec[S], values Codec[V]) {
+	d.symbolCodec = symbols
+	d.valueCodec = values
+}
+
+// Load returns the [Dfa] stored in data by [Dfa.MarshalBinary], using symbols and values to decode its symbols and
+// accepting values (see [Dfa.SetCodecs]).
+//
+// The data is fully validated before the DFA is returned: an error wrapping [ErrInvalidBinary] is returned if it's
+// corrupt, truncated or written by an unsupported version.
+func Load[S comparable, V any](data []byte, symbols Codec[S], values Codec[V]) (*Dfa[S, V], error) {
+	d := &Dfa[S, V]{}
+	d.SetCodecs(symbols, values)
+
+	if err := d.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+//
+// The binary form consists of a magic number and a format version, followed by the states of the DFA and a CRC-32
+// checksum of all the preceding bytes. The transitions of every state are sorted by the binary form of their symbol,
+// so marshaling the same DFA always produces the same data.
+//
+// A guard is stored as the ranges of symbols between its boundaries (see [State.GuardBoundaries]) and their targets, so
+// only guards that are built from an [nfa.Class] can be marshaled, and only for symbols of a predeclared integer or
+// string type, since the ranges require the symbols to be ordered. States with trailing context can't be marshaled
+// either, since the splitting of trailing context is a function.
+//
+// The start states per kind of the previous symbol (see [Dfa.StartAfter]) are stored after the start state, and the
+// conditions of a state (see [State.AcceptingBefore]) after its own acceptance, as an acceptance index and an
+// accepting value per kind of the next symbol.
+func (d *Dfa[S, V]) MarshalBinary() ([]byte, error) {
+	symbols, values, err := d.codecs()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if d.start == nil {
+		return nil, errors.New("dfa: can't marshal a DFA without a start state")
+	}
+
+	buf := slices.Clone(binaryMagic)
+	buf = binary.AppendUvarint(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(d.states)))
+	buf = binary.AppendUvarint(buf, uint64(d.start.id))
+	buf = binary.AppendUvarint(buf, uint64(len(d.starts)))
+
+	for _, start := range d.starts {
+		buf = binary.AppendUvarint(buf, uint64(start.id))
+	}
+
+	for _, state := range d.states {
+		if buf, err = appendAcceptance(buf, state, state, values); err != nil {
+			return nil, err
+		}
+
+		buf = binary.AppendUvarint(buf, uint64(len(state.conditions)))
+
+		for _, accepting := range state.conditions {
+			if buf, err = appendAcceptance(buf, state, accepting, values); err != nil {
+				return nil, err
+			}
+		}
+
+		transitions := make([][]byte, 0, len(state.transitions))
+
+		for symbol, target := range state.transitions {
+			transition, err := symbols.AppendBinary(nil, symbol)
+
+			if err != nil {
+				return nil, fmt.Errorf("dfa: encode symbol of state %d: %w", state.id, err)
+			}
+
+			transitions = append(transitions, binary.AppendUvarint(transition, uint64(target.id)))
+		}
+
+		slices.SortFunc(transitions, bytes.Compare)
+
+		buf = binary.AppendUvarint(buf, uint64(len(transitions)))
+
+		for _, transition := range transitions {
+			buf = append(buf, transition...)
+		}
+
+		if buf, err = appendGuard(buf, state, symbols); err != nil {
+			return nil, err
+		}
+	}
+
+	return binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+//
+// The data must have been produced by [Dfa.MarshalBinary] with the same codecs. It's fully validated before the DFA
+// is modified: an error wrapping [ErrInvalidBinary] is returned if it's corrupt, truncated or written by an
+// unsupported version.
+func (d *Dfa[S, V]) UnmarshalBinary(data []byte) error {
+	symbols, values, err := d.codecs()
+
+	if err != nil {
+		return err
+	}
+
+	if len(data) < len(binaryMagic)+crc32.Size || !bytes.HasPrefix(data, binaryMagic) {
+		return fmt.Errorf("%w: missing header", ErrInvalidBinary)
+	}
+
+	body := data[:len(data)-crc32.Size]
+
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(data[len(body):]) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidBinary)
+	}
+
+	reader := &binaryReader{data: body[len(binaryMagic):]}
+
+	version := reader.uvarint()
+
+	if reader.err == nil && (version < 1 || version > binaryVersion) {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidBinary, version)
+	}
+
+	// NOTE: Every state takes at least 2 bytes, which bounds the number of states that have to be allocated.
+	count := reader.count(len(reader.data) / 2)
+	startID := reader.index(count)
+
+	states := make([]*State[S, V], count)
+
+	for id := range states {
+		states[id] = &State[S, V]{
+			id:          id,
+			transitions: make(map[S]*State[S, V]),
+		}
+	}
+
+	var startIDs []int
+
+	if version > 2 {
+		for range reader.kinds() {
+			startIDs = append(startIDs, reader.index(count))
+		}
+	}
+
+	for _, state := range states {
+		state.acceptIdx = reader.acceptIdx()
+
+		if state.acceptIdx > -1 {
+			state.value = decode(reader, values)
+		}
+
+		if version > 2 {
+			state.conditions = readConditions[S](reader, values)
+		}
+
+		if state.conditions != nil && state.acceptIdx > -1 {
+			reader.fail(fmt.Errorf("conditional state %d is accepting", state.id))
+		}
+
+		// NOTE: Every transition takes at least 2 bytes.
+		transitions := reader.count(len(reader.data) / 2)
+
+		for range transitions {
+			symbol := decode(reader, symbols)
+			target := reader.index(count)
+
+			if reader.err != nil {
+				break
+			}
+
+			if _, ok := state.transitions[symbol]; ok {
+				reader.fail(fmt.Errorf("duplicate symbol in state %d", state.id))
+
+				break
+			}
+
+			state.transitions[symbol] = states[target]
+		}
+
+		if version > 1 {
+			state.guard = readGuard(reader, symbols, states)
+		}
+	}
+
+	if reader.err == nil && len(reader.data) > 0 {
+		reader.fail(errors.New("trailing data"))
+	}
+
+	if reader.err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBinary, reader.err)
+	}
+
+	d.start = states[startID]
+	d.starts = nil
+	d.states = states
+
+	for _, id := range startIDs {
+		d.starts = append(d.starts, states[id])
+	}
+	d.nextStateID = count
+
+	return nil
+}
+
+// Appends the acceptance of accepting (state itself or one of its conditions) to buf as its acceptance index, followed
+// by its accepting value if it's accepting (a nil condition isn't accepting).
+func appendAcceptance[S comparable, V any](buf []byte, state, accepting *State[S, V], values Codec[V]) ([]byte, error) {
+	if accepting == nil {
+		return binary.AppendVarint(buf, -1), nil
+	}
+
+	if accepting.trailing != nil {
+		return nil, fmt.Errorf("dfa: can't marshal state %d, since it has trailing context", state.id)
+	}
+
+	buf = binary.AppendVarint(buf, int64(accepting.acceptIdx))
+
+	if !accepting.IsAccepting() {
+		return buf, nil
+	}
+
+	buf, err := values.AppendBinary(buf, accepting.value)
+
+	if err != nil {
+		return nil, fmt.Errorf("dfa: encode value of state %d: %w", state.id, err)
+	}
+
+	return buf, nil
+}
+
+// Reads the conditions written after the acceptance of a state, or nil if the state isn't conditional.
+func readConditions[S comparable, V any](reader *binaryReader, values Codec[V]) []*State[S, V] {
+	var conditions []*State[S, V]
+
+	for range reader.kinds() {
+		acceptIdx := reader.acceptIdx()
+
+		if acceptIdx == -1 {
+			conditions = append(conditions, nil)
+
+			continue
+		}
+
+		conditions = append(conditions, &State[S, V]{id: -1, acceptIdx: acceptIdx, value: decode(reader, values)})
+	}
+
+	return conditions
+}
+
+// Appends the guard of state to buf as the number of ranges of symbols between the boundaries of the guard, followed by
+// the first symbol of every range and its target (as its ID plus one, where 0 means that there's no target). Adjacent
+// ranges with the same target are merged. A state without a guard has 0 ranges.
+func appendGuard[S comparable, V any](buf []byte, state *State[S, V], symbols Codec[S]) ([]byte, error) {
+	if state.guard == nil {
+		return binary.AppendUvarint(buf, 0), nil
+	}
+
+	compare, ok := symbolOrder[S]()
+
+	if !ok || state.guard.boundaries == nil {
+		return nil, fmt.Errorf("dfa: can't marshal state %d, since it has opaque predicate transitions", state.id)
+	}
+
+	boundaries := slices.SortedFunc(slices.Values(state.guard.boundaries), compare)
+	boundaries = slices.CompactFunc(boundaries, func(a, b S) bool { return compare(a, b) == 0 })
+
+	var ranges []byte
+
+	count, previous := 0, -1
+
+	for _, boundary := range boundaries {
+		target := 0
+
+		if next := state.guard.outgoingFor(boundary); next != nil {
+			target = next.id + 1
+		}
+
+		if target == previous {
+			continue
+		}
+
+		var err error
+
+		if ranges, err = symbols.AppendBinary(ranges, boundary); err != nil {
+			return nil, fmt.Errorf("dfa: encode symbol of state %d: %w", state.id, err)
+		}
+
+		ranges = binary.AppendUvarint(ranges, uint64(target))
+		count, previous = count+1, target
+	}
+
+	return append(binary.AppendUvarint(buf, uint64(count)), ranges...), nil
+}
+
+// Reads a guard written by appendGuard, whose targets are taken from states, or nil if it has no ranges.
+//
+// Every target gets its own predicate, which holds for the symbols of the ranges that lead to it, so the minterms of
+// the guard never have more than one predicate that holds.
+func readGuard[S comparable, V any](reader *binaryReader, symbols Codec[S], states []*State[S, V]) *guard[S, V] {
+	// NOTE: Every range takes at least 2 bytes.
+	count := reader.count(len(reader.data) / 2)
+
+	if count == 0 || reader.err != nil {
+		return nil
+	}
+
+	compare, ok := symbolOrder[S]()
+
+	if !ok {
+		reader.fail(errors.New("guard for symbols that aren't ordered"))
+
+		return nil
+	}
+
+	g := &guard[S, V]{targets: make(map[uint64]*State[S, V])}
+	predicates := make(map[*State[S, V]]int) // The predicate of every target.
+	ranges := make([]int, count)             // The predicate of every range (-1 if it has no target).
+
+	for idx := range ranges {
+		boundary := decode(reader, symbols)
+		target := reader.index(len(states) + 1)
+
+		if reader.err != nil {
+			return nil
+		}
+
+		if idx > 0 && compare(g.boundaries[idx-1], boundary) >= 0 {
+			reader.fail(errors.New("guard ranges out of order"))
+
+			return nil
+		}
+
+		g.boundaries = append(g.boundaries, boundary)
+		ranges[idx] = -1
+
+		if target == 0 {
+			continue
+		}
+
+		predicate, ok := predicates[states[target-1]]
+
+		if !ok {
+			if len(predicates) == maxExactGuardPredicates {
+				reader.fail(errors.New("too many guard targets"))
+
+				return nil
+			}
+
+			predicate = len(predicates)
+			predicates[states[target-1]] = predicate
+			g.targets[1<<predicate] = states[target-1]
+		}
+
+		ranges[idx] = predicate
+	}
+
+	// Returns the predicate of the range that symbol belongs to (-1 if it has no target).
+	rangeOf := func(symbol S) int {
+		idx, found := slices.BinarySearchFunc(g.boundaries, symbol, compare)
+
+		if !found {
+			idx--
+		}
+
+		if idx < 0 {
+			return -1
+		}
+
+		return ranges[idx]
+	}
+
+	g.predicates = make([]func(S) bool, len(predicates))
+
+	for predicate := range g.predicates {
+		g.predicates[predicate] = func(symbol S) bool { return rangeOf(symbol) == predicate }
+	}
+
+	return g
+}
+
+// Returns a function that compares symbols of type S or false if S isn't a predeclared integer or string type.
+func symbolOrder[S comparable]() (func(a, b S) int, bool) {
+	var compare any
+
+	switch any(*new(S)).(type) {
+	case int:
+		compare = cmp.Compare[int]
+	case int8:
+		compare = cmp.Compare[int8]
+	case int16:
+		compare = cmp.Compare[int16]
+	case int32:
+		compare = cmp.Compare[int32]
+	case int64:
+		compare = cmp.Compare[int64]
+	case uint:
+		compare = cmp.Compare[uint]
+	case uint8:
+		compare = cmp.Compare[uint8]
+	case uint16:
+		compare = cmp.Compare[uint16]
+	case uint32:
+		compare = cmp.Compare[uint32]
+	case uint64:
+		compare = cmp.Compare[uint64]
+	case string:
+		compare = cmp.Compare[string]
+	default:
+		return nil, false
+	}
+
+	return compare.(func(a, b S) int), true
+}
+
+// Returns the codecs for the symbols and the values of the DFA.
+func (d *Dfa[S, V]) codecs() (Codec[S], Codec[V], error) {
+	symbols, values := d.symbolCodec, d.valueCodec
+
+	if symbols == nil {
+		symbols = defaultCodec[S]()
+	}
+
+	if values == nil {
+		values = defaultCodec[V]()
+	}
+
+	if symbols == nil || values == nil {
+		var (
+			symbol S
+			value  V
+		)
+
+		return nil, nil, fmt.Errorf("dfa: no codec for symbols of type %T or values of type %T", symbol, value)
+	}
+
+	return symbols, values, nil
+}
+
+// A 'binaryReader' decodes the binary form of a [Dfa].
+// Once decoding fails, the error is recorded and all subsequent reads return zero values.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+// Records err (unless an error was recorded before).
+func (reader *binaryReader) fail(err error) {
+	if reader.err == nil {
+		reader.err = err
+	}
+}
+
+// Reads an unsigned varint.
+func (reader *binaryReader) uvarint() uint64 {
+	if reader.err != nil {
+		return 0
+	}
+
+	value, n := binary.Uvarint(reader.data)
+
+	if n <= 0 {
+		reader.fail(errors.New("malformed varint"))
+
+		return 0
+	}
+
+	reader.data = reader.data[n:]
+
+	return value
+}
+
+// Reads a count, which must be at most limit.
+func (reader *binaryReader) count(limit int) int {
+	value := reader.uvarint()
+
+	if value > uint64(limit) {
+		reader.fail(fmt.Errorf("count %d exceeds the size of the data", value))
+
+		return 0
+	}
+
+	return int(value)
+}
+
+// Reads the index of a state, which must be less than count.
+func (reader *binaryReader) index(count int) int {
+	value := reader.uvarint()
+
+	if reader.err == nil && value >= uint64(count) {
+		reader.fail(fmt.Errorf("state %d out of range", value))
+	}
+
+	if reader.err != nil {
+		return 0
+	}
+
+	return int(value)
+}
+
+// Reads the number of kinds of symbols of a list that's indexed by kind, which is either 0 or [nfa.NumSymbolKinds].
+func (reader *binaryReader) kinds() int {
+	value := reader.uvarint()
+
+	if reader.err == nil && value != 0 && value != nfa.NumSymbolKinds {
+		reader.fail(fmt.Errorf("%d kinds of symbols, want %d", value, nfa.NumSymbolKinds))
+	}
+
+	if reader.err != nil {
+		return 0
+	}
+
+	return int(value)
+}
+
+// Reads an acceptance index, which must be at least -1.
+func (reader *binaryReader) acceptIdx() int {
+	if reader.err != nil {
+		return -1
+	}
+
+	value, n := binary.Varint(reader.data)
+
+	if n <= 0 || value < -1 || value != int64(int(value)) {
+		reader.fail(errors.New("malformed acceptance index"))
+
+		return -1
+	}
+
+	reader.data = reader.data[n:]
+
+	return int(value)
+}
+
+// Reads a single value using codec.
+func decode[T any](reader *binaryReader, codec Codec[T]) T {
+	var zero T
+
+	if reader.err != nil {
+		return zero
+	}
+
+	value, n, err := codec.DecodeBinary(reader.data)
+
+	if err == nil && (n <= 0 || n > len(reader.data)) {
+		err = errShortBuffer
+	}
+
+	if err != nil {
+		reader.fail(fmt.Errorf("malformed value: %w", err))
+
+		return zero
+	}
+
+	reader.data = reader.data[n:]
+
+	return value
+}
@@ -0,0 +1,343 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// A type of accepting values without a default codec.
+type label string
+
+// Returns a minimal [dfa.Dfa] recognizing the keywords "if" and "in", words made of 'a', 'b' and 'i' and the empty
+// string.
+func newBinaryMachine() *dfa.Dfa[rune, label] {
+	nMachine := nfa.New[rune, label]()
+
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'i'), 'f'), "IF")
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'i'), 'n'), "IN")
+
+	loop := nMachine.AddEpsilonTransition(nMachine.Start())
+	word := nMachine.NewState()
+
+	for _, r := range "abi" {
+		nMachine.ConnectEpsilon(nMachine.Add(loop, r), word)
+	}
+
+	nMachine.ConnectEpsilon(word, loop)
+	nMachine.AddAcceptingEpsilonTransition(word, "WORD")
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Start(), "EMPTY")
+
+	minimal, _ := dfa.Minimize(dfa.FromNfa(nMachine))
+
+	return minimal
+}
+
+// Calls fn for every input of at most size symbols of alphabet.
+func forEachInput[S any](alphabet []S, size int, fn func([]S)) {
+	input := make([]S, 0, size)
+
+	var walk func()
+
+	walk = func() {
+		fn(input)
+
+		if len(input) == size {
+			return
+		}
+
+		for _, symbol := range alphabet {
+			input = append(input, symbol)
+			walk()
+			input = input[:len(input)-1]
+		}
+	}
+
+	walk()
+}
+
+// UT: Marshal and unmarshal a 'Dfa'.
+func TestDfa_MarshalBinary(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When a DFA is loaded, it accepts exactly what the original DFA accepts.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newBinaryMachine()
+		machine.SetCodecs(nil, dfa.StringCodec[label]{})
+
+		data, err := machine.MarshalBinary()
+
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When marshaling a DFA, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		// Act.
+		loaded, err := dfa.Load(data, dfa.IntCodec[rune]{}, dfa.StringCodec[label]{})
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When loading a marshaled DFA, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		forEachInput([]rune("abfinx"), 4, func(input []rune) {
+			want, wantOk := run(machine, input)
+			got, gotOk := run(loaded, input)
+
+			assert.Truef(t, got == want && gotOk == wantOk, "\n\n"+
+				"UT Name:  When a DFA is loaded, it accepts exactly what the original DFA accepts.\n"+
+				"\033[32mExpected: %q (%t) for %q.\033[0m\n"+
+				"\033[31mActual:   %q (%t).\033[0m\n\n", want, wantOk, string(input), got, gotOk)
+		})
+	})
+
+	t.Run("When a DFA is marshaled twice, the data is identical.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newBinaryMachine()
+		machine.SetCodecs(nil, dfa.StringCodec[label]{})
+
+		// Act.
+		first, _ := machine.MarshalBinary()
+		second, _ := machine.MarshalBinary()
+
+		// Assert.
+		assert.Truef(t, bytes.Equal(first, second), "\n\n"+
+			"UT Name:  When a DFA is marshaled twice, the data is identical.\n"+
+			"\033[32mExpected: %x.\033[0m\n"+
+			"\033[31mActual:   %x.\033[0m\n\n", first, second)
+	})
+
+	t.Run("When there's no codec for the accepting values, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		_, err := newBinaryMachine().MarshalBinary()
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When there's no codec for the accepting values, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+
+	t.Run("When a DFA with class transitions is loaded, it's equivalent to the original DFA.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newRuleMachine(
+			rule{literal: "if", value: 1},
+			rule{class: &runeRange{'a', 'z'}, value: 2},
+			rule{class: &runeRange{'0', '9'}, value: 3},
+		)
+
+		data, err := machine.MarshalBinary()
+
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When marshaling a DFA with class transitions, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		// Act.
+		loaded, err := dfa.Load[rune, int](data, nil, nil)
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When loading a marshaled DFA with class transitions, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		witness, ok := dfa.Equivalent(machine, loaded, equalValues)
+
+		assert.Truef(t, ok, "\n\n"+
+			"UT Name:  When a DFA with class transitions is loaded, it's equivalent to the original DFA.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", string(witness))
+
+		forEachInput([]rune("if09z-"), 3, func(input []rune) {
+			want, wantOk := run(machine, input)
+			got, gotOk := run(loaded, input)
+
+			assert.Truef(t, got == want && gotOk == wantOk, "\n\n"+
+				"UT Name:  When a DFA with class transitions is loaded, it accepts exactly what the original DFA accepts.\n"+
+				"\033[32mExpected: %d (%t) for %q.\033[0m\n"+
+				"\033[31mActual:   %d (%t).\033[0m\n\n", want, wantOk, string(input), got, gotOk)
+		})
+	})
+
+	t.Run("When a DFA with assertions is loaded, it matches exactly what the original DFA matches.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		// Rule 0: "ab" at the start of a line, followed by a word boundary.
+		// Rule 1: "ab" followed by the end of a line.
+		// Rule 2: a lowercase letter, followed by NO word boundary.
+		nMachine := nfa.New[rune, int]()
+
+		lineStart, boundary := nMachine.NewState(), nMachine.NewState()
+		nMachine.AddAssertion(nMachine.Start(), lineStart, nfa.BeginLine)
+		nMachine.AddAssertion(nMachine.Add(nMachine.Add(lineStart, 'a'), 'b'), boundary, nfa.WordBoundary)
+		nMachine.AddAcceptingEpsilonTransition(boundary, 0)
+
+		lineEnd := nMachine.NewState()
+		nMachine.AddAssertion(nMachine.Add(nMachine.Add(nMachine.Start(), 'a'), 'b'), lineEnd, nfa.EndLine)
+		nMachine.AddAcceptingEpsilonTransition(lineEnd, 1)
+
+		letter, inside := nMachine.NewState(), nMachine.NewState()
+		nMachine.AddClassTransition(nMachine.Start(), letter, runeRange{'a', 'z'})
+		nMachine.AddAssertion(letter, inside, nfa.NoWordBoundary)
+		nMachine.AddAcceptingEpsilonTransition(inside, 2)
+
+		machine, _ := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		data, err := machine.MarshalBinary()
+
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When marshaling a DFA with assertions, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		// Act.
+		loaded, err := dfa.Load[rune, int](data, nil, nil)
+
+		// Assert.
+		assert.Truef(t, err == nil && loaded.HasAssertions(), "\n\n"+
+			"UT Name:  When loading a marshaled DFA with assertions, the DFA has assertions.\n"+
+			"\033[32mExpected: <nil>, true.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		for _, input := range allStrings("ab-\n", 4) {
+			for start := range len(input) + 1 {
+				want, wantOk := longestMatch(machine, []rune(input), start)
+				got, gotOk := longestMatch(loaded, []rune(input), start)
+
+				assert.Truef(t, got == want && gotOk == wantOk, "\n\n"+
+					"UT Name:  When a DFA with assertions is loaded, it matches %q at offset %d like the original DFA.\n"+
+					"\033[32mExpected: %v (%t).\033[0m\n"+
+					"\033[31mActual:   %v (%t).\033[0m\n\n", input, start, want, wantOk, got, gotOk)
+			}
+		}
+	})
+
+	t.Run("When a state has opaque predicate transitions, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[rune, int]()
+		digit := nMachine.NewState()
+
+		nMachine.AddPredicateTransition(nMachine.Start(), digit, unicode.IsDigit)
+		nMachine.AddAcceptingEpsilonTransition(digit, 1)
+
+		// Act.
+		_, err := dfa.FromNfa(nMachine).MarshalBinary()
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When a state has opaque predicate transitions, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+
+	t.Run("When a state has trailing context, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[rune, int]()
+		accepting := nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Start(), 'a'), 1)
+
+		nMachine.SetTrailingContext(accepting, &nfa.TrailingContext[rune]{HeadLen: 1, TailLen: -1})
+
+		dMachine := dfa.FromNfa(nMachine)
+		dMachine.SetCodecs(dfa.IntCodec[rune]{}, dfa.IntCodec[int]{})
+
+		// Act.
+		_, err := dMachine.MarshalBinary()
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When a state has trailing context, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+}
+
+// UT: Load an invalid binary form of a 'Dfa'.
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	nMachine := nfa.New[rune, int]()
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'i'), 'f'), 1)
+
+	data, _ := dfa.FromNfa(nMachine).MarshalBinary()
+
+	// Returns a copy of data where the byte at idx is replaced by value and the checksum is updated.
+	patch := func(idx int, value byte) []byte {
+		patched := bytes.Clone(data[:len(data)-crc32.Size])
+		patched[idx] = value
+
+		return binary.BigEndian.AppendUint32(patched, crc32.ChecksumIEEE(patched))
+	}
+
+	flipped := bytes.Clone(data)
+	flipped[len(flipped)/2] ^= 0xFF
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"empty data", nil},
+		{"a wrong magic number", patch(0, 'X')},
+		{"a corrupt byte", flipped},
+		{"truncated data", data[:len(data)-1]},
+		{"an unsupported version", patch(4, 4)},
+		{"a state out of range", patch(6, 0x7F)},
+		{"a wrong number of start states", patch(7, 2)},
+	} {
+		// Act.
+		_, err := dfa.Load[rune, int](tc.data, nil, nil)
+
+		// Assert.
+		assert.Truef(t, errors.Is(err, dfa.ErrInvalidBinary), "\n\n"+
+			"UT Name:  When loading %s, 'ErrInvalidBinary' is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", tc.name, dfa.ErrInvalidBinary, err)
+	}
+}
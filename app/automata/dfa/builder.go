@@ -26,61 +26,199 @@
 package dfa
 
 import (
+	"errors"
+	"iter"
+	"maps"
+	"math/bits"
+	"slices"
+
 	"github.com/kdeconinck/realign/automata/nfa"
 	"github.com/kdeconinck/realign/collections/queue"
+	"github.com/kdeconinck/realign/collections/set"
 )
 
 // A builder for creating a [Dfa] from a [nfa.Nfa] using the "Subset Construction" algorithm.
 type dfaBuilder[S comparable, V any] struct {
 	dfa                 *Dfa[S, V]
-	workingQueue        *queue.Queue[[]*nfa.State[S, V]]
+	workingQueue        *queue.Queue[subset[S, V]]
 	subsetKeyToStateMap map[string]*State[S, V]
+	closures            *closureCache[S, V]
+	special             []S // The symbols that aren't of kind [nfa.KindOther] if the Nfa has assertions (nil otherwise).
+	kindBoundaries      []S // The boundaries of the symbols of kind [nfa.KindOther] (if special isn't nil).
+
+	symbolClasses map[S]int // The class of every symbol with a concrete transition (see [nfa.SymbolClasses]).
+}
+
+// A 'subset' is a subset of [nfa.State]s that's waiting to be expanded, together with the kind of the symbol before it.
+type subset[S comparable, V any] struct {
+	states []*nfa.State[S, V]
+	prev   nfa.SymbolKind
+	key    string
 }
 
-// Returns a [Dfa] that's equivalent to machine.
-func (builder *dfaBuilder[S, V]) buildFromNfa(machine *nfa.Nfa[S, V]) *Dfa[S, V] {
-	startStates := findPossibleStates(machine.Start())
+// Returns a [Dfa] that's equivalent to machine or an error if the guard of any of its states can't be built.
+func (builder *dfaBuilder[S, V]) buildFromNfa(machine *nfa.Nfa[S, V]) (*Dfa[S, V], error) {
+	startStates := builder.closures.findPossibleStates(machine.Start())
 
 	builder.dfa.start = builder.buildStartState(startStates)
 
+	if machine.HasAssertions() {
+		builder.special, builder.kindBoundaries = specialSymbols[S]()
+		builder.dfa.starts = []*State[S, V]{builder.dfa.start}
+
+		for prev := nfa.KindNone + 1; prev < nfa.NumSymbolKinds; prev++ {
+			builder.dfa.starts = append(builder.dfa.starts, builder.ensureState(startStates, prev))
+		}
+	}
+
 	for builder.workingQueue.Len() > 0 {
-		currentSubset, _ := builder.workingQueue.Dequeue()
+		current, _ := builder.workingQueue.Dequeue()
+
+		from := builder.subsetKeyToStateMap[current.key]
+		states := builder.resolve(current.states, current.prev, nfa.KindOther)
+		classes := findGuardClasses(states)
+
+		nextSubsets := expandStatesPerSymbol(builder.closures, states, classes, builder.symbolClasses)
+		symbols := slices.Collect(maps.Keys(nextSubsets))
+
+		sortSymbols(symbols)
+
+		for _, sym := range symbols {
+			if builder.special != nil && nfa.KindOf(sym) != nfa.KindOther {
+				continue // NOTE: These are expanded by expandSpecialSymbols.
+			}
+
+			from.transitions[sym] = builder.ensureState(nextSubsets[sym], nfa.KindOther)
+		}
 
-		sKey := calculateStatesKey(currentSubset)
-		from := builder.subsetKeyToStateMap[sKey]
+		if len(classes) > 0 {
+			g, err := builder.buildGuard(from, classes)
 
-		for sym, nextSubset := range expandStatesPerSymbol(currentSubset) {
-			to := builder.ensureState(nextSubset)
-			from.transitions[sym] = to
+			if err != nil {
+				return nil, err
+			}
+
+			from.guard = g
 		}
+
+		builder.expandSpecialSymbols(from, current)
 	}
 
-	return builder.dfa
+	return builder.dfa, nil
+}
+
+// Returns a guard of from that dispatches symbols on the minterms of classes.
+//
+// Every minterm that can hold leads to the [State] for the subset reachable through the classes that hold. When all the
+// predicates are built from an [nfa.Class], the minterms that can hold are found by evaluating the classes on their
+// boundaries. Otherwise, they're found by evaluating the classes on every symbol without a concrete transition in from
+// if the symbols can be enumerated (see [enumerateSymbols]), or every non-empty minterm is assumed to be possible.
+// An error is returned if there are more than maxGuardPredicates classes when all the non-empty minterms are assumed to
+// be possible, or more than maxExactGuardPredicates classes otherwise.
+func (builder *dfaBuilder[S, V]) buildGuard(from *State[S, V], classes []*guardClass[S, V]) (*guard[S, V], error) {
+	boundaries, exact := guardBoundaries(classes)
+	symbols, enumerable := enumerateSymbols[S]()
+
+	if len(classes) > maxExactGuardPredicates || (!exact && !enumerable && len(classes) > maxGuardPredicates) {
+		return nil, errors.New("dfa: too many predicate transitions leaving a single state")
+	}
+
+	g := &guard[S, V]{
+		predicates: make([]func(S) bool, len(classes)),
+		targets:    make(map[uint64]*State[S, V]),
+		boundaries: boundaries,
+		labels:     make([]string, len(classes)),
+	}
+
+	for idx, class := range classes {
+		g.predicates[idx] = class.matches
+		g.labels[idx] = class.label()
+	}
+
+	// NOTE: The symbols that aren't of kind [nfa.KindOther] always have a concrete transition when the Nfa has
+	// assertions, so they're excluded from the guard. Adding the boundaries of those symbols keeps the guard exact.
+	if builder.special != nil {
+		for idx, class := range classes {
+			g.predicates[idx] = func(symbol S) bool {
+				return nfa.KindOf(symbol) == nfa.KindOther && class.matches(symbol)
+			}
+		}
+
+		boundaries, exact = guardBoundaries(classes, builder.kindBoundaries...)
+		g.boundaries = boundaries
+	}
+
+	var masks []uint64
+
+	switch {
+	case exact:
+		masks = findMinterms(g, slices.Values(boundaries))
+	case enumerable && len(classes) > 1:
+		masks = findMinterms(g, func(yield func(S) bool) {
+			for symbol := range symbols {
+				if _, ok := from.transitions[symbol]; !ok && !yield(symbol) {
+					return
+				}
+			}
+		})
+	default:
+		for mask := uint64(1); mask < 1<<len(classes); mask++ {
+			masks = append(masks, mask)
+		}
+	}
+
+	for _, mask := range masks {
+		endStates := make([]*nfa.State[S, V], 0, bits.OnesCount64(mask))
+
+		for idx, class := range classes {
+			if mask&(1<<idx) != 0 {
+				endStates = append(endStates, class.endState)
+			}
+		}
+
+		g.targets[mask] = builder.ensureState(builder.closures.findPossibleStates(endStates...), nfa.KindOther)
+	}
+
+	return g, nil
+}
+
+// Returns the non-empty minterms of g for symbols, in ascending order.
+func findMinterms[S comparable, V any](g *guard[S, V], symbols iter.Seq[S]) []uint64 {
+	seen := set.New[uint64]()
+
+	var masks []uint64
+
+	for symbol := range symbols {
+		if mask := g.minterm(symbol); mask != 0 && !seen.Has(mask) {
+			seen.Add(mask)
+			masks = append(masks, mask)
+		}
+	}
+
+	slices.Sort(masks)
+
+	return masks
 }
 
 // Build a [State] from states.
 // The states parameter is added to the builder's working queue for further expansion.
 func (builder *dfaBuilder[S, V]) buildStartState(states []*nfa.State[S, V]) *State[S, V] {
-	acceptingIdx, acceptingValue := findAcceptanceIdx(states)
-
 	sState := &State[S, V]{
 		id:          0, // start is always 0
 		transitions: make(map[S]*State[S, V]),
-		acceptIdx:   acceptingIdx,
-		value:       acceptingValue,
+		acceptIdx:   -1,
 	}
 
-	sKey := calculateStatesKey(states)
-
-	builder.subsetKeyToStateMap[sKey] = sState
-	builder.workingQueue.Enqueue(states)
+	builder.dfa.states = append(builder.dfa.states, sState)
+	builder.register(sState, states, nfa.KindNone, builder.subsetKey(states, nfa.KindNone))
 
 	return sState
 }
 
-// Returns the acceptance index (and value) in states with the lowest value.
-func findAcceptanceIdx[S comparable, V any](states []*nfa.State[S, V]) (int, V) {
+// Returns the acceptance index (and value and trailing context) in states with the lowest value.
+func findAcceptanceIdx[S comparable, V any](states []*nfa.State[S, V]) (int, V, *nfa.TrailingContext[S]) {
 	var valueV V
+	var trailing *nfa.TrailingContext[S]
 	bestIdx := -1
 
 	for _, s := range states {
@@ -93,33 +231,38 @@ func findAcceptanceIdx[S comparable, V any](states []*nfa.State[S, V]) (int, V)
 		if bestIdx == -1 || idx < bestIdx {
 			bestIdx = idx
 			valueV = s.AcceptValue()
+			trailing = s.TrailingContext()
 		}
 	}
 
-	return bestIdx, valueV
+	return bestIdx, valueV, trailing
 }
 
 // Adds states to the [Dfa] that's being constructed by the builder if it hasn't seen by the builder yet.
-func (builder *dfaBuilder[S, V]) ensureState(states []*nfa.State[S, V]) *State[S, V] {
-	sKey := calculateStatesKey(states)
+// The kind of the symbol before states is prev, which is only taken into account if states have assertions.
+func (builder *dfaBuilder[S, V]) ensureState(states []*nfa.State[S, V], prev nfa.SymbolKind) *State[S, V] {
+	sKey := builder.subsetKey(states, prev)
 
 	if state, ok := builder.subsetKeyToStateMap[sKey]; ok {
 		return state
 	}
 
-	acceptingIdx, acceptingValue := findAcceptanceIdx(states)
+	state := builder.dfa.newState()
+	builder.register(state, states, prev, sKey)
 
-	if acceptingIdx > -1 {
-		state := builder.dfa.newAcceptingState(acceptingIdx, acceptingValue)
-		builder.subsetKeyToStateMap[sKey] = state
-		builder.workingQueue.Enqueue(states)
+	return state
+}
 
-		return state
+// Records that state is built from states (with key sKey), sets its acceptance and queues states for expansion.
+func (builder *dfaBuilder[S, V]) register(
+	state *State[S, V], states []*nfa.State[S, V], prev nfa.SymbolKind, sKey string,
+) {
+	if !hasAssertions(states) {
+		state.acceptIdx, state.value, state.trailing = findAcceptanceIdx(states)
+	} else {
+		builder.setConditions(state, states, prev)
 	}
 
-	state := builder.dfa.newState()
 	builder.subsetKeyToStateMap[sKey] = state
-	builder.workingQueue.Enqueue(states)
-
-	return state
+	builder.workingQueue.Enqueue(subset[S, V]{states: states, prev: prev, key: sKey})
 }
@@ -0,0 +1,106 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/collections/queue"
+	"github.com/kdeconinck/realign/collections/set"
+)
+
+// A 'closureCache' computes epsilon closures of [nfa.State]s.
+//
+// Reasoning:
+// During the "Subset Construction" algorithm, the epsilon closure of the same [nfa.State] is requested over and over
+// again (once for every subset it ends up in). Since the closure of a single [nfa.State] never changes, it's computed
+// once and reused for the whole conversion. The closure of a set of [nfa.State]s is the union of the closures of its
+// members.
+type closureCache[S comparable, V any] struct {
+	closures map[int][]*nfa.State[S, V]
+}
+
+func newClosureCache[S comparable, V any]() *closureCache[S, V] {
+	return &closureCache[S, V]{
+		closures: make(map[int][]*nfa.State[S, V]),
+	}
+}
+
+// Returns all the [nfa.State]s, reachable from states, by following zero or more epsilon transitions.
+// Every [nfa.State] is returned exactly once. The returned slice may be shared, so callers should NOT modify it.
+func (cache *closureCache[S, V]) findPossibleStates(states ...*nfa.State[S, V]) []*nfa.State[S, V] {
+	if len(states) == 1 {
+		return cache.closureOf(states[0])
+	}
+
+	seen := set.New[int]()
+	reachableStates := make([]*nfa.State[S, V], 0, len(states))
+
+	for _, state := range states {
+		for _, reachable := range cache.closureOf(state) {
+			if seen.Has(reachable.ID()) {
+				continue
+			}
+
+			seen.Add(reachable.ID())
+			reachableStates = append(reachableStates, reachable)
+		}
+	}
+
+	return reachableStates
+}
+
+// Returns the epsilon closure of state (including state itself).
+// Epsilon cycles are safe since every [nfa.State] is visited at most once.
+func (cache *closureCache[S, V]) closureOf(state *nfa.State[S, V]) []*nfa.State[S, V] {
+	if closure, ok := cache.closures[state.ID()]; ok {
+		return closure
+	}
+
+	workingQueue := queue.New[*nfa.State[S, V]]()
+	seen := set.New[int]()
+	closure := []*nfa.State[S, V]{state}
+
+	workingQueue.Enqueue(state)
+	seen.Add(state.ID())
+
+	for workingQueue.Len() > 0 {
+		queuedState, _ := workingQueue.Dequeue()
+
+		for _, nState := range queuedState.Epsilon() {
+			if seen.Has(nState.ID()) {
+				continue
+			}
+
+			seen.Add(nState.ID())
+			workingQueue.Enqueue(nState)
+			closure = append(closure, nState)
+		}
+	}
+
+	cache.closures[state.ID()] = closure
+
+	return closure
+}
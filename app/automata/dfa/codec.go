@@ -0,0 +1,151 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Codec encodes and decodes values of type T to and from their binary form.
+// It's used to serialize the symbols and the accepting values of a [Dfa] (see [Dfa.MarshalBinary]).
+type Codec[T any] interface {
+	// AppendBinary appends the binary form of value to buf and returns the extended buffer.
+	AppendBinary(buf []byte, value T) ([]byte, error)
+
+	// DecodeBinary decodes a single value from the start of data.
+	// It returns the value and the number of bytes that were consumed.
+	DecodeBinary(data []byte) (T, int, error)
+}
+
+// errShortBuffer is returned by the codecs in this package when data ends in the middle of a value.
+var errShortBuffer = errors.New("unexpected end of data")
+
+// IntCodec is a [Codec] for signed integers, which are encoded as zig-zag varints.
+type IntCodec[T ~int | ~int8 | ~int16 | ~int32 | ~int64] struct{}
+
+// AppendBinary appends the binary form of value to buf.
+func (IntCodec[T]) AppendBinary(buf []byte, value T) ([]byte, error) {
+	return binary.AppendVarint(buf, int64(value)), nil
+}
+
+// DecodeBinary decodes a single integer from the start of data.
+func (IntCodec[T]) DecodeBinary(data []byte) (T, int, error) {
+	value, n := binary.Varint(data)
+
+	if n <= 0 {
+		return 0, 0, errShortBuffer
+	}
+
+	if int64(T(value)) != value {
+		return 0, 0, fmt.Errorf("value %d overflows %T", value, T(0))
+	}
+
+	return T(value), n, nil
+}
+
+// UintCodec is a [Codec] for unsigned integers, which are encoded as varints.
+type UintCodec[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64] struct{}
+
+// AppendBinary appends the binary form of value to buf.
+func (UintCodec[T]) AppendBinary(buf []byte, value T) ([]byte, error) {
+	return binary.AppendUvarint(buf, uint64(value)), nil
+}
+
+// DecodeBinary decodes a single integer from the start of data.
+func (UintCodec[T]) DecodeBinary(data []byte) (T, int, error) {
+	value, n := binary.Uvarint(data)
+
+	if n <= 0 {
+		return 0, 0, errShortBuffer
+	}
+
+	if uint64(T(value)) != value {
+		return 0, 0, fmt.Errorf("value %d overflows %T", value, T(0))
+	}
+
+	return T(value), n, nil
+}
+
+// StringCodec is a [Codec] for strings, which are encoded as their length (a varint) followed by their bytes.
+type StringCodec[T ~string] struct{}
+
+// AppendBinary appends the binary form of value to buf.
+func (StringCodec[T]) AppendBinary(buf []byte, value T) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+
+	return append(buf, value...), nil
+}
+
+// DecodeBinary decodes a single string from the start of data.
+func (StringCodec[T]) DecodeBinary(data []byte) (T, int, error) {
+	size, n := binary.Uvarint(data)
+
+	if n <= 0 || size > uint64(len(data)-n) {
+		return "", 0, errShortBuffer
+	}
+
+	return T(data[n : n+int(size)]), n + int(size), nil
+}
+
+// Returns the [Codec] for values of type T that's used when no codec is set explicitly or nil if there's no such codec.
+// Codecs are available for the predeclared integer and string types.
+func defaultCodec[T any]() Codec[T] {
+	var (
+		zero  T
+		codec any
+	)
+
+	switch any(zero).(type) {
+	case int:
+		codec = IntCodec[int]{}
+	case int8:
+		codec = IntCodec[int8]{}
+	case int16:
+		codec = IntCodec[int16]{}
+	case int32:
+		codec = IntCodec[int32]{}
+	case int64:
+		codec = IntCodec[int64]{}
+	case uint:
+		codec = UintCodec[uint]{}
+	case uint8:
+		codec = UintCodec[uint8]{}
+	case uint16:
+		codec = UintCodec[uint16]{}
+	case uint32:
+		codec = UintCodec[uint32]{}
+	case uint64:
+		codec = UintCodec[uint64]{}
+	case string:
+		codec = StringCodec[string]{}
+	default:
+		return nil
+	}
+
+	return codec.(Codec[T])
+}
@@ -0,0 +1,291 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"math"
+	"slices"
+
+	"github.com/kdeconinck/realign/collections/queue"
+	"github.com/kdeconinck/realign/collections/set"
+)
+
+// IsEmpty returns true if d doesn't accept any input. Otherwise, it returns a shortest input that d accepts.
+//
+// Panics if d has assertions (see [Dfa.HasAssertions]) or if the inputs of a guard of d can't be enumerated (see
+// [Equivalent]).
+func IsEmpty[S comparable, V any](d *Dfa[S, V]) ([]S, bool) {
+	return findShortest(d, nil, nil, func(a, _ *State[S, V]) bool {
+		return a != nil && a.IsAccepting()
+	})
+}
+
+// IsUniversal returns true if d accepts every sequence of symbols of alphabet (including the empty one). Otherwise, it
+// returns a shortest such sequence that d doesn't accept.
+//
+// Panics if d has assertions (see [Dfa.HasAssertions]).
+func IsUniversal[S comparable, V any](d *Dfa[S, V], alphabet []S) ([]S, bool) {
+	return findShortest(d, nil, alphabet, func(a, _ *State[S, V]) bool {
+		return a == nil || !a.IsAccepting()
+	})
+}
+
+// Includes returns true if a accepts every input that b accepts. Otherwise, it returns a shortest input that b accepts,
+// but a doesn't.
+//
+// When equal isn't nil, an input that both machines accept, but with accepting values that aren't equal, isn't
+// included either. That way, inputs for which a refactored set of rules produces a different token are reported.
+//
+// Panics if either machine has assertions (see [Dfa.HasAssertions]) or if the inputs of a guard can't be enumerated
+// (see [Equivalent]).
+func Includes[S comparable, V any](a, b *Dfa[S, V], equal func(x, y V) bool) ([]S, bool) {
+	return findShortest(a, b, nil, func(p, q *State[S, V]) bool {
+		return q != nil && q.IsAccepting() && !acceptsLike(p, q, equal)
+	})
+}
+
+// Equivalent returns true if a and b accept the same inputs. Otherwise, it returns a shortest input that only one of
+// them accepts.
+//
+// When equal isn't nil, the machines must also agree on the accepting value of every input they accept (see
+// [Includes]). The acceptance indexes are never compared.
+//
+// The machines are compared with the algorithm of Hopcroft and Karp: states that must be equivalent are merged with a
+// union-find structure, so every state is visited once. Only when the machines differ, a shortest counterexample is
+// searched in their product.
+//
+// Guards are explored through the boundaries of their classes (see [State.GuardBoundaries]). When a boundary has a
+// concrete transition, the next symbol that hasn't is explored instead, which is only possible for integer symbols.
+// Panics if either machine has assertions (see [Dfa.HasAssertions]) or if the inputs of a guard can't be enumerated.
+func Equivalent[S comparable, V any](a, b *Dfa[S, V], equal func(x, y V) bool) ([]S, bool) {
+	differs := func(p, q *State[S, V]) bool {
+		return !acceptsLike(p, q, equal) || !acceptsLike(q, p, equal)
+	}
+
+	if hopcroftKarp(a, b, differs) {
+		return nil, true
+	}
+
+	return findShortest(a, b, nil, differs)
+}
+
+// Returns true if a accepts with a value that's equal to the one of b, given that b accepts (or if b doesn't accept).
+func acceptsLike[S comparable, V any](a, b *State[S, V], equal func(x, y V) bool) bool {
+	if b == nil || !b.IsAccepting() {
+		return true
+	}
+
+	if a == nil || !a.IsAccepting() {
+		return false
+	}
+
+	return equal == nil || equal(a.value, b.value)
+}
+
+// Returns true if no pair of states of a and b that's reached by the same input differs.
+func hopcroftKarp[S comparable, V any](a, b *Dfa[S, V], differs func(p, q *State[S, V]) bool) bool {
+	checkDecidable(a, b)
+
+	// NOTE: A missing state (nil) never accepts, so it's shared by both machines.
+	parents := make(map[*State[S, V]]*State[S, V])
+
+	find := func(state *State[S, V]) *State[S, V] {
+		for {
+			parent, ok := parents[state]
+
+			if !ok || parent == state {
+				return state
+			}
+
+			// NOTE: Path halving keeps the trees flat.
+			if grandparent, ok := parents[parent]; ok {
+				parents[state] = grandparent
+			}
+
+			state = parent
+		}
+	}
+
+	workingQueue := queue.New[statePair[S, V]]()
+	parents[a.start] = b.start
+	workingQueue.Enqueue(statePair[S, V]{a: a.start, b: b.start})
+
+	for workingQueue.Len() > 0 {
+		pair, _ := workingQueue.Dequeue()
+
+		if differs(pair.a, pair.b) {
+			return false
+		}
+
+		for _, symbol := range decisionSymbols(pair, nil) {
+			target := statePair[S, V]{a: outgoingFor(pair.a, symbol), b: outgoingFor(pair.b, symbol)}
+			rootA, rootB := find(target.a), find(target.b)
+
+			if rootA != rootB {
+				parents[rootA] = rootB
+				workingQueue.Enqueue(target)
+			}
+		}
+	}
+
+	return true
+}
+
+// Returns a shortest input that leads a and b (which may be nil) to a pair of states that's bad, or true if there's no
+// such input. When alphabet is nil, the symbols are taken from the transitions of the states.
+func findShortest[S comparable, V any](
+	a, b *Dfa[S, V], alphabet []S, bad func(p, q *State[S, V]) bool,
+) ([]S, bool) {
+	checkDecidable(a, b)
+
+	// A 'step' records how a pair of states was first reached.
+	type step struct {
+		from   statePair[S, V]
+		symbol S
+	}
+
+	start := statePair[S, V]{a: a.start}
+
+	if b != nil {
+		start.b = b.start
+	}
+
+	steps := map[statePair[S, V]]step{start: {}}
+	workingQueue := queue.New[statePair[S, V]]()
+	workingQueue.Enqueue(start)
+
+	for workingQueue.Len() > 0 {
+		pair, _ := workingQueue.Dequeue()
+
+		if bad(pair.a, pair.b) {
+			input := []S{}
+
+			for ; pair != start; pair = steps[pair].from {
+				input = append(input, steps[pair].symbol)
+			}
+
+			slices.Reverse(input)
+
+			return input, false
+		}
+
+		// NOTE: A pair without states can't lead to a pair that's bad anymore.
+		if pair.a == nil && pair.b == nil {
+			continue
+		}
+
+		for _, symbol := range decisionSymbols(pair, alphabet) {
+			target := statePair[S, V]{a: outgoingFor(pair.a, symbol), b: outgoingFor(pair.b, symbol)}
+
+			if _, ok := steps[target]; !ok {
+				steps[target] = step{from: pair, symbol: symbol}
+				workingQueue.Enqueue(target)
+			}
+		}
+	}
+
+	return nil, true
+}
+
+// Panics if a or b (which may be nil) has assertions.
+func checkDecidable[S comparable, V any](a, b *Dfa[S, V]) {
+	if a.HasAssertions() || (b != nil && b.HasAssertions()) {
+		panic("decide: machines with assertions aren't supported")
+	}
+}
+
+// Returns the symbols to explore from pair: alphabet if it isn't nil. Otherwise, the symbols with a concrete transition
+// in either state, followed by a symbol for every interval of the boundaries of the guards of both states.
+// Panics if a guard is opaque or if its boundary has a concrete transition and the next symbol can't be computed.
+func decisionSymbols[S comparable, V any](pair statePair[S, V], alphabet []S) []S {
+	if alphabet != nil {
+		return alphabet
+	}
+
+	symbols := pairSymbols(pair)
+	concrete, seen := set.New[S](), set.New[S]()
+
+	for _, symbol := range symbols {
+		concrete.Add(symbol)
+		seen.Add(symbol)
+	}
+
+	for _, g := range []*guard[S, V]{guardOf(pair.a), guardOf(pair.b)} {
+		if g == nil {
+			continue
+		}
+
+		if g.boundaries == nil {
+			panic("decide: machines with opaque guards aren't supported")
+		}
+
+		for _, boundary := range g.boundaries {
+			// NOTE: Every symbol of the interval that starts at boundary leads to the same target, unless it has a
+			// concrete transition, so the first symbol without one is explored.
+			for concrete.Has(boundary) {
+				next, ok := nextSymbol(boundary)
+
+				if !ok {
+					panic("decide: the symbols of a guard can't be enumerated")
+				}
+
+				boundary = next
+			}
+
+			if !seen.Has(boundary) {
+				seen.Add(boundary)
+				symbols = append(symbols, boundary)
+			}
+		}
+	}
+
+	return symbols
+}
+
+// Returns the symbol that follows symbol or false if symbol isn't an integer or if it's the largest one of its type.
+func nextSymbol[S comparable](symbol S) (S, bool) {
+	var next any
+	var ok bool
+
+	switch s := any(symbol).(type) {
+	case int:
+		next, ok = s+1, s < math.MaxInt
+	case int32:
+		next, ok = s+1, s < math.MaxInt32
+	case int64:
+		next, ok = s+1, s < math.MaxInt64
+	case uint8:
+		next, ok = s+1, s < math.MaxUint8
+	case uint16:
+		next, ok = s+1, s < math.MaxUint16
+	case uint32:
+		next, ok = s+1, s < math.MaxUint32
+	default:
+		return symbol, false
+	}
+
+	return next.(S), ok
+}
@@ -0,0 +1,241 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// A 'runeRange' is the class of the runes from lo up to (and including) hi.
+type runeRange struct{ lo, hi rune }
+
+func (r runeRange) Contains(symbol rune) bool { return r.lo <= symbol && symbol <= r.hi }
+func (r runeRange) Boundaries() []rune        { return []rune{math.MinInt32, r.lo, r.hi + 1} }
+func (r runeRange) String() string            { return fmt.Sprintf("%q-%q", r.lo, r.hi) }
+
+// A 'rule' is a rule of a machine built by newRuleMachine: either a sequence of runes or a class that repeats.
+type rule struct {
+	literal string
+	class   *runeRange
+	value   int
+}
+
+// Returns a 'Dfa' that accepts the literals and the (non-empty) repetitions of the classes of rules.
+func newRuleMachine(rules ...rule) *dfa.Dfa[rune, int] {
+	machine := nfa.New[rune, int]()
+
+	for _, r := range rules {
+		state := machine.Start()
+
+		if r.class != nil {
+			loop := machine.NewState()
+			machine.AddClassTransition(state, loop, *r.class)
+			machine.AddClassTransition(loop, loop, *r.class)
+			state = loop
+		}
+
+		for _, symbol := range r.literal {
+			state = machine.Add(state, symbol)
+		}
+
+		machine.AddAcceptingEpsilonTransition(state, r.value)
+	}
+
+	return dfa.FromNfa(machine)
+}
+
+// Returns true if both values are equal.
+func equalValues(x, y int) bool { return x == y }
+
+// UT: Decide whether the language of a 'Dfa' is empty.
+func TestIsEmpty(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name    string
+		machine *dfa.Dfa[rune, int]
+		want    bool
+		witness string
+	}{
+		{"the machine accepts input", newRuleMachine(rule{literal: "abc"}, rule{literal: "ba"}), false, "ba"},
+		{"the machine accepts the empty input", newRuleMachine(rule{}), false, ""},
+		{"the machine doesn't accept anything", dfa.FromNfa(nfa.New[rune, int]()), true, ""},
+	} {
+		// Act.
+		witness, got := dfa.IsEmpty(tc.machine)
+
+		// Assert.
+		assert.Truef(t, got == tc.want && string(witness) == tc.witness, "\n\n"+
+			"UT Name:  When %s, the language is (NOT) empty and the shortest input is returned.\n"+
+			"\033[32mExpected: %t (%q).\033[0m\n"+
+			"\033[31mActual:   %t (%q).\033[0m\n\n", tc.name, tc.want, tc.witness, got, string(witness))
+	}
+}
+
+// UT: Decide whether a 'Dfa' accepts every input over an alphabet.
+func TestIsUniversal(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	alphabet := []rune("ab")
+
+	for _, tc := range []struct {
+		name    string
+		machine *dfa.Dfa[rune, int]
+		want    bool
+		witness string
+	}{
+		{"the machine accepts everything", newRuleMachine(rule{}, rule{class: &runeRange{'a', 'b'}}), true, ""},
+		{"the machine accepts all but the empty input", newRuleMachine(rule{class: &runeRange{'a', 'b'}}), false, ""},
+		{"a symbol isn't accepted", newRuleMachine(rule{}, rule{class: &runeRange{'a', 'a'}}), false, "b"},
+	} {
+		// Act.
+		witness, got := dfa.IsUniversal(tc.machine, alphabet)
+
+		// Assert.
+		assert.Truef(t, got == tc.want && string(witness) == tc.witness, "\n\n"+
+			"UT Name:  When %s, the language is (NOT) universal and the shortest rejected input is returned.\n"+
+			"\033[32mExpected: %t (%q).\033[0m\n"+
+			"\033[31mActual:   %t (%q).\033[0m\n\n", tc.name, tc.want, tc.witness, got, string(witness))
+	}
+}
+
+// UT: Decide whether the language of a 'Dfa' includes the language of another one.
+func TestIncludes(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	identifiers := newRuleMachine(rule{literal: "if", value: 1}, rule{class: &runeRange{'a', 'z'}, value: 2})
+	keyword := newRuleMachine(rule{literal: "if", value: 2})
+	digits := newRuleMachine(rule{literal: "if", value: 1}, rule{literal: "i9", value: 2})
+
+	for _, tc := range []struct {
+		name    string
+		a, b    *dfa.Dfa[rune, int]
+		equal   func(x, y int) bool
+		want    bool
+		witness string
+	}{
+		{"every input of b is accepted by a", identifiers, keyword, nil, true, ""},
+		{"an input of b is accepted with another value", identifiers, keyword, equalValues, false, "if"},
+		{"an input of b isn't accepted by a", identifiers, digits, nil, false, "i9"},
+		{"a accepts more than b", keyword, identifiers, nil, false, "i"},
+	} {
+		// Act.
+		witness, got := dfa.Includes(tc.a, tc.b, tc.equal)
+
+		// Assert.
+		assert.Truef(t, got == tc.want && string(witness) == tc.witness, "\n\n"+
+			"UT Name:  When %s, b is (NOT) included and the shortest counterexample is returned.\n"+
+			"\033[32mExpected: %t (%q).\033[0m\n"+
+			"\033[31mActual:   %t (%q).\033[0m\n\n", tc.name, tc.want, tc.witness, got, string(witness))
+	}
+}
+
+// UT: Decide whether two 'Dfa's are equivalent.
+func TestEquivalent(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	lower := runeRange{'a', 'z'}
+	upper := runeRange{'A', 'Z'}
+
+	for _, tc := range []struct {
+		name    string
+		a, b    *dfa.Dfa[rune, int]
+		equal   func(x, y int) bool
+		want    bool
+		witness string
+	}{
+		{
+			"the rules are refactored without changing the language",
+			newRuleMachine(rule{literal: "if"}, rule{class: &lower}),
+			newRuleMachine(rule{class: &lower}),
+			equalValues, true, "",
+		},
+		{
+			"the same language is accepted with another value",
+			newRuleMachine(rule{literal: "if", value: 1}, rule{class: &lower, value: 2}),
+			newRuleMachine(rule{class: &lower, value: 2}),
+			equalValues, false, "if",
+		},
+		{
+			"the values are ignored",
+			newRuleMachine(rule{literal: "if", value: 1}, rule{class: &lower, value: 2}),
+			newRuleMachine(rule{class: &lower, value: 2}),
+			nil, true, "",
+		},
+		{
+			"a class is narrowed",
+			newRuleMachine(rule{class: &lower}, rule{class: &upper}),
+			newRuleMachine(rule{class: &runeRange{'a', 'y'}}, rule{class: &upper}),
+			nil, false, "z",
+		},
+		{
+			"a symbol of a class has a concrete transition",
+			newRuleMachine(rule{literal: "a"}, rule{class: &runeRange{'a', 'b'}}),
+			newRuleMachine(rule{literal: "a"}),
+			nil, false, "b",
+		},
+		{
+			"a longer input differs",
+			newRuleMachine(rule{literal: "abc"}, rule{literal: "abd"}),
+			newRuleMachine(rule{literal: "abc"}),
+			nil, false, "abd",
+		},
+	} {
+		// Act.
+		witness, got := dfa.Equivalent(tc.a, tc.b, tc.equal)
+
+		// Assert.
+		assert.Truef(t, got == tc.want && string(witness) == tc.witness, "\n\n"+
+			"UT Name:  When %s, the machines are (NOT) equivalent and the shortest counterexample is returned.\n"+
+			"\033[32mExpected: %t (%q).\033[0m\n"+
+			"\033[31mActual:   %t (%q).\033[0m\n\n", tc.name, tc.want, tc.witness, got, string(witness))
+	}
+
+	t.Run("When a machine has assertions, a panic is raised.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[rune, int]()
+		nMachine.AddAssertion(nMachine.Start(), nMachine.AddAcceptingEpsilonTransition(nMachine.NewState(), 1), nfa.EndText)
+
+		dMachine := dfa.FromNfa(nMachine)
+
+		// Assert.
+		assert.Panicf(t, func() { dfa.Equivalent(dMachine, dMachine, nil) }, "\n\n"+
+			"UT Name:  When a machine has assertions, a panic is raised.\n"+
+			"\033[32mExpected: panic.\033[0m\n"+
+			"\033[31mActual:   NO panic.\033[0m\n\n")
+	})
+}
@@ -26,6 +26,8 @@
 package dfa
 
 import (
+	"slices"
+
 	"github.com/kdeconinck/realign/automata/nfa"
 	"github.com/kdeconinck/realign/collections/queue"
 )
@@ -33,20 +35,55 @@ import (
 // Dfa represents a deterministic finite automaton for symbols of type S with acceptance metadata of type V.
 type Dfa[S comparable, V any] struct {
 	start       *State[S, V]
+	starts      []*State[S, V] // The start state per kind of the previous symbol (nil without assertions).
+	states      []*State[S, V] // All the states, indexed by their ID.
 	nextStateID int
+	symbolCodec Codec[S] // The codec for the symbols when serializing (nil selects the default codec).
+	valueCodec  Codec[V] // The codec for the accepting values when serializing (nil selects the default codec).
 }
 
 // Start returns the start state of the DFA.
 func (d *Dfa[S, V]) Start() *State[S, V] { return d.start }
 
+// States returns all the states of the DFA, indexed by their ID.
+func (d *Dfa[S, V]) States() []*State[S, V] { return slices.Clone(d.states) }
+
 // FromNfa converts and returns n into an equivalent [Dfa] using the "Subset Construction" algorithm.
+//
+// The symbols with a concrete transition are grouped into the classes of [nfa.SymbolClasses] first, so the states that
+// a subset reaches are computed once per class, instead of once per symbol.
+//
+// Panics if the predicate transitions leaving a single subset of states of n can't be determinized (see [TryFromNfa]).
 func FromNfa[S comparable, V any](n *nfa.Nfa[S, V]) *Dfa[S, V] {
+	d, err := TryFromNfa(n)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// TryFromNfa is like [FromNfa], but returns an error instead of panicking when the predicate transitions leaving a
+// single subset of states of n can't be determinized.
+//
+// Every combination of predicates that hold for the same symbol requires its own transition. When some predicates
+// aren't built from an [nfa.Class] and the symbols can't be enumerated (which is only possible for small integer types
+// and runes), every combination is assumed to be possible, so at most 16 predicate transitions can leave a single
+// subset. Otherwise, at most 64 can.
+//
+// NOTE: Since rune is an alias for int32, int32 symbols are always enumerated as runes: only the valid code points
+// (from 0 up to and including [unicode.MaxRune]) are evaluated. A combination of opaque predicates that only holds for
+// a negative symbol or a symbol above [unicode.MaxRune] therefore doesn't get a transition.
+func TryFromNfa[S comparable, V any](n *nfa.Nfa[S, V]) (*Dfa[S, V], error) {
 	dfaBuilder := &dfaBuilder[S, V]{
 		dfa: &Dfa[S, V]{
 			nextStateID: 1,
 		},
-		workingQueue:        queue.New[[]*nfa.State[S, V]](),
+		workingQueue:        queue.New[subset[S, V]](),
 		subsetKeyToStateMap: make(map[string]*State[S, V]),
+		closures:            newClosureCache[S, V](),
+		symbolClasses:       nfa.SymbolClasses(n),
 	}
 
 	return dfaBuilder.buildFromNfa(n)
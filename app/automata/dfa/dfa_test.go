@@ -26,6 +26,7 @@
 package dfa_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/kdeconinck/realign/assert"
@@ -133,6 +134,262 @@ func TestFromNfa_Complex(t *testing.T) {
 	})
 }
 
+// UT: Convert an 'Nfa' with predicate transitions to a 'Dfa'.
+func TestFromNfa_Predicates(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	isDigit := func(r rune) bool { return r >= '0' && r <= '9' }
+	isHex := func(r rune) bool { return isDigit(r) || (r >= 'a' && r <= 'f') }
+
+	// Rule 0: the keyword "a".
+	// Rule 1: a single decimal digit.
+	// Rule 2: a single hexadecimal digit.
+	nMachine := nfa.New[rune, string]()
+	s0 := nMachine.Start()
+
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(s0, 'a'), "KEYWORD")
+
+	decimal := nMachine.NewState()
+	nMachine.AddPredicateTransition(s0, decimal, isDigit)
+	nMachine.AddAcceptingEpsilonTransition(decimal, "DECIMAL")
+
+	hex := nMachine.NewState()
+	nMachine.AddPredicateTransition(s0, hex, isHex)
+	nMachine.AddAcceptingEpsilonTransition(hex, "HEX")
+
+	// Act.
+	dStart := dfa.FromNfa(nMachine).Start()
+
+	// Assert.
+	for _, tc := range []struct {
+		name   string
+		symbol rune
+		want   string
+	}{
+		{"A concrete symbol that also satisfies a predicate follows the rule with the highest priority.", 'a', "KEYWORD"},
+		{"A symbol that satisfies overlapping predicates follows the rule with the highest priority.", '7', "DECIMAL"},
+		{"A symbol that satisfies a single predicate follows that predicate.", 'c', "HEX"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Act.
+			state := dStart.OutgoingFor(tc.symbol)
+
+			// Assert.
+			assert.NotNilf(t, state, "\n\n"+
+				"UT Name:  %s\n"+
+				"\033[32mExpected: NOT <nil>.\033[0m\n"+
+				"\033[31mActual:   <nil>.\033[0m\n\n", tc.name)
+
+			assert.Equalf(t, state.AcceptValue(), tc.want, "\n\n"+
+				"UT Name:  %s\n"+
+				"\033[32mExpected: %s.\033[0m\n"+
+				"\033[31mActual:   %s.\033[0m\n\n", tc.name, tc.want, state.AcceptValue())
+		})
+	}
+
+	t.Run("A symbol that satisfies no predicate has no transition.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		state := dStart.OutgoingFor('z')
+
+		// Assert.
+		assert.Nilf(t, state, "\n\n"+
+			"UT Name:  A symbol that satisfies no predicate has no transition.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   NOT <nil>.\033[0m\n\n")
+	})
+}
+
+// A 'decade' is the class of the integers from 10*n up to 10*n+9.
+type decade int
+
+func (n decade) Contains(i int) bool { return i/10 == int(n) && i >= 0 }
+func (n decade) Boundaries() []int   { return []int{math.MinInt, 10 * int(n), 10*int(n) + 10} }
+
+// UT: Convert an 'Nfa' with class transitions to a 'Dfa'.
+func TestFromNfa_Classes(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	// NOTE: There are more classes than opaque predicates can be determinized, but only 21 minterms can hold.
+	nMachine := nfa.New[int, int]()
+
+	for n := range 40 {
+		state := nMachine.NewState()
+		nMachine.AddClassTransition(nMachine.Start(), state, decade(n))
+		nMachine.AddAcceptingEpsilonTransition(state, n)
+	}
+
+	// Act.
+	var dMachine *dfa.Dfa[int, int]
+
+	fn := func() { dMachine = dfa.FromNfa(nMachine) }
+
+	// Assert.
+	assert.NoPanicf(t, fn, "\n\n"+
+		"UT Name:  When converting an 'Nfa' with 40 class transitions, the function should NOT panic.\n"+
+		"\033[32mExpected: NOT panic.\033[0m\n"+
+		"\033[31mActual:   panic.\033[0m\n\n")
+
+	for _, tc := range []struct {
+		symbol int
+		want   int
+	}{
+		{0, 0}, {9, 0}, {10, 1}, {255, 25}, {399, 39},
+	} {
+		state := dMachine.Start().OutgoingFor(tc.symbol)
+
+		assert.Equalf(t, state.AcceptValue(), tc.want, "\n\n"+
+			"UT Name:  When converting an 'Nfa' with class transitions, %d follows the transition of its class.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", tc.symbol, tc.want, state.AcceptValue())
+	}
+
+	for _, symbol := range []int{-1, 400} {
+		state := dMachine.Start().OutgoingFor(symbol)
+
+		assert.Nilf(t, state, "\n\n"+
+			"UT Name:  When converting an 'Nfa' with class transitions, %d (which isn't in any class) has NO transition.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", symbol, state)
+	}
+
+	_, ok := dMachine.Start().GuardBoundaries()
+
+	assert.Truef(t, ok, "\n\n"+
+		"UT Name:  When converting an 'Nfa' with class transitions, the boundaries of the guard are known.\n"+
+		"\033[32mExpected: true.\033[0m\n"+
+		"\033[31mActual:   false.\033[0m\n\n")
+}
+
+// UT: Convert an 'Nfa' with many opaque predicate transitions to a 'Dfa'.
+func TestTryFromNfa(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When the symbols can be enumerated, only the minterms that hold are built.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[byte, int]()
+
+		for idx := range 20 {
+			state := nMachine.NewState()
+			nMachine.AddPredicateTransition(nMachine.Start(), state, func(b byte) bool { return b%20 == byte(idx) })
+			nMachine.AddAcceptingEpsilonTransition(state, idx)
+		}
+
+		// Act.
+		dMachine, err := dfa.TryFromNfa(nMachine)
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When the symbols can be enumerated, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		assert.Equalf(t, dMachine.NumStates(), 21, "\n\n"+
+			"UT Name:  When the symbols can be enumerated, only the minterms that hold are built.\n"+
+			"\033[32mExpected: 21.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", dMachine.NumStates())
+
+		state := dMachine.Start().OutgoingFor(47)
+
+		assert.Equalf(t, state.AcceptValue(), 7, "\n\n"+
+			"UT Name:  When the symbols can be enumerated, a symbol follows the predicate that holds.\n"+
+			"\033[32mExpected: 7.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", state.AcceptValue())
+	})
+
+	t.Run("When the symbols can't be enumerated, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[int, int]()
+
+		for idx := range 17 {
+			state := nMachine.NewState()
+			nMachine.AddPredicateTransition(nMachine.Start(), state, func(i int) bool { return i == idx })
+			nMachine.AddAcceptingEpsilonTransition(state, idx)
+		}
+
+		// Act.
+		_, err := dfa.TryFromNfa(nMachine)
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When the symbols can't be enumerated, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+
+	t.Run("When the symbols are int32s, only the valid code points are enumerated.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[int32, int]()
+		negative, letter := nMachine.NewState(), nMachine.NewState()
+		nMachine.AddPredicateTransition(nMachine.Start(), negative, func(r int32) bool { return r < 0 })
+		nMachine.AddPredicateTransition(nMachine.Start(), letter, func(r int32) bool { return r >= 'a' && r <= 'z' })
+		nMachine.AddAcceptingEpsilonTransition(negative, 1)
+		nMachine.AddAcceptingEpsilonTransition(letter, 2)
+
+		// Act.
+		dMachine := dfa.FromNfa(nMachine)
+
+		// Assert.
+		state := dMachine.Start().OutgoingFor('q')
+
+		assert.Equalf(t, state.AcceptValue(), 2, "\n\n"+
+			"UT Name:  When the symbols are int32s, a valid code point follows the predicate that holds.\n"+
+			"\033[32mExpected: 2.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", state.AcceptValue())
+
+		assert.Nilf(t, dMachine.Start().OutgoingFor(-1), "\n\n"+
+			"UT Name:  When the symbols are int32s, a negative symbol has NO transition.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", dMachine.Start().OutgoingFor(-1))
+
+		assert.Nilf(t, dMachine.Start().OutgoingFor(math.MaxInt32), "\n\n"+
+			"UT Name:  When the symbols are int32s, a symbol above 'unicode.MaxRune' has NO transition.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", dMachine.Start().OutgoingFor(math.MaxInt32))
+	})
+}
+
+// UT: Convert an 'Nfa' with epsilon cycles to a 'Dfa'.
+func TestFromNfa_EpsilonCycles(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	// (ab)* as produced by 'RepeatAtLeast': the end of the body loops back to its start.
+	nMachine := nfa.New[rune, int]()
+	bodyStart := nMachine.AddEpsilonTransition(nMachine.Start())
+	bodyEnd := nMachine.Add(nMachine.Add(bodyStart, 'a'), 'b')
+
+	nMachine.ConnectEpsilon(bodyEnd, bodyStart)
+	nMachine.ConnectEpsilon(bodyStart, nMachine.Start()) // NOTE: Intentionally creating an epsilon cycle.
+	nMachine.AddAcceptingEpsilonTransition(bodyStart, 1)
+
+	// Act.
+	dStart := dfa.FromNfa(nMachine).Start()
+	afterAB := dStart.OutgoingFor('a').OutgoingFor('b')
+
+	// Assert.
+	assert.Truef(t, dStart.IsAccepting(), "\n\n"+
+		"UT Name:  When converting an 'Nfa' with epsilon cycles, the start state accepts the empty input.\n"+
+		"\033[32mExpected: true.\033[0m\n"+
+		"\033[31mActual:   false.\033[0m\n\n")
+
+	assert.Equalf(t, afterAB.OutgoingFor('a').OutgoingFor('b'), afterAB, "\n\n"+
+		"UT Name:  When converting an 'Nfa' with epsilon cycles, the loop leads back to the same state.\n"+
+		"\033[32mExpected: Same State pointer.\033[0m\n"+
+		"\033[31mActual:   Different.\033[0m\n\n")
+}
+
 var benchmarkOutput *dfa.Dfa[int, int] // Output of the benchmark(s).
 
 // Benchmark: Linear NFa to Dfa.
@@ -145,6 +402,11 @@ func BenchmarkFromNfa_FanOut_10(b *testing.B)   { benchmarkFromNfa_FanOut(10, b)
 func BenchmarkFromNfa_FanOut_100(b *testing.B)  { benchmarkFromNfa_FanOut(100, b) }
 func BenchmarkFromNfa_FanOut_1000(b *testing.B) { benchmarkFromNfa_FanOut(1_000, b) }
 
+// Benchmark: Kleene star heavy NFa to Dfa.
+func BenchmarkFromNfa_Star_10(b *testing.B)  { benchmarkFromNfa_Star(10, b) }
+func BenchmarkFromNfa_Star_50(b *testing.B)  { benchmarkFromNfa_Star(50, b) }
+func BenchmarkFromNfa_Star_100(b *testing.B) { benchmarkFromNfa_Star(100, b) }
+
 func benchmarkFromNfa_Linear(count int, b *testing.B) {
 	nMachine := nfa.New[int, int]()
 	current := nMachine.Start()
@@ -172,3 +434,25 @@ func benchmarkFromNfa_FanOut(count int, b *testing.B) {
 		benchmarkOutput = dfa.FromNfa(nMachine)
 	}
 }
+
+func benchmarkFromNfa_Star(count int, b *testing.B) {
+	nMachine := nfa.New[int, int]()
+	current := nMachine.Start()
+
+	// A sequence of 'count' Kleene stars: 0* 1* 0* 1* ...
+	// Every star can be skipped, so the epsilon closure of the start state spans the whole nfa.
+	for i := 0; i < count; i++ {
+		bodyStart := nMachine.AddEpsilonTransition(current)
+		bodyEnd := nMachine.Add(bodyStart, i%2)
+
+		nMachine.ConnectEpsilon(bodyEnd, bodyStart)
+		current = nMachine.AddEpsilonTransition(bodyStart)
+	}
+
+	nMachine.AddAcceptingEpsilonTransition(current, 1)
+
+	b.ResetTimer()
+	for b.Loop() {
+		benchmarkOutput = dfa.FromNfa(nMachine)
+	}
+}
@@ -30,8 +30,35 @@
 //   - Having exactly one transition for every state and every possible symbol.
 //   - No epsilon transitions.
 //
-// Since a Dfa is deterministic, its transitions are typically based only on concrete symbols (type S).
-// Predicate-based transitions are generally not supported as they can violate determinism.
+// Since a Dfa is deterministic, its transitions are primarily based on concrete symbols (type S).
+// Predicate-based transitions of an Nfa are determinized into guards: the predicates leaving a subset of Nfa states
+// are combined into disjoint minterms, and a symbol without a concrete transition is dispatched on the minterm of the
+// predicates that hold for it.
 // States can be marked as accepting and carry an associated value of type V, which can later be used by a matcher or
 // engine built on top of this package.
+//
+// Assertion transitions of an Nfa (like line anchors and word boundaries) are determinized by splitting states per
+// kind of the previous symbol. A Dfa built from such an Nfa has a start state per kind of the symbol before the input
+// ([Dfa.StartAfter]) and states whose acceptance depends on the kind of the next symbol ([State.AcceptingBefore]).
+//
+// Converting an Nfa can create exponentially many states. A [Lazy] DFA avoids that by determinizing only the states
+// that the input demands, caching them within a memory budget and falling back to simulating the Nfa when the cache
+// thrashes.
+//
+// The languages of Dfas can be combined with [Intersect], [Difference] and [Complement], which use a product
+// construction. A Dfa can be integrated into an Nfa with [Dfa.Build].
+//
+// Languages can be compared with [IsEmpty], [IsUniversal], [Includes] and [Equivalent], which return a shortest
+// counterexample when the answer is negative. Optionally, the accepting values are compared as well, so that a set of
+// rules that accepts the same inputs, but produces different tokens, is reported.
+//
+// [FromNfa] expands the symbols that no transition distinguishes (see [nfa.SymbolClasses]) once per class instead of
+// once per symbol. A Dfa over bytes can be compiled into a [Table] with [Compile], which resolves the transitions once
+// per class of bytes (see [AlphabetOf]), stores a dense row of targets for every state and matches input without any
+// map lookup.
+//
+// A Dfa can be serialized with [Dfa.MarshalBinary] and restored with [Load] (or [Dfa.UnmarshalBinary]), so machines
+// can be compiled ahead of time and embedded in a binary. The symbols and values are encoded by a [Codec].
+//
+// The structure of a Dfa can be inspected with [Dfa.Reachable], [State.Transitions] and [State.GuardTransitions].
 package dfa
@@ -0,0 +1,158 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/kdeconinck/realign/automata/internal/dot"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// DOTOptions configures the output of [WriteDOT].
+type DOTOptions[S comparable, V any] = dot.Options[S, V]
+
+// WriteDOT writes machine to w in the DOT language of Graphviz.
+//
+// Every [State] that's reachable from a start state is drawn, labeled with its ID. Accepting states are drawn as a
+// double circle, labeled with their accepting value and acceptance index. The transitions of a guard are drawn dotted
+// and labeled with the classes of the predicates that hold if they implement [fmt.Stringer], or with their minterm
+// otherwise (where the rightmost digit corresponds to the first predicate).
+//
+// For a DFA with assertions, the start states after a symbol of another kind (see [Dfa.StartAfter]) have an extra
+// start point with a dashed edge, labeled with those kinds. Conditional states (see [State.AcceptingBefore]) are drawn
+// as a dashed double circle, labeled with their acceptance before every kind of the next symbol.
+//
+// The output only depends on the structure of machine, so it can be compared against a golden file.
+func WriteDOT[S comparable, V any](w io.Writer, machine *Dfa[S, V], opts DOTOptions[S, V]) error {
+	name := opts.Name
+
+	if name == "" {
+		name = "dfa"
+	}
+
+	graph := dot.New(name, machine.start.id)
+	states := slices.Collect(machine.Reachable())
+
+	writeStarts(graph, machine)
+
+	for _, state := range states {
+		if state.conditions != nil {
+			dot.ConditionalState(graph, opts, state.id, conditionsOf(state))
+
+			continue
+		}
+
+		dot.State(graph, opts, state.id, state.acceptIdx, state.value)
+	}
+
+	for _, state := range states {
+		symbolsByTarget := make(map[*State[S, V]][]S)
+		var targets []*State[S, V]
+
+		for symbol, target := range state.transitions {
+			if _, ok := symbolsByTarget[target]; !ok {
+				targets = append(targets, target)
+			}
+
+			symbolsByTarget[target] = append(symbolsByTarget[target], symbol)
+		}
+
+		slices.SortFunc(targets, func(a, b *State[S, V]) int { return a.id - b.id })
+
+		for _, target := range targets {
+			dot.Symbols(graph, opts, state.id, target.id, symbolsByTarget[target])
+		}
+
+		if state.guard == nil {
+			continue
+		}
+
+		for _, mask := range state.guard.masks() {
+			dot.Edge(graph, state.id, state.guard.targets[mask].id, state.guard.label(mask), "dotted")
+		}
+	}
+
+	_, err := graph.WriteTo(w)
+
+	return err
+}
+
+// Adds an extra start point to graph for every start state of machine that follows a symbol (see [Dfa.StartAfter]),
+// unless it's the start state itself. The kinds that share a start state are drawn as a single edge.
+func writeStarts[S comparable, V any](graph *dot.Graph, machine *Dfa[S, V]) {
+	kindsByStart := make(map[*State[S, V]][]string)
+	var starts []*State[S, V]
+
+	for prev, start := range machine.starts {
+		if prev == int(nfa.KindNone) || start == machine.start {
+			continue
+		}
+
+		if _, ok := kindsByStart[start]; !ok {
+			starts = append(starts, start)
+		}
+
+		kindsByStart[start] = append(kindsByStart[start], nfa.SymbolKind(prev).String())
+	}
+
+	for _, start := range starts {
+		dot.Start(graph, start.id, "after "+strings.Join(kindsByStart[start], ", "))
+	}
+}
+
+// Returns the conditions of the conditional state, in the order of the first kind of the next symbol for which they
+// hold. The kinds for which state accepts with the same acceptance index are merged into a single condition.
+func conditionsOf[S comparable, V any](state *State[S, V]) []dot.Condition[V] {
+	var conditions []dot.Condition[V]
+
+	positions := make(map[int]int)
+
+	for next, accepting := range state.conditions {
+		if accepting == nil {
+			continue
+		}
+
+		kind := nfa.SymbolKind(next).String()
+
+		if pos, ok := positions[accepting.acceptIdx]; ok {
+			conditions[pos].Before += ", " + kind
+
+			continue
+		}
+
+		positions[accepting.acceptIdx] = len(conditions)
+		conditions = append(conditions, dot.Condition[V]{
+			Before:    kind,
+			AcceptIdx: accepting.acceptIdx,
+			Value:     accepting.value,
+		})
+	}
+
+	return conditions
+}
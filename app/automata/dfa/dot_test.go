@@ -0,0 +1,218 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns a minimal [dfa.Dfa] recognizing the keyword "if", a single digit and words made of the letters 'a' to 'e'.
+func newDOTMachine() *dfa.Dfa[rune, string] {
+	nMachine := nfa.New[rune, string]()
+	digit := nMachine.NewState()
+	word := nMachine.NewState()
+
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'i'), 'f'), "IF")
+	nMachine.AddPredicateTransition(nMachine.Start(), digit, unicode.IsDigit)
+	nMachine.AddAcceptingEpsilonTransition(digit, "DIGIT")
+
+	for _, r := range "abcde" {
+		nMachine.ConnectEpsilon(nMachine.Add(nMachine.Start(), r), word)
+		nMachine.ConnectEpsilon(nMachine.Add(word, r), word)
+	}
+
+	nMachine.AddAcceptingEpsilonTransition(word, "WORD")
+
+	minimal, _ := dfa.Minimize(dfa.FromNfa(nMachine))
+
+	return minimal
+}
+
+// UT: Write a 'Dfa' in the DOT language.
+func TestWriteDOT(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When ranges are collapsed, the output is correct.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		var sb strings.Builder
+
+		// Act.
+		err := dfa.WriteDOT(&sb, newDOTMachine(), dfa.DOTOptions[rune, string]{CollapseRanges: true})
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When writing a DFA in the DOT language, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		want := `digraph "dfa" {
+	rankdir=LR;
+	node [shape=circle];
+	__start [shape=point];
+	__start -> 0;
+	0;
+	1 [shape=doublecircle, label="1\nWORD #2"];
+	2;
+	3 [shape=doublecircle, label="3\nDIGIT #1"];
+	4 [shape=doublecircle, label="4\nIF #0"];
+	0 -> 1 [label="'a'-'e'"];
+	0 -> 2 [label="'i'"];
+	0 -> 3 [style=dotted, label="minterm 1"];
+	1 -> 1 [label="'a'-'e'"];
+	2 -> 4 [label="'f'"];
+}
+`
+
+		assert.Equalf(t, sb.String(), want, "\n\n"+
+			"UT Name:  When ranges are collapsed, the output is correct.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", want, sb.String())
+	})
+
+	t.Run("When the classes of a guard implement fmt.Stringer, its edges are labeled with them.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		var sb strings.Builder
+
+		machine := newRuleMachine(rule{class: &runeRange{'a', 'z'}, value: 1}, rule{class: &runeRange{'m', 'p'}, value: 2})
+
+		// Act.
+		_ = dfa.WriteDOT(&sb, machine, dfa.DOTOptions[rune, int]{})
+
+		// Assert.
+		want := `digraph "dfa" {
+	rankdir=LR;
+	node [shape=circle];
+	__start [shape=point];
+	__start -> 0;
+	0;
+	1 [shape=doublecircle, label="1\n1 #0"];
+	2 [shape=doublecircle, label="2\n1 #0"];
+	0 -> 1 [style=dotted, label="'a'-'z'"];
+	0 -> 2 [style=dotted, label="'a'-'z' & 'm'-'p'"];
+	1 -> 1 [style=dotted, label="'a'-'z'"];
+	2 -> 1 [style=dotted, label="'a'-'z'"];
+	2 -> 2 [style=dotted, label="'a'-'z' & 'm'-'p'"];
+}
+`
+
+		assert.Equalf(t, sb.String(), want, "\n\n"+
+			"UT Name:  When the classes of a guard implement fmt.Stringer, its edges are labeled with them.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", want, sb.String())
+	})
+
+	t.Run("When the DFA has assertions, its extra start states and conditions are drawn.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		var sb strings.Builder
+
+		nMachine := nfa.New[rune, string]()
+		line, end := nMachine.NewState(), nMachine.NewState()
+
+		nMachine.AddAssertion(nMachine.Start(), line, nfa.BeginLine)
+		nMachine.AddAssertion(nMachine.Add(line, 'a'), end, nfa.EndLine)
+		nMachine.AddAcceptingEpsilonTransition(end, "LINE")
+		nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Start(), 'a'), "A")
+
+		minimal, _ := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		// Act.
+		_ = dfa.WriteDOT(&sb, minimal, dfa.DOTOptions[rune, string]{})
+
+		// Assert.
+		want := `digraph "dfa" {
+	rankdir=LR;
+	node [shape=circle];
+	__start [shape=point];
+	__start -> 0;
+	__start1 [shape=point];
+	__start1 -> 1 [style=dashed, label="after word, other"];
+	0;
+	1;
+	2 [shape=doublecircle, style=dashed, label="2\nLINE #0 before none, newline\nA #1 before word, other"];
+	3 [shape=doublecircle, label="3\nA #1"];
+	0 -> 2 [label="'a'"];
+	1 -> 3 [label="'a'"];
+}
+`
+
+		assert.Equalf(t, sb.String(), want, "\n\n"+
+			"UT Name:  When the DFA has assertions, its extra start states and conditions are drawn.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", want, sb.String())
+	})
+
+	t.Run("When ranges are NOT collapsed, every symbol has its own edge.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		var sb strings.Builder
+
+		// Act.
+		_ = dfa.WriteDOT(&sb, newDOTMachine(), dfa.DOTOptions[rune, string]{})
+
+		// Assert.
+		got := strings.Count(sb.String(), "1 -> 1 [")
+
+		assert.Equalf(t, got, 5, "\n\n"+
+			"UT Name:  When ranges are NOT collapsed, every symbol has its own edge.\n"+
+			"\033[32mExpected: 5.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", got)
+	})
+
+	t.Run("When writing equivalent DFAs, the output is identical.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		var first strings.Builder
+
+		_ = dfa.WriteDOT(&first, newDOTMachine(), dfa.DOTOptions[rune, string]{})
+
+		for range 10 {
+			var sb strings.Builder
+
+			// Act.
+			_ = dfa.WriteDOT(&sb, newDOTMachine(), dfa.DOTOptions[rune, string]{})
+
+			// Assert.
+			assert.Equalf(t, sb.String(), first.String(), "\n\n"+
+				"UT Name:  When writing equivalent DFAs, the output is identical.\n"+
+				"\033[32mExpected: %s.\033[0m\n"+
+				"\033[31mActual:   %s.\033[0m\n\n", first.String(), sb.String())
+		}
+	})
+}
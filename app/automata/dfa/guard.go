@@ -0,0 +1,269 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/collections/set"
+)
+
+// The maximum number of distinct predicate targets leaving a single subset of [nfa.State]s when some of them are opaque
+// and the symbols can't be enumerated.
+//
+// Reasoning:
+// Predicates are opaque functions, so it's impossible to know which combinations of them can be true at the same time
+// without evaluating them on every symbol. Every combination (minterm) therefore requires its own transition, which
+// means that the number of transitions grows exponentially with the number of predicates.
+const maxGuardPredicates = 16
+
+// The maximum number of distinct predicate targets leaving a single subset of [nfa.State]s when all of them are built
+// from an [nfa.Class] or when the symbols can be enumerated.
+// Since only the minterms that can actually hold are built, this is only limited by the size of a minterm.
+const maxExactGuardPredicates = 64
+
+// Returns every symbol of type S or false if the symbols of type S can't be enumerated.
+// Only 8-bit and 16-bit integers and runes (int32) can be enumerated. For runes, only the valid code points (from 0 up
+// to and including [unicode.MaxRune]) are returned.
+//
+// Reasoning:
+// Since rune is an alias for int32, both types are the same type, so every int32 is enumerated as a rune. Enumerating
+// every int32 instead would evaluate the predicates on more than 4 billion symbols.
+func enumerateSymbols[S comparable]() (iter.Seq[S], bool) {
+	var symbols any
+
+	switch any(*new(S)).(type) {
+	case uint8:
+		symbols = enumerateRange[uint8](0, math.MaxUint8)
+	case int8:
+		symbols = enumerateRange[int8](math.MinInt8, math.MaxInt8)
+	case uint16:
+		symbols = enumerateRange[uint16](0, math.MaxUint16)
+	case int16:
+		symbols = enumerateRange[int16](math.MinInt16, math.MaxInt16)
+	case int32:
+		symbols = enumerateRange[int32](0, unicode.MaxRune)
+	default:
+		return nil, false
+	}
+
+	return symbols.(iter.Seq[S]), true
+}
+
+// Returns the integers from lo up to and including hi.
+func enumerateRange[T int8 | uint8 | int16 | uint16 | int32](lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for symbol := lo; ; symbol++ {
+			if !yield(symbol) || symbol == hi {
+				return
+			}
+		}
+	}
+}
+
+// A 'guard' dispatches symbols that don't have a concrete transition from a [State] on the outcome of a set of
+// predicates.
+//
+// Each predicate corresponds to a bit in a minterm: a bitmask recording which predicates hold for a given symbol. The
+// minterms are disjoint by construction, so every symbol selects exactly one target (or none if no predicate holds).
+type guard[S comparable, V any] struct {
+	predicates []func(S) bool
+	targets    map[uint64]*State[S, V]
+	boundaries []S      // The boundaries of the classes of the predicates (nil if any of them is opaque).
+	labels     []string // The labels of the predicates ("" or missing if unknown, see [guardClass.label]).
+}
+
+// Returns the minterm of symbol.
+func (g *guard[S, V]) minterm(symbol S) uint64 {
+	var mask uint64
+
+	for idx, fn := range g.predicates {
+		if fn(symbol) {
+			mask |= 1 << idx
+		}
+	}
+
+	return mask
+}
+
+// Returns the target [State] for symbol or nil if no predicate holds for symbol.
+func (g *guard[S, V]) outgoingFor(symbol S) *State[S, V] {
+	return g.targets[g.minterm(symbol)]
+}
+
+// Returns the label of the minterm mask: the labels of the predicates that hold, joined by " & ". If any of those
+// predicates doesn't have a label, the minterm itself is returned, where the rightmost digit corresponds to the first
+// predicate.
+func (g *guard[S, V]) label(mask uint64) string {
+	var labels []string
+
+	for idx := range g.predicates {
+		if mask&(1<<idx) == 0 {
+			continue
+		}
+
+		if idx >= len(g.labels) || g.labels[idx] == "" {
+			return fmt.Sprintf("minterm %0*b", len(g.predicates), mask)
+		}
+
+		labels = append(labels, g.labels[idx])
+	}
+
+	return strings.Join(labels, " & ")
+}
+
+// Returns the labels of the predicates of g, where the unknown ones are "".
+func (g *guard[S, V]) predicateLabels() []string {
+	labels := make([]string, len(g.predicates))
+	copy(labels, g.labels)
+
+	return labels
+}
+
+// Returns the minterms that lead to a target, in ascending order.
+func (g *guard[S, V]) masks() []uint64 {
+	masks := make([]uint64, 0, len(g.targets))
+
+	for mask := range g.targets {
+		masks = append(masks, mask)
+	}
+
+	slices.Sort(masks)
+
+	return masks
+}
+
+// Returns the boundaries of all the classes (and extra) or false if any of the classes is opaque.
+// Evaluating the classes on every boundary produces every minterm that can hold (see [nfa.Class]).
+func guardBoundaries[S comparable, V any](classes []*guardClass[S, V], extra ...S) ([]S, bool) {
+	seen := set.New[S]()
+
+	var boundaries []S
+
+	for _, boundary := range extra {
+		seen.Add(boundary)
+		boundaries = append(boundaries, boundary)
+	}
+
+	for _, class := range classes {
+		if class.opaque {
+			return nil, false
+		}
+
+		for _, c := range class.classes {
+			for _, boundary := range c.Boundaries() {
+				if !seen.Has(boundary) {
+					seen.Add(boundary)
+					boundaries = append(boundaries, boundary)
+				}
+			}
+		}
+	}
+
+	return boundaries, true
+}
+
+// A 'guardClass' is a group of predicate transitions that lead to the same [nfa.State].
+// Since the outcome of a minterm only depends on the [nfa.State]s that are reached, these predicates can be merged.
+type guardClass[S comparable, V any] struct {
+	predicates []func(S) bool
+	classes    []nfa.Class[S] // The classes of the predicates (nil if any of them is opaque).
+	endState   *nfa.State[S, V]
+	opaque     bool // True if any of the predicates isn't built from an [nfa.Class].
+}
+
+// Returns true if any of the predicates of the class holds for symbol.
+func (class *guardClass[S, V]) matches(symbol S) bool {
+	for _, fn := range class.predicates {
+		if fn(symbol) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the labels of the classes of the predicates, joined by " | ", or "" if any of them doesn't implement
+// [fmt.Stringer].
+func (class *guardClass[S, V]) label() string {
+	if class.opaque {
+		return ""
+	}
+
+	labels := make([]string, 0, len(class.classes))
+
+	for _, c := range class.classes {
+		stringer, ok := c.(fmt.Stringer)
+
+		if !ok {
+			return ""
+		}
+
+		labels = append(labels, stringer.String())
+	}
+
+	return strings.Join(labels, " | ")
+}
+
+// Groups the predicate transitions leaving states by the [nfa.State] they lead to.
+// The order of the classes is the order in which their [nfa.State]s are first encountered.
+func findGuardClasses[S comparable, V any](states []*nfa.State[S, V]) []*guardClass[S, V] {
+	var classes []*guardClass[S, V]
+
+	classByEndState := make(map[int]*guardClass[S, V])
+
+	for _, state := range states {
+		for _, transition := range state.Predicates() {
+			class, ok := classByEndState[transition.EndState.ID()]
+
+			if !ok {
+				class = &guardClass[S, V]{
+					endState: transition.EndState,
+				}
+
+				classByEndState[transition.EndState.ID()] = class
+				classes = append(classes, class)
+			}
+
+			class.predicates = append(class.predicates, transition.Fn)
+
+			if transition.Class == nil {
+				class.opaque = true
+				class.classes = nil
+			} else if !class.opaque {
+				class.classes = append(class.classes, transition.Class)
+			}
+		}
+	}
+
+	return classes
+}
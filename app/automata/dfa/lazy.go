@@ -0,0 +1,269 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"sync"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// DefaultCacheBudget is the number of bytes that a [Lazy] DFA caches by default.
+const DefaultCacheBudget = 1 << 20
+
+// The approximate number of bytes used by a cached [lazyState] (excluding its [nfa.State]s) and by a single cached
+// transition.
+const (
+	lazyStateBytes      = 96
+	lazyTransitionBytes = 48
+)
+
+// The minimum number of symbols that must be consumed per cached [lazyState] between two flushes of the cache.
+// When the cache is flushed sooner, the cached states are hardly reused, so determinizing them is a waste of time.
+const minSymbolsPerState = 10
+
+// LazyOption configures a [Lazy] DFA.
+type LazyOption func(*lazyOptions)
+
+// The configuration of a [Lazy] DFA.
+type lazyOptions struct {
+	budget int
+}
+
+// WithCacheBudget sets the (approximate) number of bytes that a [Lazy] DFA may use to cache states and transitions.
+// The default is [DefaultCacheBudget].
+func WithCacheBudget(bytes int) LazyOption {
+	return func(opts *lazyOptions) {
+		opts.budget = max(bytes, 0)
+	}
+}
+
+// LazyStats describes the work done by a [Lazy] DFA.
+type LazyStats struct {
+	States    int // The number of states that are currently cached.
+	Bytes     int // The (approximate) number of bytes that are currently cached.
+	Flushes   int // The number of times the cache was flushed because it was full.
+	Fallbacks int // The number of matches that fell back to simulating the Nfa.
+}
+
+// Lazy is a deterministic automaton that's built from an [nfa.Nfa] on the fly, while input is matched.
+//
+// Unlike [FromNfa], which builds every reachable subset of [nfa.State]s up front, a Lazy DFA only determinizes the
+// subsets (and the transitions between them) that the input demands. Those are cached, so matching similar input
+// again is as fast as with a [Dfa]. When the cache exceeds its budget (see [WithCacheBudget]), it's flushed and
+// rebuilt as needed. If the cache is flushed before enough input was consumed to make up for building it, the cache
+// is thrashing: the match falls back to simulating the Nfa (see [nfa.Nfa.MatchAt]), whose memory use doesn't depend
+// on the input.
+//
+// As a result, the memory use is bounded, even for patterns like (a|b)*a(a|b){20}, whose [Dfa] has millions of states.
+//
+// An Nfa with assertion transitions is always simulated. A Lazy DFA is safe for concurrent use, but matches are
+// serialized, since they share the cache.
+type Lazy[S comparable, V any] struct {
+	mu         sync.Mutex
+	machine    *nfa.Nfa[S, V]
+	closures   *closureCache[S, V]
+	budget     int
+	states     map[string]*lazyState[S, V] // The cached states, by the key of their subset.
+	start      *lazyState[S, V]            // The cached start state (nil if it isn't cached).
+	bytes      int                         // The number of bytes that are cached.
+	sinceFlush int                         // The number of symbols that were consumed since the last flush.
+	thrashing  bool                        // True if the cache was flushed too soon during the current match.
+	stats      LazyStats
+}
+
+// A 'lazyState' is a cached subset of [nfa.State]s of a [Lazy] DFA.
+type lazyState[S comparable, V any] struct {
+	states      []*nfa.State[S, V]
+	transitions map[S]*lazyState[S, V] // The cached transitions (a nil target means that no state is reached).
+	acceptIdx   int
+	value       V
+}
+
+// NewLazy creates a [Lazy] DFA for machine.
+func NewLazy[S comparable, V any](machine *nfa.Nfa[S, V], opts ...LazyOption) *Lazy[S, V] {
+	config := lazyOptions{
+		budget: DefaultCacheBudget,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &Lazy[S, V]{
+		machine:  machine,
+		closures: newClosureCache[S, V](),
+		budget:   config.budget,
+		states:   make(map[string]*lazyState[S, V]),
+	}
+}
+
+// Stats returns the work done by the DFA so far.
+func (l *Lazy[S, V]) Stats() LazyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.States = len(l.states)
+	stats.Bytes = l.bytes
+
+	return stats
+}
+
+// Match matches the longest prefix of input, just like [nfa.Nfa.Match] does.
+func (l *Lazy[S, V]) Match(input []S) (nfa.Match[V], bool) {
+	return l.MatchAt(input, 0)
+}
+
+// MatchAt matches the longest prefix of input[start:], just like [nfa.Nfa.MatchAt] does.
+func (l *Lazy[S, V]) MatchAt(input []S, start int) (nfa.Match[V], bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.machine.HasAssertions() {
+		l.stats.Fallbacks++
+
+		return l.machine.MatchAt(input, start)
+	}
+
+	var match nfa.Match[V]
+	found := false
+
+	l.thrashing = false
+	state := l.startState()
+
+	for idx := start; state != nil; idx++ {
+		if state.acceptIdx > -1 {
+			match = nfa.Match[V]{Value: state.value, AcceptIdx: state.acceptIdx, Length: idx - start}
+			found = true
+		}
+
+		if idx == len(input) {
+			break
+		}
+
+		next, ok := state.transitions[input[idx]]
+
+		if !ok {
+			next = l.addTransition(state, input[idx])
+		}
+
+		if l.thrashing {
+			l.stats.Fallbacks++
+
+			return l.machine.MatchAt(input, start)
+		}
+
+		l.sinceFlush++
+		state = next
+	}
+
+	return match, found
+}
+
+// Returns the cached start state, adding it to the cache if it isn't cached.
+func (l *Lazy[S, V]) startState() *lazyState[S, V] {
+	if l.start == nil {
+		l.start = l.ensureState(l.closures.findPossibleStates(l.machine.Start()))
+	}
+
+	return l.start
+}
+
+// Determinizes the transition from state for symbol, adds it to the cache and returns its target (which is nil if no
+// [nfa.State] is reached).
+func (l *Lazy[S, V]) addTransition(state *lazyState[S, V], symbol S) *lazyState[S, V] {
+	var targets []*nfa.State[S, V]
+
+	for _, nState := range state.states {
+		targets = append(targets, nState.OutgoingFor(symbol)...)
+
+		for _, transition := range nState.Predicates() {
+			if transition.Fn(symbol) {
+				targets = append(targets, transition.EndState)
+			}
+		}
+	}
+
+	var target *lazyState[S, V]
+
+	if len(targets) > 0 {
+		target = l.ensureState(l.closures.findPossibleStates(targets...))
+	}
+
+	// NOTE: When the cache was flushed, state isn't cached anymore, so its transitions don't need to be recorded.
+	if l.reserve(lazyTransitionBytes) {
+		state.transitions[symbol] = target
+	}
+
+	return target
+}
+
+// Returns the cached state for the subset states, adding it to the cache if it isn't cached.
+func (l *Lazy[S, V]) ensureState(states []*nfa.State[S, V]) *lazyState[S, V] {
+	key := calculateStatesKey(states)
+
+	if state, ok := l.states[key]; ok {
+		return state
+	}
+
+	acceptIdx, value, _ := findAcceptanceIdx(states)
+
+	state := &lazyState[S, V]{
+		states:      states,
+		transitions: make(map[S]*lazyState[S, V]),
+		acceptIdx:   acceptIdx,
+		value:       value,
+	}
+
+	l.reserve(lazyStateBytes + 8*len(states))
+	l.states[key] = state
+
+	return state
+}
+
+// Reserves n bytes of the cache, flushing it first if it would exceed the budget.
+// It returns false if the cache was flushed.
+func (l *Lazy[S, V]) reserve(n int) bool {
+	if l.bytes+n <= l.budget {
+		l.bytes += n
+
+		return true
+	}
+
+	if l.sinceFlush < minSymbolsPerState*len(l.states) {
+		l.thrashing = true
+	}
+
+	clear(l.states)
+
+	l.start = nil
+	l.bytes = n
+	l.sinceFlush = 0
+	l.stats.Flushes++
+
+	return false
+}
@@ -0,0 +1,175 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns an 'Nfa' for (a|b)*a(a|b){n}, whose 'Dfa' has 2^(n+1)+1 states.
+func newExplodingMachine(n int) *nfa.Nfa[rune, int] {
+	machine := nfa.New[rune, int]()
+	s0 := machine.Start()
+
+	machine.ConnectEpsilon(machine.Add(s0, 'a'), s0)
+	machine.ConnectEpsilon(machine.Add(s0, 'b'), s0)
+
+	tail := machine.Add(s0, 'a')
+
+	for range n {
+		next := machine.NewState()
+		machine.ConnectEpsilon(machine.Add(tail, 'a'), next)
+		machine.ConnectEpsilon(machine.Add(tail, 'b'), next)
+		tail = next
+	}
+
+	machine.AddAcceptingEpsilonTransition(tail, 1)
+
+	return machine
+}
+
+// Returns a pseudo-random string of n runes of "ab".
+func randomInput(n int) []rune {
+	input := make([]rune, n)
+	seed := uint32(2166136261)
+
+	for idx := range input {
+		seed = seed*1664525 + 1013904223
+		input[idx] = rune('a' + seed>>31)
+	}
+
+	return input
+}
+
+// UT: Match input with a 'Lazy' DFA.
+func TestLazy_Match(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	nMachine := newExplodingMachine(3)
+
+	for _, tc := range []struct {
+		name   string
+		budget int
+	}{
+		{"the cache is large enough", dfa.DefaultCacheBudget},
+		{"the cache is flushed", 1024},
+		{"the cache thrashes", 0},
+	} {
+		lazy := dfa.NewLazy(nMachine, dfa.WithCacheBudget(tc.budget))
+
+		for _, input := range allStrings("abc", 7) {
+			// Act.
+			want, wantOk := nMachine.Match([]rune(input))
+			got, gotOk := lazy.Match([]rune(input))
+
+			// Assert.
+			assert.Equalf(t, got == want && gotOk == wantOk, true, "\n\n"+
+				"UT Name:  When %s, the 'Lazy' DFA matches %q like the 'Nfa'.\n"+
+				"\033[32mExpected: %v (%t).\033[0m\n"+
+				"\033[31mActual:   %v (%t).\033[0m\n\n", tc.name, input, want, wantOk, got, gotOk)
+		}
+	}
+}
+
+// UT: Bound the memory used by a 'Lazy' DFA.
+func TestLazy_Stats(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	const budget = 64 << 10
+
+	nMachine := newExplodingMachine(20)
+	input := randomInput(100_000)
+
+	t.Run("When the cache exceeds its budget, it's flushed.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lazy := dfa.NewLazy(nMachine, dfa.WithCacheBudget(budget))
+
+		// Act.
+		got, _ := lazy.Match(input)
+		want, _ := nMachine.Match(input)
+		stats := lazy.Stats()
+
+		// Assert.
+		assert.Equalf(t, got, want, "\n\n"+
+			"UT Name:  When the cache exceeds its budget, the 'Lazy' DFA matches like the 'Nfa'.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, got)
+
+		assert.Truef(t, stats.Flushes > 0 && stats.Bytes <= budget, "\n\n"+
+			"UT Name:  When the cache exceeds its budget, it's flushed.\n"+
+			"\033[32mExpected: Flushes > 0, Bytes <= %d.\033[0m\n"+
+			"\033[31mActual:   Flushes = %d, Bytes = %d.\033[0m\n\n", budget, stats.Flushes, stats.Bytes)
+	})
+
+	t.Run("When the cache thrashes, the 'Nfa' is simulated.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lazy := dfa.NewLazy(nMachine, dfa.WithCacheBudget(1024))
+
+		// Act.
+		got, _ := lazy.Match(input)
+		want, _ := nMachine.Match(input)
+		stats := lazy.Stats()
+
+		// Assert.
+		assert.Equalf(t, got, want, "\n\n"+
+			"UT Name:  When the cache thrashes, the 'Lazy' DFA matches like the 'Nfa'.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, got)
+
+		assert.Equalf(t, stats.Fallbacks, 1, "\n\n"+
+			"UT Name:  When the cache thrashes, the 'Nfa' is simulated.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 1, stats.Fallbacks)
+	})
+
+	t.Run("When the cache is large enough, it's never flushed.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lazy := dfa.NewLazy(newExplodingMachine(3))
+
+		// Act.
+		lazy.Match(input)
+		stats := lazy.Stats()
+
+		// Assert.
+		assert.Truef(t, stats.Flushes == 0 && stats.Fallbacks == 0 && stats.States == 17, "\n\n"+
+			"UT Name:  When the cache is large enough, it's never flushed.\n"+
+			"\033[32mExpected: Flushes = 0, Fallbacks = 0, States = 17.\033[0m\n"+
+			"\033[31mActual:   Flushes = %d, Fallbacks = %d, States = %d.\033[0m\n\n",
+			stats.Flushes, stats.Fallbacks, stats.States)
+	})
+}
@@ -0,0 +1,547 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/collections/set"
+)
+
+// Minimize returns a new [Dfa] that's equivalent to d with the minimal number of states, together with the number of
+// states that were removed.
+//
+// Minimize implements Hopcroft's partition refinement algorithm. The initial partition separates states by their
+// acceptance index, so states that would produce different accepting values are never merged. States that can never
+// reach an accepting state are removed together with the transitions leading to them.
+//
+// The guards of states (see [FromNfa]) that are built from classes (see [nfa.Class]) are compared by their boundaries:
+// a guard dispatches all the symbols from one boundary up to the next one to the same target, so two guards with the
+// same boundaries are equivalent if they dispatch every boundary to equivalent targets. States with guards that have
+// other boundaries, or with opaque predicates, which can't be compared, are never merged with each other.
+//
+// The states of the returned [Dfa] have consecutive IDs, starting with 0 for the start state. The order of the other
+// states follows the IDs of the states in d they were created from.
+func Minimize[S comparable, V any](d *Dfa[S, V]) (*Dfa[S, V], int) {
+	m := newMinimizer(d)
+
+	m.refine()
+
+	minimal := m.build()
+
+	return minimal, len(d.states) - len(minimal.states)
+}
+
+// A 'minimizer' holds the state of Hopcroft's algorithm for a single [Dfa].
+//
+// Reasoning:
+// The textbook algorithm requires a complete automaton, but a [Dfa] only stores the transitions that exist. Completing
+// it with a "dead" state would require a transition for every state and every symbol. Instead, the states that can't
+// reach an accepting state are removed first, after which the partition is refined using the existing transitions only
+// (as described by Valmari and Lehtinen). This keeps the work proportional to the number of transitions.
+type minimizer[S comparable, V any] struct {
+	dfa          *Dfa[S, V]
+	live         []*State[S, V]         // The states that can reach an accepting state, in order of their ID.
+	index        map[int]int            // The index in live of every live state, by ID.
+	alphabet     map[S]int              // The index of every symbol.
+	guardSymbols map[guardSymbol[S]]int // The index of every boundary of a guard, after the ones of the symbols.
+	boundarySets []set.Set[S]           // The distinct sets of boundaries of the guards built from classes.
+	boundarySet  []int                  // The index in boundarySets of the guard of every live state (or -1).
+	inverse      [][]minimizeArc        // The transitions into every live state.
+	buckets      [][]int                // Per symbol index, the sources that are being marked.
+	partition    *partition
+}
+
+// A 'guardSymbol' stands for the symbols that a guard with the boundaries in the set with index boundarySet dispatches
+// like boundary: the ones from boundary up to (but excluding) the next boundary.
+type guardSymbol[S comparable] struct {
+	boundarySet int
+	boundary    S
+}
+
+// A 'minimizeArc' is a transition on the symbol with index symbol from the live state with index source.
+type minimizeArc struct {
+	symbol int
+	source int
+}
+
+func newMinimizer[S comparable, V any](d *Dfa[S, V]) *minimizer[S, V] {
+	m := &minimizer[S, V]{
+		dfa:          d,
+		index:        make(map[int]int),
+		alphabet:     make(map[S]int),
+		guardSymbols: make(map[guardSymbol[S]]int),
+	}
+
+	for _, state := range findLiveStates(d) {
+		m.index[state.id] = len(m.live)
+		m.live = append(m.live, state)
+		m.boundarySet = append(m.boundarySet, m.boundarySetOf(state.guard))
+	}
+
+	m.inverse = make([][]minimizeArc, len(m.live))
+
+	for source, state := range m.live {
+		for symbol, next := range state.transitions {
+			if _, ok := m.alphabet[symbol]; !ok {
+				m.alphabet[symbol] = len(m.alphabet)
+			}
+
+			m.addArc(source, m.alphabet[symbol], next)
+		}
+	}
+
+	numSymbols := len(m.alphabet)
+
+	// NOTE: A guard dispatches the symbols without a concrete transition, so those are transitions as well.
+	for source, state := range m.live {
+		if m.boundarySet[source] < 0 {
+			continue
+		}
+
+		for symbol, symbolIdx := range m.alphabet {
+			if _, ok := state.transitions[symbol]; !ok {
+				m.addArc(source, symbolIdx, state.guard.outgoingFor(symbol))
+			}
+		}
+
+		for _, boundary := range state.guard.boundaries {
+			key := guardSymbol[S]{boundarySet: m.boundarySet[source], boundary: boundary}
+			symbolIdx, ok := m.guardSymbols[key]
+
+			if !ok {
+				symbolIdx = numSymbols
+				m.guardSymbols[key] = symbolIdx
+				numSymbols++
+			}
+
+			m.addArc(source, symbolIdx, state.guard.outgoingFor(boundary))
+		}
+	}
+
+	m.buckets = make([][]int, numSymbols)
+	m.partition = newPartition(len(m.live), m.initialBlockKey)
+
+	return m
+}
+
+// Adds the transition on the symbol with index symbol from the live state with index source to next (if it's live).
+func (m *minimizer[S, V]) addArc(source, symbol int, next *State[S, V]) {
+	if next == nil {
+		return
+	}
+
+	if target, ok := m.index[next.id]; ok {
+		m.inverse[target] = append(m.inverse[target], minimizeArc{symbol: symbol, source: source})
+	}
+}
+
+// Returns the index in m.boundarySets of the boundaries of g, or -1 if there's no guard or its predicates are opaque.
+// Guards with the same boundaries, in any order, share an index.
+func (m *minimizer[S, V]) boundarySetOf(g *guard[S, V]) int {
+	if g == nil || g.boundaries == nil {
+		return -1
+	}
+
+	boundaries := set.New[S]()
+
+	for _, boundary := range g.boundaries {
+		boundaries.Add(boundary)
+	}
+
+	for idx, other := range m.boundarySets {
+		if other.Len() == boundaries.Len() && hasAll(other, g.boundaries) {
+			return idx
+		}
+	}
+
+	m.boundarySets = append(m.boundarySets, boundaries)
+
+	return len(m.boundarySets) - 1
+}
+
+// Returns true if s holds all the values.
+func hasAll[S comparable](s set.Set[S], values []S) bool {
+	for _, value := range values {
+		if !s.Has(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the states of d that can reach an accepting state, in order of their ID.
+func findLiveStates[S comparable, V any](d *Dfa[S, V]) []*State[S, V] {
+	predecessors := make([][]*State[S, V], len(d.states))
+
+	addPredecessor := func(source, target *State[S, V]) {
+		predecessors[target.id] = append(predecessors[target.id], source)
+	}
+
+	var stack []*State[S, V]
+
+	isLive := make([]bool, len(d.states))
+
+	for _, state := range d.states {
+		for _, next := range state.transitions {
+			addPredecessor(state, next)
+		}
+
+		if state.guard != nil {
+			for _, next := range state.guard.targets {
+				addPredecessor(state, next)
+			}
+		}
+
+		if state.IsAccepting() || state.IsConditional() {
+			isLive[state.id] = true
+			stack = append(stack, state)
+		}
+	}
+
+	for len(stack) > 0 {
+		state := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, predecessor := range predecessors[state.id] {
+			if !isLive[predecessor.id] {
+				isLive[predecessor.id] = true
+				stack = append(stack, predecessor)
+			}
+		}
+	}
+
+	liveStates := make([]*State[S, V], 0, len(d.states))
+
+	for _, state := range d.states {
+		if isLive[state.id] {
+			liveStates = append(liveStates, state)
+		}
+	}
+
+	return liveStates
+}
+
+// Returns the key of the block the live state with index idx belongs to in the initial partition.
+// States with different keys can never be equivalent.
+func (m *minimizer[S, V]) initialBlockKey(idx int) blockKey {
+	state := m.live[idx]
+
+	key := blockKey{
+		acceptIdx:   state.acceptIdx,
+		boundarySet: m.boundarySet[idx],
+		singleton:   -1,
+	}
+
+	for next := range key.conditions {
+		key.conditions[next] = acceptIdxOf(state.AcceptingBefore(nfa.SymbolKind(next)))
+	}
+
+	if state.guard != nil && key.boundarySet < 0 {
+		key.singleton = idx // NOTE: The predicates of the guard are opaque.
+	}
+
+	return key
+}
+
+// Refines the partition until all the states in a block are equivalent.
+func (m *minimizer[S, V]) refine() {
+	p := m.partition
+
+	var symbols []int
+
+	for len(p.worklist) > 0 {
+		splitter := p.worklist[len(p.worklist)-1]
+		p.worklist = p.worklist[:len(p.worklist)-1]
+		p.inWorklist[splitter] = false
+
+		// Group the transitions into the splitter per symbol, since every symbol splits the blocks differently.
+		for _, state := range p.members(splitter) {
+			for _, arc := range m.inverse[state] {
+				if len(m.buckets[arc.symbol]) == 0 {
+					symbols = append(symbols, arc.symbol)
+				}
+
+				m.buckets[arc.symbol] = append(m.buckets[arc.symbol], arc.source)
+			}
+		}
+
+		for _, symbol := range symbols {
+			for _, source := range m.buckets[symbol] {
+				p.mark(source)
+			}
+
+			m.buckets[symbol] = m.buckets[symbol][:0]
+
+			p.split()
+		}
+
+		symbols = symbols[:0]
+	}
+}
+
+// Builds the minimal [Dfa] from the refined partition.
+func (m *minimizer[S, V]) build() *Dfa[S, V] {
+	p := m.partition
+
+	minimal := &Dfa[S, V]{
+		nextStateID: 0,
+	}
+
+	// NOTE: When the start state can't reach an accepting state, it's kept, but without any transitions. Without
+	// assertions, the language is empty. With assertions, the start states after a symbol may still be live.
+	var dead *State[S, V]
+
+	if _, ok := m.index[m.dfa.start.id]; !ok {
+		dead = minimal.newState()
+		minimal.start = dead
+
+		if m.dfa.starts == nil {
+			return minimal
+		}
+	}
+
+	// Map every block to a new state, starting with the block of the start state. The other blocks are ordered by the
+	// lowest ID of the states they contain.
+	stateOfBlock := make(map[int]*State[S, V])
+	representatives := make([]*State[S, V], 0, len(m.live))
+
+	for _, state := range append([]*State[S, V]{m.dfa.start}, m.live...) {
+		idx, ok := m.index[state.id]
+
+		if !ok {
+			continue // NOTE: The start state is dead.
+		}
+
+		block := p.blockOf[idx]
+
+		if _, ok := stateOfBlock[block]; ok {
+			continue
+		}
+
+		newState := minimal.newState()
+		newState.acceptIdx = state.acceptIdx
+		newState.value = state.value
+		newState.trailing = state.trailing
+		newState.conditions = state.conditions
+
+		stateOfBlock[block] = newState
+		representatives = append(representatives, state)
+	}
+
+	targetOf := func(state *State[S, V]) *State[S, V] {
+		idx, ok := m.index[state.id]
+
+		if !ok {
+			return nil
+		}
+
+		return stateOfBlock[p.blockOf[idx]]
+	}
+
+	for _, oldState := range representatives {
+		newState := targetOf(oldState)
+
+		for symbol, next := range oldState.transitions {
+			if target := targetOf(next); target != nil {
+				newState.transitions[symbol] = target
+			}
+		}
+
+		if oldState.guard != nil {
+			newState.guard = &guard[S, V]{
+				predicates: oldState.guard.predicates,
+				boundaries: oldState.guard.boundaries,
+				labels:     oldState.guard.labels,
+				targets:    make(map[uint64]*State[S, V], len(oldState.guard.targets)),
+			}
+
+			for mask, next := range oldState.guard.targets {
+				if target := targetOf(next); target != nil {
+					newState.guard.targets[mask] = target
+				}
+			}
+		}
+	}
+
+	if dead == nil {
+		minimal.start = targetOf(m.dfa.start)
+	}
+
+	// NOTE: The start states that can't reach an accepting state (after a symbol of some kind) share a single state
+	// without any transitions.
+	for _, start := range m.dfa.starts {
+		target := targetOf(start)
+
+		if target == nil {
+			if dead == nil {
+				dead = minimal.newState()
+			}
+
+			target = dead
+		}
+
+		minimal.starts = append(minimal.starts, target)
+	}
+
+	return minimal
+}
+
+// The key of a block in the initial partition of Hopcroft's algorithm.
+type blockKey struct {
+	acceptIdx   int
+	conditions  [nfa.NumSymbolKinds]int // The acceptance index per kind of the next symbol (see [State.AcceptingBefore]).
+	boundarySet int                     // The boundaries of the guard (see [minimizer.boundarySets]) or -1.
+	singleton   int                     // The state that must be kept in a block of its own or -1.
+}
+
+// A 'partition' is a partition of the states 0..n-1 into disjoint blocks that can be refined efficiently.
+//
+// Reasoning:
+// The states are stored in a single slice, grouped per block. Every block occupies a contiguous range of that slice.
+// Marking a state moves it to the front of its block, so splitting a block into its marked and unmarked states only
+// requires moving the boundary of the range.
+type partition struct {
+	elements   []int // The states, grouped per block.
+	location   []int // The position of every state in elements.
+	blockOf    []int // The block of every state.
+	first      []int // The position of the first state of every block in elements.
+	end        []int // The position directly after the last state of every block in elements.
+	marked     []int // The number of marked states of every block.
+	touched    []int // The blocks that contain at least one marked state.
+	worklist   []int // The blocks that still need to be used as a splitter.
+	inWorklist []bool
+}
+
+// Creates a new partition of the states 0..n-1, where states with the same key end up in the same block.
+func newPartition(n int, keyOf func(int) blockKey) *partition {
+	p := &partition{
+		elements: make([]int, 0, n),
+		location: make([]int, n),
+		blockOf:  make([]int, n),
+	}
+
+	var keys []blockKey
+
+	membersOfKey := make(map[blockKey][]int)
+
+	for state := range n {
+		key := keyOf(state)
+
+		if _, ok := membersOfKey[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		membersOfKey[key] = append(membersOfKey[key], state)
+	}
+
+	for _, key := range keys {
+		block := len(p.first)
+
+		p.first = append(p.first, len(p.elements))
+
+		for _, state := range membersOfKey[key] {
+			p.location[state] = len(p.elements)
+			p.blockOf[state] = block
+			p.elements = append(p.elements, state)
+		}
+
+		p.end = append(p.end, len(p.elements))
+		p.marked = append(p.marked, 0)
+		p.worklist = append(p.worklist, block)
+		p.inWorklist = append(p.inWorklist, true)
+	}
+
+	return p
+}
+
+// Returns the states in block.
+func (p *partition) members(block int) []int {
+	return p.elements[p.first[block]:p.end[block]]
+}
+
+// Marks state by moving it to the front of its block.
+func (p *partition) mark(state int) {
+	block := p.blockOf[state]
+	position := p.location[state]
+	boundary := p.first[block] + p.marked[block]
+
+	if position < boundary {
+		return // NOTE: The state is already marked.
+	}
+
+	other := p.elements[boundary]
+
+	p.elements[boundary], p.elements[position] = state, other
+	p.location[state], p.location[other] = boundary, position
+
+	if p.marked[block] == 0 {
+		p.touched = append(p.touched, block)
+	}
+
+	p.marked[block]++
+}
+
+// Splits every touched block into its marked and unmarked states and clears all the marks.
+func (p *partition) split() {
+	for _, block := range p.touched {
+		count := p.marked[block]
+		p.marked[block] = 0
+
+		if count == p.end[block]-p.first[block] {
+			continue // NOTE: All the states are marked, so the block isn't split.
+		}
+
+		newBlock := len(p.first)
+
+		p.first = append(p.first, p.first[block])
+		p.end = append(p.end, p.first[block]+count)
+		p.marked = append(p.marked, 0)
+		p.inWorklist = append(p.inWorklist, false)
+		p.first[block] += count
+
+		for _, state := range p.members(newBlock) {
+			p.blockOf[state] = newBlock
+		}
+
+		switch {
+		case p.inWorklist[block]:
+			p.push(newBlock)
+		case count <= p.end[block]-p.first[block]:
+			p.push(newBlock)
+		default:
+			p.push(block)
+		}
+	}
+
+	p.touched = p.touched[:0]
+}
+
+// Adds block to the worklist.
+func (p *partition) push(block int) {
+	p.worklist = append(p.worklist, block)
+	p.inWorklist[block] = true
+}
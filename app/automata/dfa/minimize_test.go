@@ -0,0 +1,278 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns the accepting value of the [dfa.State] reached by consuming input or false if input is NOT accepted.
+func run[S comparable, V any](machine *dfa.Dfa[S, V], input []S) (V, bool) {
+	var zero V
+
+	state := machine.Start()
+
+	for _, symbol := range input {
+		if state = state.OutgoingFor(symbol); state == nil {
+			return zero, false
+		}
+	}
+
+	if !state.IsAccepting() {
+		return zero, false
+	}
+
+	return state.AcceptValue(), true
+}
+
+// UT: Minimize a 'Dfa'.
+func TestMinimize(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When states are equivalent, they are merged.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		// A single rule matching "ab" or "cb". The states after 'a' and 'c' are equivalent, as are the ones after "ab"
+		// and "cb".
+		nMachine := nfa.New[rune, string]()
+		end := nMachine.NewState()
+
+		nMachine.ConnectEpsilon(nMachine.Add(nMachine.Add(nMachine.Start(), 'a'), 'b'), end)
+		nMachine.ConnectEpsilon(nMachine.Add(nMachine.Add(nMachine.Start(), 'c'), 'b'), end)
+		nMachine.AddAcceptingEpsilonTransition(end, "AB")
+
+		// Act.
+		minimal, removed := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		// Assert.
+		assert.Equalf(t, removed, 2, "\n\n"+
+			"UT Name:  When states are equivalent, they are merged.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 2, removed)
+
+		assert.Equalf(t, minimal.Start().OutgoingFor('a'), minimal.Start().OutgoingFor('c'), "\n\n"+
+			"UT Name:  When states are equivalent, the transitions lead to the merged state.\n"+
+			"\033[32mExpected: Same State pointer.\033[0m\n"+
+			"\033[31mActual:   Different.\033[0m\n\n")
+	})
+
+	t.Run("When states produce different accepting values, they are NOT merged.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[rune, string]()
+
+		nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'a'), 'b'), "AB")
+		nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'c'), 'b'), "CB")
+
+		// Act.
+		minimal, removed := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		// Assert.
+		assert.Equalf(t, removed, 0, "\n\n"+
+			"UT Name:  When states produce different accepting values, they are NOT merged.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 0, removed)
+
+		for input, want := range map[string]string{"ab": "AB", "cb": "CB"} {
+			got, _ := run(minimal, []rune(input))
+
+			assert.Equalf(t, got, want, "\n\n"+
+				"UT Name:  When states produce different accepting values, the values are preserved.\n"+
+				"\033[32mExpected: %s.\033[0m\n"+
+				"\033[31mActual:   %s.\033[0m\n\n", want, got)
+		}
+	})
+
+	t.Run("When a state can never accept, it's removed.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[rune, string]()
+
+		nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Start(), 'a'), "A")
+		nMachine.Add(nMachine.Add(nMachine.Start(), 'b'), 'c') // NOTE: Intentionally leading nowhere.
+
+		// Act.
+		minimal, removed := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		// Assert.
+		assert.Equalf(t, removed, 2, "\n\n"+
+			"UT Name:  When a state can never accept, it's removed.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 2, removed)
+
+		assert.Nilf(t, minimal.Start().OutgoingFor('b'), "\n\n"+
+			"UT Name:  When a state can never accept, the transitions leading to it are removed.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   NOT <nil>.\033[0m\n\n")
+	})
+
+	t.Run("When a dfa contains loops and predicates, the language is preserved.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		// (a|b)*c with a predicate for 'c', next to the keyword "ab".
+		nMachine := nfa.New[rune, string]()
+		loop := nMachine.AddEpsilonTransition(nMachine.Start())
+		end := nMachine.NewState()
+
+		nMachine.ConnectEpsilon(nMachine.Add(loop, 'a'), loop)
+		nMachine.ConnectEpsilon(nMachine.Add(loop, 'b'), loop)
+		nMachine.AddPredicateTransition(loop, end, func(r rune) bool { return r == 'c' })
+		nMachine.AddAcceptingEpsilonTransition(end, "STAR")
+		nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'a'), 'b'), "AB")
+
+		original := dfa.FromNfa(nMachine)
+
+		// Act.
+		minimal, _ := dfa.Minimize(original)
+
+		// Assert.
+		for _, input := range []string{"", "a", "ab", "abc", "c", "bbbac", "abab", "abca", "d"} {
+			want, wantOk := run(original, []rune(input))
+			got, gotOk := run(minimal, []rune(input))
+
+			assert.Truef(t, got == want && gotOk == wantOk, "\n\n"+
+				"UT Name:  When a dfa contains loops and predicates, the language is preserved (%q).\n"+
+				"\033[32mExpected: %q (%t).\033[0m\n"+
+				"\033[31mActual:   %q (%t).\033[0m\n\n", input, want, wantOk, got, gotOk)
+		}
+	})
+
+	t.Run("When states have guards with the same boundaries and equivalent targets, they are merged.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		// A single rule matching 1 or 2, followed by a symbol from 20 up to 29. The states after 1 and 2 both dispatch
+		// that class to the same state.
+		nMachine := nfa.New[int, string]()
+		end := nMachine.AddAcceptingEpsilonTransition(nMachine.NewState(), "TWENTIES")
+
+		nMachine.AddClassTransition(nMachine.Add(nMachine.Start(), 1), end, decade(2))
+		nMachine.AddClassTransition(nMachine.Add(nMachine.Start(), 2), end, decade(2))
+
+		original := dfa.FromNfa(nMachine)
+
+		// Act.
+		minimal, removed := dfa.Minimize(original)
+
+		// Assert.
+		assert.Equalf(t, removed, 1, "\n\n"+
+			"UT Name:  When states have guards with the same boundaries and equivalent targets, they are merged.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 1, removed)
+
+		for _, input := range [][]int{{1, 20}, {2, 29}, {1, 19}, {2, 30}, {1}, {3, 25}} {
+			want, wantOk := run(original, input)
+			got, gotOk := run(minimal, input)
+
+			assert.Truef(t, got == want && gotOk == wantOk, "\n\n"+
+				"UT Name:  When states with guards are merged, the language is preserved (%v).\n"+
+				"\033[32mExpected: %q (%t).\033[0m\n"+
+				"\033[31mActual:   %q (%t).\033[0m\n\n", input, want, wantOk, got, gotOk)
+		}
+	})
+
+	t.Run("When states have guards that dispatch a class to different targets, they are NOT merged.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[int, string]()
+		end := nMachine.AddAcceptingEpsilonTransition(nMachine.NewState(), "END")
+
+		nMachine.AddClassTransition(nMachine.Add(nMachine.Start(), 1), end, decade(2))
+		nMachine.AddClassTransition(nMachine.Add(nMachine.Start(), 2), nMachine.AddAcceptingEpsilonTransition(
+			nMachine.NewState(), "OTHER"), decade(2))
+
+		// Act.
+		minimal, removed := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		// Assert.
+		assert.Falsef(t, minimal.Start().OutgoingFor(1) == minimal.Start().OutgoingFor(2), "\n\n"+
+			"UT Name:  When states have guards that dispatch a class to different targets, they are NOT merged.\n"+
+			"\033[32mExpected: Different State pointers.\033[0m\n"+
+			"\033[31mActual:   Same (%d states removed).\033[0m\n\n", removed)
+	})
+
+	t.Run("When only the start state after a symbol can accept, that start state is kept.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		// \Ba: at the start of the input, 'a' is preceded by a word boundary, so only the start after a word can accept.
+		nMachine := nfa.New[rune, string]()
+		inside := nMachine.NewState()
+
+		nMachine.AddAssertion(nMachine.Start(), inside, nfa.NoWordBoundary)
+		nMachine.AddAcceptingEpsilonTransition(nMachine.Add(inside, 'a'), "A")
+
+		// Act.
+		minimal, _ := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		// Assert.
+		got := minimal.StartAfter(nfa.KindWord).OutgoingFor('a')
+
+		assert.Truef(t, got != nil && got.IsAccepting(), "\n\n"+
+			"UT Name:  When only the start state after a word can accept, 'a' is accepted after a word.\n"+
+			"\033[32mExpected: accepting state.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", got)
+
+		assert.Nilf(t, minimal.StartAfter(nfa.KindNone).OutgoingFor('a'), "\n\n"+
+			"UT Name:  When only the start state after a word can accept, 'a' is rejected at the start of the input.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   NOT <nil>.\033[0m\n\n")
+	})
+}
+
+var benchmarkMinimizeOutput *dfa.Dfa[int, int] // Output of the benchmark(s).
+
+// Benchmark(s): Minimize a 'Dfa' built from many keywords sharing their suffix.
+func BenchmarkMinimize_10(b *testing.B)   { benchmarkMinimize(10, b) }
+func BenchmarkMinimize_100(b *testing.B)  { benchmarkMinimize(100, b) }
+func BenchmarkMinimize_1000(b *testing.B) { benchmarkMinimize(1_000, b) }
+
+func benchmarkMinimize(count int, b *testing.B) {
+	nMachine := nfa.New[int, int]()
+	end := nMachine.NewState()
+
+	for i := 0; i < count; i++ {
+		nMachine.ConnectEpsilon(nMachine.Add(nMachine.Add(nMachine.Add(nMachine.Start(), i), -1), -2), end)
+	}
+
+	nMachine.AddAcceptingEpsilonTransition(end, 1)
+
+	dMachine := dfa.FromNfa(nMachine)
+
+	b.ResetTimer()
+	for b.Loop() {
+		benchmarkMinimizeOutput, _ = dfa.Minimize(dMachine)
+	}
+}
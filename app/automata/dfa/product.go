@@ -0,0 +1,436 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/collections/queue"
+)
+
+// CombineFunc decides the accepting value of a state of a product of two [Dfa]s, given the states of both machines the
+// product state was built from. The state of the second machine is nil if that machine can't accept anymore.
+//
+// The value must only depend on the acceptance indexes and the accepting values of both states. When no CombineFunc is
+// given, a product state has the acceptance index of the state of the first machine. Otherwise, every distinct pair of
+// acceptance indexes gets its own acceptance index, ordered by the index of the first machine and then by the index of
+// the second one, so that [Minimize] never merges states with a different combined value.
+type CombineFunc[S comparable, V any] func(a, b *State[S, V]) V
+
+// Intersect returns a [Dfa] accepting the inputs that are accepted by both a and b.
+//
+// The accepting value of a product state is decided by combine. When combine is nil, the value of the state of a is
+// used. See [CombineFunc] for the acceptance index.
+// Panics if either machine has assertions (see [Dfa.HasAssertions]).
+func Intersect[S comparable, V any](a, b *Dfa[S, V], combine CombineFunc[S, V]) *Dfa[S, V] {
+	return buildProduct(a, b, combine,
+		func(p, q *State[S, V]) bool { return p != nil && q != nil },
+		func(p, q *State[S, V]) bool { return p.IsAccepting() && q.IsAccepting() },
+	)
+}
+
+// Difference returns a [Dfa] accepting the inputs that are accepted by a, but NOT by b.
+//
+// The accepting value of a product state is decided by combine. When combine is nil, the value of the state of a is
+// used. See [CombineFunc] for the acceptance index.
+// Panics if either machine has assertions (see [Dfa.HasAssertions]).
+func Difference[S comparable, V any](a, b *Dfa[S, V], combine CombineFunc[S, V]) *Dfa[S, V] {
+	return buildProduct(a, b, combine,
+		func(p, q *State[S, V]) bool { return p != nil },
+		func(p, q *State[S, V]) bool { return p.IsAccepting() && (q == nil || !q.IsAccepting()) },
+	)
+}
+
+// Complement returns a [Dfa] accepting every sequence of symbols of alphabet that's NOT accepted by d.
+// The accepting states of the result have value as their accepting value and 0 as their acceptance index.
+//
+// Symbols outside alphabet are never accepted, even if d accepts them (e.g., through a guard).
+func Complement[S comparable, V any](d *Dfa[S, V], alphabet []S, value V) *Dfa[S, V] {
+	universal := &Dfa[S, V]{}
+	universal.start = universal.newAcceptingState(0, value)
+
+	for _, symbol := range alphabet {
+		universal.start.transitions[symbol] = universal.start
+	}
+
+	return Difference(universal, d, nil)
+}
+
+// A 'statePair' is a pair of states of two [Dfa]s, where nil represents a state that can't accept anymore.
+type statePair[S comparable, V any] struct {
+	a *State[S, V]
+	b *State[S, V]
+}
+
+// A 'productBuilder' builds the product of two [Dfa]s.
+//
+// Every state of the product corresponds to a pair of states of both machines. Missing transitions lead to nil, which
+// is kept as a regular member of a pair, so that operations like [Difference] can continue when only one of the
+// machines can still accept.
+type productBuilder[S comparable, V any] struct {
+	dfa       *Dfa[S, V]
+	states    map[statePair[S, V]]*State[S, V]
+	accepts   map[*State[S, V]]acceptPair // The acceptance indexes that every accepting state was built from.
+	queue     *queue.Queue[statePair[S, V]]
+	combine   CombineFunc[S, V]
+	live      func(a, b *State[S, V]) bool // Reports whether the pair is part of the product.
+	accepting func(a, b *State[S, V]) bool // Reports whether the pair (which is live) is accepting.
+}
+
+// Returns the product of a and b (see [productBuilder]).
+func buildProduct[S comparable, V any](
+	a, b *Dfa[S, V], combine CombineFunc[S, V], live, accepting func(a, b *State[S, V]) bool,
+) *Dfa[S, V] {
+	if a.HasAssertions() || b.HasAssertions() {
+		panic("product: machines with assertions aren't supported")
+	}
+
+	builder := &productBuilder[S, V]{
+		dfa:       &Dfa[S, V]{},
+		states:    make(map[statePair[S, V]]*State[S, V]),
+		accepts:   make(map[*State[S, V]]acceptPair),
+		queue:     queue.New[statePair[S, V]](),
+		combine:   combine,
+		live:      live,
+		accepting: accepting,
+	}
+
+	builder.dfa.start = builder.ensureState(statePair[S, V]{a: a.start, b: b.start})
+
+	for builder.queue.Len() > 0 {
+		pair, _ := builder.queue.Dequeue()
+		from := builder.states[pair]
+
+		for _, symbol := range pairSymbols(pair) {
+			target := statePair[S, V]{a: outgoingFor(pair.a, symbol), b: outgoingFor(pair.b, symbol)}
+
+			if builder.live(target.a, target.b) {
+				from.transitions[symbol] = builder.ensureState(target)
+			}
+		}
+
+		from.guard = builder.buildGuard(pair)
+	}
+
+	if combine != nil {
+		builder.renumberAccepts()
+	}
+
+	return builder.dfa
+}
+
+// An 'acceptPair' holds the acceptance indexes of the states of a [statePair], where -1 is used for a missing state or
+// a state that isn't accepting.
+type acceptPair struct {
+	a int
+	b int
+}
+
+// Gives every accepting state of the product the rank of its [acceptPair] as acceptance index (see [CombineFunc]).
+func (builder *productBuilder[S, V]) renumberAccepts() {
+	ranks := make(map[acceptPair]int)
+
+	for _, pair := range builder.accepts {
+		ranks[pair] = 0
+	}
+
+	pairs := slices.SortedFunc(maps.Keys(ranks), func(x, y acceptPair) int {
+		return cmp.Or(cmp.Compare(x.a, y.a), cmp.Compare(x.b, y.b))
+	})
+
+	for rank, pair := range pairs {
+		ranks[pair] = rank
+	}
+
+	for state, pair := range builder.accepts {
+		state.acceptIdx = ranks[pair]
+	}
+}
+
+// Returns the [State] of the product for pair, adding it if it hasn't been seen yet.
+func (builder *productBuilder[S, V]) ensureState(pair statePair[S, V]) *State[S, V] {
+	if state, ok := builder.states[pair]; ok {
+		return state
+	}
+
+	state := builder.dfa.newState()
+
+	if builder.accepting(pair.a, pair.b) {
+		state.acceptIdx = pair.a.acceptIdx
+		state.value = pair.a.value
+
+		if builder.combine != nil {
+			state.value = builder.combine(pair.a, pair.b)
+			builder.accepts[state] = acceptPair{a: pair.a.acceptIdx, b: acceptIdxOf(pair.b)}
+		}
+	}
+
+	builder.states[pair] = state
+	builder.queue.Enqueue(pair)
+
+	return state
+}
+
+// Returns the guard of the product state for pair or nil if none of its minterms leads to a state of the product.
+//
+// The predicates of the guard are the ones of the guard of a, followed by the ones of the guard of b. As a result, the
+// minterm of a symbol is the minterm of a, combined with the minterm of b shifted by the number of predicates of a.
+// Symbols with a concrete transition in either state never reach the guard, since those are part of the concrete
+// transitions of the product state.
+func (builder *productBuilder[S, V]) buildGuard(pair statePair[S, V]) *guard[S, V] {
+	guardA, guardB := guardOf(pair.a), guardOf(pair.b)
+
+	if guardA == nil && guardB == nil {
+		return nil
+	}
+
+	g := &guard[S, V]{
+		targets: make(map[uint64]*State[S, V]),
+	}
+
+	// NOTE: The minterm 0 (no predicate holds) leads to nil, which is a valid member of a pair.
+	var targetsA, targetsB map[uint64]*State[S, V]
+
+	masksA, masksB, shift := []uint64{0}, []uint64{0}, 0
+
+	if guardA != nil {
+		g.predicates = append(g.predicates, guardA.predicates...)
+		g.labels = append(g.labels, guardA.predicateLabels()...)
+		targetsA = guardA.targets
+		masksA = append(masksA, guardA.masks()...)
+		shift = len(guardA.predicates)
+	}
+
+	if guardB != nil {
+		g.predicates = append(g.predicates, guardB.predicates...)
+		g.labels = append(g.labels, guardB.predicateLabels()...)
+		targetsB = guardB.targets
+		masksB = append(masksB, guardB.masks()...)
+	}
+
+	g.boundaries = productBoundaries(guardA, guardB)
+
+	if len(g.predicates) > 64 {
+		panic("product: too many predicates leaving a single state")
+	}
+
+	for _, maskA := range masksA {
+		for _, maskB := range masksB {
+			target := statePair[S, V]{a: targetsA[maskA], b: targetsB[maskB]}
+
+			if (maskA == 0 && maskB == 0) || !builder.live(target.a, target.b) {
+				continue
+			}
+
+			g.targets[maskA|maskB<<shift] = builder.ensureState(target)
+		}
+	}
+
+	// NOTE: A guard without targets doesn't dispatch any symbol, so the product state doesn't need one.
+	if len(g.targets) == 0 {
+		return nil
+	}
+
+	return g
+}
+
+// Returns the boundaries of the guard of a product state or nil if the guard of either state is opaque.
+func productBoundaries[S comparable, V any](guardA, guardB *guard[S, V]) []S {
+	if (guardA != nil && guardA.boundaries == nil) || (guardB != nil && guardB.boundaries == nil) {
+		return nil
+	}
+
+	var boundaries []S
+
+	for _, g := range []*guard[S, V]{guardA, guardB} {
+		if g != nil {
+			boundaries = append(boundaries, g.boundaries...)
+		}
+	}
+
+	return boundaries
+}
+
+// Returns the symbols with a concrete transition in either state of pair, sorted by sortSymbols.
+func pairSymbols[S comparable, V any](pair statePair[S, V]) []S {
+	var symbols []S
+
+	if pair.a != nil {
+		symbols = pair.a.Symbols()
+	}
+
+	if pair.b != nil {
+		for symbol := range pair.b.transitions {
+			if pair.a == nil {
+				symbols = append(symbols, symbol)
+
+				continue
+			}
+
+			if _, ok := pair.a.transitions[symbol]; !ok {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+
+	sortSymbols(symbols)
+
+	return symbols
+}
+
+// Returns the target of state for symbol or nil if state is nil or has no transition for symbol.
+func outgoingFor[S comparable, V any](state *State[S, V], symbol S) *State[S, V] {
+	if state == nil {
+		return nil
+	}
+
+	return state.OutgoingFor(symbol)
+}
+
+// Returns the guard of state or nil if state is nil or has no guard.
+func guardOf[S comparable, V any](state *State[S, V]) *guard[S, V] {
+	if state == nil {
+		return nil
+	}
+
+	return state.guard
+}
+
+// Build integrates the language of the DFA into machine, starting from startState, and returns the final state of the
+// constructed part. This makes a [Dfa] usable as a fragment of another automaton (e.g., a scanner.Fragment).
+//
+// Every state of the DFA becomes a state of machine and every accepting state is connected to the returned state with
+// an epsilon transition. The accepting values of the DFA are discarded. The transitions of a guard become transitions
+// that only hold for symbols without a concrete transition and with the minterm of the transition. When the guard is
+// built from classes (see [State.GuardBoundaries]) and the symbols are of a predeclared integer or string type, those
+// are class transitions (see [nfa.Class]), so the boundaries of the guard survive. Otherwise, they're predicate
+// transitions.
+//
+// Panics if the DFA has assertions, since the conditions of its states can't be expressed as assertion transitions.
+func (d *Dfa[S, V]) Build(machine *nfa.Nfa[S, V], startState *nfa.State[S, V]) *nfa.State[S, V] {
+	if d.HasAssertions() {
+		panic("Build: machines with assertions aren't supported")
+	}
+
+	endState := machine.NewState()
+	states := make(map[*State[S, V]]*nfa.State[S, V], len(d.states))
+
+	// NOTE: A fresh state is used for the start state, since the DFA may loop back to it, which must NOT include the
+	// transitions that startState already has.
+	for _, state := range d.states {
+		states[state] = machine.NewState()
+	}
+
+	machine.ConnectEpsilon(startState, states[d.start])
+
+	for _, state := range d.states {
+		from := states[state]
+
+		for symbol, target := range state.transitions {
+			machine.Connect(from, states[target], symbol)
+		}
+
+		if state.IsAccepting() {
+			machine.ConnectEpsilon(from, endState)
+		}
+
+		if classes, ok := guardTargetClasses(state); ok {
+			for _, class := range classes {
+				machine.AddClassTransition(from, states[class.target], class)
+			}
+
+			continue
+		}
+
+		for fn, target := range state.GuardTransitions() {
+			machine.AddPredicateTransition(from, states[target], fn)
+		}
+	}
+
+	return endState
+}
+
+// A 'guardTargetClass' is the [nfa.Class] of the symbols without a concrete transition from state that its guard
+// dispatches to target.
+type guardTargetClass[S comparable, V any] struct {
+	state      *State[S, V]
+	target     *State[S, V]
+	boundaries []S
+}
+
+// Returns a class for every target of the guard of state or false if the guard isn't built from classes or the
+// symbols with a concrete transition can't be excluded from the classes (see [successor]).
+//
+// Reasoning:
+// The classes exclude the symbols with a concrete transition, so a class holds for none of the symbols from such a
+// symbol up to its successor. Those are added to the boundaries of the guard, so the membership of a class only
+// changes at a boundary.
+func guardTargetClasses[S comparable, V any](state *State[S, V]) ([]guardTargetClass[S, V], bool) {
+	boundaries, ok := state.GuardBoundaries()
+
+	if state.guard == nil || !ok {
+		return nil, false
+	}
+
+	boundaries = slices.Clone(boundaries)
+
+	for symbol := range state.transitions {
+		next, ok := successor(symbol)
+
+		if !ok {
+			return nil, false
+		}
+
+		boundaries = append(boundaries, symbol, next)
+	}
+
+	classes := make([]guardTargetClass[S, V], 0, len(state.guard.targets))
+
+	for _, mask := range state.guard.masks() {
+		classes = append(classes, guardTargetClass[S, V]{
+			state:      state,
+			target:     state.guard.targets[mask],
+			boundaries: boundaries,
+		})
+	}
+
+	return classes, true
+}
+
+// Contains reports whether the guard of the state dispatches symbol to the target.
+func (class guardTargetClass[S, V]) Contains(symbol S) bool {
+	if _, ok := class.state.transitions[symbol]; ok {
+		return false
+	}
+
+	return class.state.guard.outgoingFor(symbol) == class.target
+}
+
+// Boundaries returns the boundaries of the guard and the symbols with a concrete transition and their successors.
+func (class guardTargetClass[S, V]) Boundaries() []S { return class.boundaries }
@@ -0,0 +1,228 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns a [dfa.Dfa] accepting non-empty words of symbols for which fn holds, with value as accepting value.
+// When fn is nil, the words are made of the concrete symbols instead.
+func newWordMachine(value string, fn func(rune) bool, symbols ...rune) *dfa.Dfa[rune, string] {
+	nMachine := nfa.New[rune, string]()
+	loop := nMachine.AddEpsilonTransition(nMachine.Start())
+	word := nMachine.NewState()
+
+	for _, r := range symbols {
+		nMachine.Connect(loop, word, r)
+	}
+
+	if fn != nil {
+		nMachine.AddPredicateTransition(loop, word, fn)
+	}
+
+	nMachine.ConnectEpsilon(word, loop)
+	nMachine.AddAcceptingEpsilonTransition(word, value)
+
+	return dfa.FromNfa(nMachine)
+}
+
+// Returns a [dfa.Dfa] accepting the keywords "if" and "in" with value as accepting value.
+func newKeywordMachine(value string) *dfa.Dfa[rune, string] {
+	nMachine := nfa.New[rune, string]()
+
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'i'), 'f'), value)
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'i'), 'n'), value)
+
+	return dfa.FromNfa(nMachine)
+}
+
+// UT: Combine 'Dfa's using boolean operations.
+func TestProduct(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	alphabet := []rune("fin1é")
+	word := newWordMachine("WORD", unicode.IsLetter)
+	short := newWordMachine("SHORT", nil, 'f', 'i', 'n', '1')
+	keyword := newKeywordMachine("KEYWORD")
+
+	for _, tc := range []struct {
+		name    string
+		machine *dfa.Dfa[rune, string]
+		want    func(input []rune) (string, bool)
+	}{
+		{
+			"an intersection",
+			dfa.Intersect(word, short, func(a, b *dfa.State[rune, string]) string {
+				return a.AcceptValue() + "&" + b.AcceptValue()
+			}),
+			func(input []rune) (string, bool) {
+				_, okWord := run(word, input)
+				_, okShort := run(short, input)
+
+				return "WORD&SHORT", okWord && okShort
+			},
+		},
+		{
+			"a difference",
+			dfa.Difference(word, keyword, nil),
+			func(input []rune) (string, bool) {
+				_, okWord := run(word, input)
+				_, okKeyword := run(keyword, input)
+
+				return "WORD", okWord && !okKeyword
+			},
+		},
+		{
+			"a complement",
+			dfa.Complement(word, alphabet, "OTHER"),
+			func(input []rune) (string, bool) {
+				_, okWord := run(word, input)
+
+				return "OTHER", !okWord
+			},
+		},
+	} {
+		forEachInput(alphabet, 4, func(input []rune) {
+			// Act.
+			got, gotOk := run(tc.machine, input)
+
+			// Assert.
+			want, wantOk := tc.want(input)
+
+			if !wantOk {
+				want = ""
+			}
+
+			assert.Truef(t, got == want && gotOk == wantOk, "\n\n"+
+				"UT Name:  When running %s on %q, the result is correct.\n"+
+				"\033[32mExpected: %q (%t).\033[0m\n"+
+				"\033[31mActual:   %q (%t).\033[0m\n\n", tc.name, string(input), want, wantOk, got, gotOk)
+		})
+	}
+}
+
+// UT: Integrate a 'Dfa' into an 'Nfa'.
+func TestDfa_Build(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := dfa.Difference(newWordMachine("WORD", unicode.IsLetter, 'i'), newKeywordMachine("KEYWORD"), nil)
+	nMachine := nfa.New[rune, string]()
+
+	// Act.
+	nMachine.AddAcceptingEpsilonTransition(machine.Build(nMachine, nMachine.Start()), "BUILT")
+
+	// Assert.
+	forEachInput([]rune("fin1é"), 4, func(input []rune) {
+		_, want := run(machine, input)
+		match, ok := nMachine.Match(input)
+		got := ok && match.Length == len(input)
+
+		assert.Equalf(t, got, want, "\n\n"+
+			"UT Name:  When a DFA is integrated into an NFA, the NFA accepts %q when the DFA does.\n"+
+			"\033[32mExpected: %t.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", string(input), want, got)
+	})
+}
+
+// UT: Integrate a 'Dfa' with guards built from classes into an 'Nfa'.
+func TestDfa_Build_Classes(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	// Words of lowercase letters, except the keyword "if", so the guards have concrete transitions next to them.
+	machine := dfa.Difference(newRuleMachine(rule{class: &runeRange{'a', 'z'}, value: 1}),
+		newRuleMachine(rule{literal: "if", value: 2}), nil)
+	nMachine := nfa.New[rune, int]()
+
+	// Act.
+	nMachine.AddAcceptingEpsilonTransition(machine.Build(nMachine, nMachine.Start()), 3)
+
+	// Assert.
+	_, ok := nfa.AlphabetOf(nMachine)
+
+	assert.Truef(t, ok, "\n\n"+
+		"UT Name:  When a DFA with guards built from classes is integrated into an NFA, the NFA only has classes.\n"+
+		"\033[32mExpected: true.\033[0m\n"+
+		"\033[31mActual:   false.\033[0m\n\n")
+
+	forEachInput([]rune("fiz0"), 4, func(input []rune) {
+		_, want := run(machine, input)
+		match, ok := nMachine.Match(input)
+		got := ok && match.Length == len(input)
+
+		assert.Equalf(t, got, want, "\n\n"+
+			"UT Name:  When a DFA with guards built from classes is integrated into an NFA, it accepts %q when the DFA does.\n"+
+			"\033[32mExpected: %t.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", string(input), want, got)
+	})
+}
+
+// UT: Minimize the product of 'Dfa's with combined accepting values.
+func TestProduct_Minimize(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	nMachine := nfa.New[rune, string]()
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Start(), 'x'), "BX")
+	nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Start(), 'y'), "BY")
+
+	product := dfa.Intersect(newWordMachine("A", nil, 'x', 'y'), dfa.FromNfa(nMachine),
+		func(_, b *dfa.State[rune, string]) string { return b.AcceptValue() })
+
+	// Act.
+	minimal, _ := dfa.Minimize(product)
+
+	// Assert.
+	witness, ok := dfa.Equivalent(product, minimal, func(x, y string) bool { return x == y })
+
+	assert.Truef(t, ok, "\n\n"+
+		"UT Name:  When a product with combined accepting values is minimized, the values are preserved.\n"+
+		"\033[32mExpected: <nil>.\033[0m\n"+
+		"\033[31mActual:   %q.\033[0m\n\n", string(witness))
+}
+
+// UT: Complement a 'Dfa' with guards.
+func TestComplement_Guards(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Act.
+	machine := dfa.Complement(newWordMachine("WORD", unicode.IsLetter), []rune("ab1"), "OTHER")
+
+	// Assert.
+	for state := range machine.Reachable() {
+		assert.Falsef(t, state.HasGuard(), "\n\n"+
+			"UT Name:  When a DFA with guards is complemented, states without guard targets don't have a guard.\n"+
+			"\033[32mExpected: false.\033[0m\n"+
+			"\033[31mActual:   true (state %d).\033[0m\n\n", state.ID())
+	}
+}
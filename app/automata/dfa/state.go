@@ -25,24 +25,79 @@
 
 package dfa
 
+import "github.com/kdeconinck/realign/automata/nfa"
+
 // State is a node in a [Dfa].
 type State[S comparable, V any] struct {
 	id          int
 	transitions map[S]*State[S, V]
+	guard       *guard[S, V] // Dispatches symbols without a concrete transition (if any).
 	acceptIdx   int
-	value       V // The accepting value (if any).
+	value       V                       // The accepting value (if any).
+	trailing    *nfa.TrailingContext[S] // The trailing context of the matches of the state (if any).
+	conditions  []*State[S, V]          // The acceptance per kind of the next symbol (nil if it doesn't depend on it).
 }
 
+// ID returns the identifier of the state, which is unique within its [Dfa].
+func (s *State[S, V]) ID() int { return s.id }
+
+// AcceptIdx returns the acceptance index of the state or -1 if the state is NOT accepting.
+//
+// When multiple accepting states of an Nfa are merged into a single [State], the lowest index wins.
+func (s *State[S, V]) AcceptIdx() int { return s.acceptIdx }
+
 // IsAccepting returns true if the state is an accepting state.
 func (s *State[S, V]) IsAccepting() bool { return s.acceptIdx > -1 }
 
+// TrailingContext returns the trailing context of the state or nil if its matches don't end in trailing context.
+// It's taken from the accepting [nfa.State] that the acceptance index of the state was taken from.
+func (s *State[S, V]) TrailingContext() *nfa.TrailingContext[S] { return s.trailing }
+
 // AcceptValue returns the accepting value of the state.
 // If the state is not accepting, it returns the zero value of V.
 func (s *State[S, V]) AcceptValue() V { return s.value }
 
 // OutgoingFor returns the target state for the given symbol, or nil if no transition exists.
+//
+// Transitions on concrete symbols take precedence. Only when there's no such transition, the symbol is dispatched on
+// the predicates (if any) that were leaving the states of the Nfa this state was built from.
 func (s *State[S, V]) OutgoingFor(symbol S) *State[S, V] {
-	return s.transitions[symbol]
+	if target, ok := s.transitions[symbol]; ok {
+		return target
+	}
+
+	if s.guard == nil {
+		return nil
+	}
+
+	return s.guard.outgoingFor(symbol)
+}
+
+// Symbols returns the symbols for which the state has a concrete transition, in no particular order.
+func (s *State[S, V]) Symbols() []S {
+	symbols := make([]S, 0, len(s.transitions))
+
+	for symbol := range s.transitions {
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols
+}
+
+// HasGuard returns true if symbols without a concrete transition are dispatched on predicates.
+// The targets of such a state can only be discovered by calling [State.OutgoingFor].
+func (s *State[S, V]) HasGuard() bool { return s.guard != nil }
+
+// GuardBoundaries returns the symbols where the target of the guard of the state may change: all the symbols from one
+// boundary up to (but excluding) the next one, that don't have a concrete transition, lead to the same target.
+// It returns false if the guard has predicates that aren't built from an [nfa.Class] (and nil, true if the state
+// doesn't have a guard).
+func (s *State[S, V]) GuardBoundaries() ([]S, bool) {
+	if s.guard == nil {
+		return nil, true
+	}
+
+	return s.guard.boundaries, s.guard.boundaries != nil
 }
 
 // Returns a new [State].
@@ -50,11 +105,15 @@ func (d *Dfa[S, V]) newState() *State[S, V] {
 	id := d.nextStateID
 	d.nextStateID++
 
-	return &State[S, V]{
+	state := &State[S, V]{
 		id:          id,
 		transitions: make(map[S]*State[S, V]),
 		acceptIdx:   -1,
 	}
+
+	d.states = append(d.states, state)
+
+	return state
 }
 
 // Returns a new accepting [State].
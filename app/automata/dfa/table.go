@@ -0,0 +1,243 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// DeadState is the state of a [Table] that's reached when no transition exists. It never accepts and never leaves.
+const DeadState = 0
+
+// Table is a compiled form of a [Dfa] over bytes, which matches input without a single map lookup.
+//
+// The states are numbered densely: [DeadState] is 0, the start state is 1 and the other states follow in
+// breadth-first order. The bytes are partitioned into classes of bytes that no state distinguishes, and the
+// transitions of every state are stored in a row with a target per class. All the rows are stored contiguously, so
+// following a transition is a lookup of the class of the byte and a single indexing operation. The acceptance of the
+// states is stored in a flat table, indexed by state.
+type Table[V any] struct {
+	classes     [256]uint8 // The class of every byte.
+	stride      int        // The number of classes (and the length of a row).
+	transitions []int32    // The targets per state and per class, stored row by row.
+	acceptIdx   []int32    // The acceptance index per state (-1 if the state isn't accepting).
+	values      []V        // The accepting value per state.
+}
+
+// Compile compiles d into a [Table].
+//
+// Predicate transitions are resolved once for every class of the alphabet of d (see [AlphabetOf]), or for every byte
+// if that alphabet can't be partitioned, so they don't cost anything while matching. A DFA with assertions (see
+// [Dfa.HasAssertions]) or with trailing context can't be compiled, since the matches of a [Table] don't depend on the
+// symbols around them.
+func Compile[V any](d *Dfa[byte, V]) (*Table[V], error) {
+	if d.start == nil {
+		return nil, errors.New("dfa: can't compile a DFA without a start state")
+	}
+
+	if d.HasAssertions() {
+		return nil, errors.New("dfa: can't compile a DFA with assertions")
+	}
+
+	ids := map[*State[byte, V]]int32{d.start: 1}
+	order := []*State[byte, V]{nil, d.start}
+	classOf, members := byteClasses(d)
+	targets := make([]*State[byte, V], len(members))
+
+	var rows [][256]int32
+
+	table := &Table[V]{}
+
+	// NOTE: The states are numbered while they're compiled, so order grows while it's traversed.
+	for id := 0; id < len(order); id++ {
+		state := order[id]
+
+		if state == nil {
+			rows = append(rows, [256]int32{})
+			table.add(-1, *new(V))
+
+			continue
+		}
+
+		if state.trailing != nil {
+			return nil, fmt.Errorf("dfa: can't compile state %d, since it has trailing context", state.id)
+		}
+
+		var row [256]int32
+
+		for class, member := range members {
+			targets[class] = state.OutgoingFor(member)
+		}
+
+		for symbol := range row {
+			target := targets[classOf[symbol]]
+
+			if target == nil {
+				continue
+			}
+
+			if _, ok := ids[target]; !ok {
+				ids[target] = int32(len(order))
+				order = append(order, target)
+			}
+
+			row[symbol] = ids[target]
+		}
+
+		rows = append(rows, row)
+		table.add(int32(state.acceptIdx), state.value)
+	}
+
+	table.compress(rows)
+
+	return table, nil
+}
+
+// Returns the class of every byte and a member of every class, for the classes of the alphabet of d (see [AlphabetOf]).
+// If that alphabet can't be partitioned, every byte has a class of its own.
+func byteClasses[V any](d *Dfa[byte, V]) ([256]int, []byte) {
+	var classOf [256]int
+	var members []byte
+
+	alphabet, ok := AlphabetOf(d)
+	indices := make(map[int]int)
+
+	for symbol := range 256 {
+		class := symbol
+
+		if ok {
+			class = alphabet.ClassOf(byte(symbol))
+		}
+
+		idx, found := indices[class]
+
+		if !found {
+			idx = len(members)
+			indices[class] = idx
+			members = append(members, byte(symbol))
+		}
+
+		classOf[symbol] = idx
+	}
+
+	return classOf, members
+}
+
+// Appends the acceptance of a state to the table.
+func (t *Table[V]) add(acceptIdx int32, value V) {
+	t.acceptIdx = append(t.acceptIdx, acceptIdx)
+	t.values = append(t.values, value)
+}
+
+// Partitions the bytes into classes of bytes that lead to the same target in every row of rows, and stores a row with
+// a target per class for every row of rows.
+func (t *Table[V]) compress(rows [][256]int32) {
+	classByColumn := make(map[string]uint8)
+
+	var representatives []int
+
+	for symbol := range 256 {
+		column := make([]byte, 0, 4*len(rows))
+
+		for _, row := range rows {
+			column = binary.LittleEndian.AppendUint32(column, uint32(row[symbol]))
+		}
+
+		class, ok := classByColumn[string(column)]
+
+		if !ok {
+			class = uint8(len(representatives))
+			classByColumn[string(column)] = class
+			representatives = append(representatives, symbol)
+		}
+
+		t.classes[symbol] = class
+	}
+
+	t.stride = len(representatives)
+	t.transitions = make([]int32, 0, len(rows)*t.stride)
+
+	for _, row := range rows {
+		for _, symbol := range representatives {
+			t.transitions = append(t.transitions, row[symbol])
+		}
+	}
+}
+
+// NumStates returns the number of states of the table (including [DeadState]).
+func (t *Table[V]) NumStates() int { return len(t.acceptIdx) }
+
+// NumClasses returns the number of classes that the bytes are partitioned into.
+func (t *Table[V]) NumClasses() int { return t.stride }
+
+// Start returns the start state of the table.
+func (t *Table[V]) Start() int32 { return 1 }
+
+// Next returns the state that's reached from state by consuming symbol ([DeadState] if no transition exists).
+func (t *Table[V]) Next(state int32, symbol byte) int32 {
+	return t.transitions[int(state)*t.stride+int(t.classes[symbol])]
+}
+
+// AcceptIdx returns the acceptance index of state or -1 if state is NOT accepting.
+func (t *Table[V]) AcceptIdx(state int32) int { return int(t.acceptIdx[state]) }
+
+// AcceptValue returns the accepting value of state.
+// If state is not accepting, it returns the zero value of V.
+func (t *Table[V]) AcceptValue(state int32) V { return t.values[state] }
+
+// Match matches the longest prefix of input, just like [nfa.Nfa.Match] does.
+func (t *Table[V]) Match(input []byte) (nfa.Match[V], bool) {
+	return t.MatchAt(input, 0)
+}
+
+// MatchAt matches the longest prefix of input[start:], just like [nfa.Nfa.MatchAt] does.
+func (t *Table[V]) MatchAt(input []byte, start int) (nfa.Match[V], bool) {
+	var match nfa.Match[V]
+	found := false
+
+	state := t.Start()
+
+	for idx := start; ; idx++ {
+		if acceptIdx := t.acceptIdx[state]; acceptIdx > -1 {
+			match = nfa.Match[V]{Value: t.values[state], AcceptIdx: int(acceptIdx), Length: idx - start}
+			found = true
+		}
+
+		if idx == len(input) {
+			break
+		}
+
+		if state = t.transitions[int(state)*t.stride+int(t.classes[input[idx]])]; state == DeadState {
+			break
+		}
+	}
+
+	return match, found
+}
@@ -0,0 +1,205 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns an 'Nfa' over bytes that accepts the keyword "if" (1), identifiers (2) and numbers (3).
+func newByteMachine() *nfa.Nfa[byte, int] {
+	isLetter := func(b byte) bool { return b >= 'a' && b <= 'z' }
+	isDigit := func(b byte) bool { return b >= '0' && b <= '9' }
+
+	machine := nfa.New[byte, int]()
+	s0 := machine.Start()
+
+	machine.AddAcceptingEpsilonTransition(machine.Add(machine.Add(s0, 'i'), 'f'), 1)
+
+	ident := machine.NewState()
+	machine.AddPredicateTransition(s0, ident, isLetter)
+	machine.AddPredicateTransition(ident, ident, func(b byte) bool { return isLetter(b) || isDigit(b) })
+	machine.AddAcceptingEpsilonTransition(ident, 2)
+
+	number := machine.NewState()
+	machine.AddPredicateTransition(s0, number, isDigit)
+	machine.AddPredicateTransition(number, number, isDigit)
+	machine.AddAcceptingEpsilonTransition(number, 3)
+
+	return machine
+}
+
+// UT: Compile a 'Dfa' over bytes into a 'Table'.
+func TestCompile(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When a DFA is compiled, the table matches exactly what the NFA matches.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := newByteMachine()
+		minimal, _ := dfa.Minimize(dfa.FromNfa(nMachine))
+
+		// Act.
+		table, err := dfa.Compile(minimal)
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When a DFA is compiled, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		for _, input := range allStrings("if9 ", 5) {
+			for start := range len(input) + 1 {
+				want, wantOk := nMachine.MatchAt([]byte(input), start)
+				got, gotOk := table.MatchAt([]byte(input), start)
+
+				assert.Equalf(t, got == want && gotOk == wantOk, true, "\n\n"+
+					"UT Name:  When a DFA is compiled, the table matches %q at offset %d like the 'Nfa'.\n"+
+					"\033[32mExpected: %v (%t).\033[0m\n"+
+					"\033[31mActual:   %v (%t).\033[0m\n\n", input, start, want, wantOk, got, gotOk)
+			}
+		}
+	})
+
+	t.Run("When a DFA is compiled, the bytes that no state distinguishes share a class.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		table, _ := dfa.Compile(dfa.FromNfa(newByteMachine()))
+
+		// Assert.
+		assert.Equalf(t, table.NumClasses(), 5, "\n\n"+
+			"UT Name:  When a DFA is compiled, the bytes that no state distinguishes share a class.\n"+
+			"\033[32mExpected: 5 ('i', 'f', the other letters, the digits and the other bytes).\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", table.NumClasses())
+	})
+
+	t.Run("When no transition exists, the dead state is reached.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		table, _ := dfa.Compile(dfa.FromNfa(newByteMachine()))
+
+		// Act.
+		state := table.Next(table.Start(), ' ')
+
+		// Assert.
+		assert.Equalf(t, state, int32(dfa.DeadState), "\n\n"+
+			"UT Name:  When no transition exists, the dead state is reached.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", dfa.DeadState, state)
+	})
+
+	t.Run("When a DFA has assertions, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[byte, int]()
+		anchored := nMachine.NewState()
+
+		nMachine.AddAssertion(nMachine.Start(), anchored, nfa.BeginText)
+		nMachine.AddAcceptingEpsilonTransition(nMachine.Add(anchored, 'a'), 1)
+
+		// Act.
+		_, err := dfa.Compile(dfa.FromNfa(nMachine))
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When a DFA has assertions, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+
+	t.Run("When a state has trailing context, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[byte, int]()
+		accepting := nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Start(), 'a'), 1)
+
+		nMachine.SetTrailingContext(accepting, &nfa.TrailingContext[byte]{HeadLen: 1, TailLen: -1})
+
+		// Act.
+		_, err := dfa.Compile(dfa.FromNfa(nMachine))
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When a state has trailing context, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+}
+
+var benchmarkTableOutput bool // Output of the benchmark(s).
+
+// Benchmark(s): Match input against a 'Dfa' and against its 'Table'.
+func BenchmarkDfa_Match_1000(b *testing.B)     { benchmarkDfa_Match(1_000, b) }
+func BenchmarkDfa_Match_100000(b *testing.B)   { benchmarkDfa_Match(100_000, b) }
+func BenchmarkTable_Match_1000(b *testing.B)   { benchmarkTable_Match(1_000, b) }
+func BenchmarkTable_Match_100000(b *testing.B) { benchmarkTable_Match(100_000, b) }
+
+// Returns an identifier of count bytes.
+func benchmarkInput(count int) []byte {
+	input := make([]byte, count)
+
+	for idx := range input {
+		input[idx] = 'a' + byte(idx%26)
+	}
+
+	return input
+}
+
+func benchmarkDfa_Match(count int, b *testing.B) {
+	machine := dfa.FromNfa(newByteMachine())
+	input := benchmarkInput(count)
+
+	for b.Loop() {
+		state := machine.Start()
+		benchmarkTableOutput = state.IsAccepting()
+
+		for _, symbol := range input {
+			if state = state.OutgoingFor(symbol); state == nil {
+				break
+			}
+
+			benchmarkTableOutput = benchmarkTableOutput || state.IsAccepting()
+		}
+	}
+}
+
+func benchmarkTable_Match(count int, b *testing.B) {
+	table, _ := dfa.Compile(dfa.FromNfa(newByteMachine()))
+	input := benchmarkInput(count)
+
+	for b.Loop() {
+		_, benchmarkTableOutput = table.Match(input)
+	}
+}
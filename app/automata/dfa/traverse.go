@@ -0,0 +1,123 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa
+
+import (
+	"iter"
+	"slices"
+)
+
+// Reachable returns an iterator over the states that are reachable from the start state of the DFA (or from any of
+// its start states if it has assertions, see [Dfa.StartAfter]), ordered by their ID.
+func (d *Dfa[S, V]) Reachable() iter.Seq[*State[S, V]] {
+	if d.start == nil {
+		return slices.Values([]*State[S, V](nil))
+	}
+
+	seen := map[*State[S, V]]bool{d.start: true}
+	states := []*State[S, V]{d.start}
+
+	visit := func(state *State[S, V]) {
+		if !seen[state] {
+			seen[state] = true
+			states = append(states, state)
+		}
+	}
+
+	for _, start := range d.starts {
+		visit(start)
+	}
+
+	for idx := 0; idx < len(states); idx++ {
+		for _, target := range states[idx].Transitions() {
+			visit(target)
+		}
+
+		for _, target := range states[idx].GuardTransitions() {
+			visit(target)
+		}
+	}
+
+	slices.SortFunc(states, func(a, b *State[S, V]) int { return a.id - b.id })
+
+	return slices.Values(states)
+}
+
+// Transitions returns an iterator over the concrete transitions of the state (the symbol and its target), in no
+// particular order.
+func (s *State[S, V]) Transitions() iter.Seq2[S, *State[S, V]] {
+	return func(yield func(S, *State[S, V]) bool) {
+		for symbol, target := range s.transitions {
+			if !yield(symbol, target) {
+				return
+			}
+		}
+	}
+}
+
+// GuardTransitions returns an iterator over the transitions of the guard of the state (if any): for every minterm that
+// leads to a target, a predicate that holds for the symbols that are dispatched to that target, in ascending order of
+// the minterms. The predicates never hold for symbols with a concrete transition, so they're disjoint.
+func (s *State[S, V]) GuardTransitions() iter.Seq2[func(S) bool, *State[S, V]] {
+	return func(yield func(func(S) bool, *State[S, V]) bool) {
+		if s.guard == nil {
+			return
+		}
+
+		for _, mask := range s.guard.masks() {
+			fn := func(symbol S) bool {
+				if _, ok := s.transitions[symbol]; ok {
+					return false
+				}
+
+				return s.guard.minterm(symbol) == mask
+			}
+
+			if !yield(fn, s.guard.targets[mask]) {
+				return
+			}
+		}
+	}
+}
+
+// NumStates returns the number of states of the DFA (see [Dfa.States]).
+func (d *Dfa[S, V]) NumStates() int { return len(d.states) }
+
+// NumTransitions returns the number of transitions of the states of the DFA: the concrete transitions and the
+// transitions of their guards (see [State.GuardTransitions]).
+func (d *Dfa[S, V]) NumTransitions() int {
+	count := 0
+
+	for _, state := range d.states {
+		count += len(state.transitions)
+
+		if state.guard != nil {
+			count += len(state.guard.targets)
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,83 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package dfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+)
+
+// UT: Walk the reachable states of a 'Dfa' and their transitions.
+func TestDfa_Reachable(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := dfa.FromNfa(newByteMachine())
+
+	// Act.
+	states := 0
+	transitions := 0
+
+	for state := range machine.Reachable() {
+		states++
+
+		for symbol := range 256 {
+			var targets []*dfa.State[byte, int]
+
+			for s, target := range state.Transitions() {
+				transitions++
+
+				if s == byte(symbol) {
+					targets = append(targets, target)
+				}
+			}
+
+			for fn, target := range state.GuardTransitions() {
+				transitions++
+
+				if fn(byte(symbol)) {
+					targets = append(targets, target)
+				}
+			}
+
+			// Assert.
+			want := state.OutgoingFor(byte(symbol))
+
+			assert.Truef(t, (want == nil && len(targets) == 0) || (len(targets) == 1 && targets[0] == want), "\n\n"+
+				"UT Name:  When walking a 'Dfa', exactly one transition of state %d leads %q to its target.\n"+
+				"\033[32mExpected: %v.\033[0m\n"+
+				"\033[31mActual:   %v.\033[0m\n\n", state.ID(), byte(symbol), want, targets)
+		}
+	}
+
+	// Assert.
+	assert.Truef(t, states == machine.NumStates() && transitions == 256*machine.NumTransitions(), "\n\n"+
+		"UT Name:  When walking a 'Dfa', every state and every transition is reported.\n"+
+		"\033[32mExpected: %d, %d.\033[0m\n"+
+		"\033[31mActual:   %d, %d.\033[0m\n\n", machine.NumStates(), machine.NumTransitions(), states, transitions/256)
+}
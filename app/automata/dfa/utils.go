@@ -26,46 +26,21 @@
 package dfa
 
 import (
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/kdeconinck/realign/automata/internal/dot"
 	"github.com/kdeconinck/realign/automata/nfa"
-	"github.com/kdeconinck/realign/collections/queue"
 	"github.com/kdeconinck/realign/collections/set"
 )
 
-// Returns all the possible [nfa.State]s, reachable from states, by following zero or more epsilon transitions.
-func findPossibleStates[S comparable, V any](states ...*nfa.State[S, V]) []*nfa.State[S, V] {
-	workingQueue := queue.New[*nfa.State[S, V]]()
-	reachableStates := make([]*nfa.State[S, V], 0, len(states))
-
-	for _, s := range states {
-		workingQueue.Enqueue(s)
-		reachableStates = append(reachableStates, s)
-	}
-
-	for workingQueue.Len() > 0 {
-		queuedState, _ := workingQueue.Dequeue()
-
-		for _, nState := range queuedState.Epsilon() {
-			workingQueue.Enqueue(nState)
-			reachableStates = append(reachableStates, nState)
-		}
-	}
-
-	return reachableStates
-}
-
 func calculateStatesKey[S comparable, V any](states []*nfa.State[S, V]) string {
-	seen := make(map[int]struct{}, len(states))
 	stateIDs := make([]int, 0, len(states))
 
 	for _, state := range states {
-		id := state.ID()
-
-		seen[id] = struct{}{}
-		stateIDs = append(stateIDs, id)
+		stateIDs = append(stateIDs, state.ID())
 	}
 
 	sort.Ints(stateIDs)
@@ -83,7 +58,74 @@ func calculateStatesKey[S comparable, V any](states []*nfa.State[S, V]) string {
 	return b.String()
 }
 
-func expandStatesPerSymbol[S comparable, V any](states []*nfa.State[S, V]) map[S][]*nfa.State[S, V] {
+// Returns the symbol directly after symbol, in the order of symbolOrder, or false if S isn't a predeclared integer or
+// string type. The largest integer is its own successor, since there's no symbol after it.
+func successor[S comparable](symbol S) (S, bool) {
+	var next any
+
+	switch s := any(symbol).(type) {
+	case int:
+		next = increment(s)
+	case int8:
+		next = increment(s)
+	case int16:
+		next = increment(s)
+	case int32:
+		next = increment(s)
+	case int64:
+		next = increment(s)
+	case uint:
+		next = increment(s)
+	case uint8:
+		next = increment(s)
+	case uint16:
+		next = increment(s)
+	case uint32:
+		next = increment(s)
+	case uint64:
+		next = increment(s)
+	case string:
+		next = s + "\x00"
+	default:
+		return symbol, false
+	}
+
+	return next.(S), true
+}
+
+// Returns value plus one or value itself if that overflows.
+func increment[T int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64](value T) T {
+	if value+1 < value {
+		return value
+	}
+
+	return value + 1
+}
+
+// Sorts symbols in a deterministic order: numerically or lexicographically for the predeclared integer and string types
+// (see symbolOrder), or in the order of [dot.Sort] otherwise.
+//
+// Reasoning:
+// The IDs of the states are assigned in the order in which they're discovered. Discovering them in the order of their
+// symbols, rather than in the order of a map, makes the IDs only depend on the structure of the automaton.
+func sortSymbols[S comparable](symbols []S) {
+	if compare, ok := symbolOrder[S](); ok {
+		slices.SortFunc(symbols, compare)
+
+		return
+	}
+
+	dot.Sort(dot.Options[S, struct{}]{}, symbols)
+}
+
+// Returns, for every concrete symbol leaving states, the [nfa.State]s reachable by consuming that symbol, including the
+// ones reached through the predicates of classes that hold for the symbol.
+//
+// The symbols of the same class of symbolClasses (see [nfa.SymbolClasses]) reach the same states, so those are only
+// computed once per class.
+func expandStatesPerSymbol[S comparable, V any](
+	closures *closureCache[S, V], states []*nfa.State[S, V], classes []*guardClass[S, V], symbolClasses map[S]int,
+) map[S][]*nfa.State[S, V] {
 	alphabet := set.New[S]()
 
 	for _, state := range states {
@@ -93,10 +135,32 @@ func expandStatesPerSymbol[S comparable, V any](states []*nfa.State[S, V]) map[S
 	}
 
 	statesPerSymbol := make(map[S][]*nfa.State[S, V])
+	statesPerClass := make(map[int][]*nfa.State[S, V])
 
 	for _, sym := range alphabet.Values() {
+		symbolClass, classified := symbolClasses[sym]
+
+		if epsilonStates, ok := statesPerClass[symbolClass]; classified && ok {
+			if len(epsilonStates) > 0 {
+				statesPerSymbol[sym] = epsilonStates
+			}
+
+			continue
+		}
+
 		symbolStates := findReachableStatesForSymbol(states, sym)
-		epsilonStates := findPossibleStates(symbolStates...)
+
+		for _, class := range classes {
+			if class.matches(sym) {
+				symbolStates = append(symbolStates, class.endState)
+			}
+		}
+
+		epsilonStates := closures.findPossibleStates(symbolStates...)
+
+		if classified {
+			statesPerClass[symbolClass] = epsilonStates
+		}
 
 		if len(epsilonStates) > 0 {
 			statesPerSymbol[sym] = epsilonStates
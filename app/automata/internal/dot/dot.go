@@ -0,0 +1,269 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package dot writes automata in the DOT language of Graphviz.
+//
+// It holds the parts that are shared by the WriteDOT functions of the packages nfa and dfa: the options, the layout of
+// the graph and the formatting of states and symbols.
+package dot
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Options configures the output of a WriteDOT function.
+type Options[S comparable, V any] struct {
+	// The name of the graph. When empty, the name of the kind of automaton is used.
+	Name string
+
+	// Returns the label of a symbol. When nil, runes and bytes are quoted like Go characters (e.g., 'a') and other
+	// symbols are formatted using fmt.Sprint.
+	FormatSymbol func(S) string
+
+	// Returns the label of an accepting value. When nil, values are formatted using fmt.Sprint.
+	FormatValue func(V) string
+
+	// When true, all the transitions on concrete symbols between the same pair of states are drawn as a single edge.
+	// For symbols of an integer type (e.g., runes and bytes), runs of at least 3 consecutive symbols are drawn as a
+	// range (e.g., 'a'-'z').
+	CollapseRanges bool
+}
+
+// Graph is a directed graph that's being written in the DOT language.
+type Graph struct {
+	buf    bytes.Buffer
+	starts int // The number of extra start points (see [Start]).
+}
+
+// New returns a new [Graph] with the given name, where the state with ID start is marked as the start state.
+func New(name string, start int) *Graph {
+	graph := &Graph{}
+
+	fmt.Fprintf(&graph.buf, "digraph %s {\n", strconv.Quote(name))
+	graph.buf.WriteString("\trankdir=LR;\n")
+	graph.buf.WriteString("\tnode [shape=circle];\n")
+	graph.buf.WriteString("\t__start [shape=point];\n")
+	fmt.Fprintf(&graph.buf, "\t__start -> %d;\n", start)
+
+	return graph
+}
+
+// Start adds an extra start point to the graph, connected to the state with ID to by a dashed edge with label.
+func Start(graph *Graph, to int, label string) {
+	graph.starts++
+
+	fmt.Fprintf(&graph.buf, "\t__start%d [shape=point];\n", graph.starts)
+	fmt.Fprintf(&graph.buf, "\t__start%d -> %d [style=dashed, label=\"%s\"];\n", graph.starts, to, escape(label))
+}
+
+// State adds a state to the graph.
+// An accepting state is drawn as a double circle, labeled with its ID, its accepting value and its acceptance index.
+func State[S comparable, V any](graph *Graph, opts Options[S, V], id, acceptIdx int, value V) {
+	if acceptIdx < 0 {
+		fmt.Fprintf(&graph.buf, "\t%d;\n", id)
+
+		return
+	}
+
+	label := escape(strconv.Itoa(id)) + `\n` + escape(formatValue(opts, value)+" #"+strconv.Itoa(acceptIdx))
+
+	fmt.Fprintf(&graph.buf, "\t%d [shape=doublecircle, label=\"%s\"];\n", id, label)
+}
+
+// Condition is the acceptance of a conditional state before a symbol of some kinds.
+type Condition[V any] struct {
+	Before    string // The kinds of the next symbol for which the state accepts (e.g., "none, newline").
+	AcceptIdx int
+	Value     V
+}
+
+// ConditionalState adds a state whose acceptance depends on the next symbol to the graph.
+// It's drawn as a dashed double circle, labeled with its ID and a line per condition with its accepting value, its
+// acceptance index and the kinds of the next symbol for which it holds.
+func ConditionalState[S comparable, V any](graph *Graph, opts Options[S, V], id int, conditions []Condition[V]) {
+	label := escape(strconv.Itoa(id))
+
+	for _, condition := range conditions {
+		line := formatValue(opts, condition.Value) + " #" + strconv.Itoa(condition.AcceptIdx) + " before " + condition.Before
+		label += `\n` + escape(line)
+	}
+
+	fmt.Fprintf(&graph.buf, "\t%d [shape=doublecircle, style=dashed, label=\"%s\"];\n", id, label)
+}
+
+// Symbols adds the edges from the state with ID from to the state with ID to for symbols.
+// Depending on opts, either a single edge is added for all the symbols or an edge is added for every symbol.
+func Symbols[S comparable, V any](graph *Graph, opts Options[S, V], from, to int, symbols []S) {
+	labels := symbolLabels(opts, symbols)
+
+	if opts.CollapseRanges {
+		labels = []string{strings.Join(labels, ", ")}
+	}
+
+	for _, label := range labels {
+		Edge(graph, from, to, label, "")
+	}
+}
+
+// Edge adds an edge from the state with ID from to the state with ID to with the given label.
+// If style isn't empty, it's used as the style of the edge (e.g., "dashed").
+func Edge(graph *Graph, from, to int, label, style string) {
+	fmt.Fprintf(&graph.buf, "\t%d -> %d [", from, to)
+
+	if style != "" {
+		fmt.Fprintf(&graph.buf, "style=%s, ", style)
+	}
+
+	fmt.Fprintf(&graph.buf, "label=\"%s\"];\n", escape(label))
+}
+
+// WriteTo completes the graph and writes it to w.
+func (graph *Graph) WriteTo(w io.Writer) (int64, error) {
+	graph.buf.WriteString("}\n")
+
+	return graph.buf.WriteTo(w)
+}
+
+// Sort sorts symbols in a deterministic order.
+// Symbols of an integer type are ordered numerically, other symbols are ordered by their label.
+func Sort[S comparable, V any](opts Options[S, V], symbols []S) {
+	integers := true
+
+	for _, symbol := range symbols {
+		if _, ok := integer(symbol); !ok {
+			integers = false
+
+			break
+		}
+	}
+
+	if integers {
+		slices.SortFunc(symbols, func(a, b S) int {
+			aValue, _ := integer(a)
+			bValue, _ := integer(b)
+
+			return cmp.Compare(aValue, bValue)
+		})
+
+		return
+	}
+
+	slices.SortFunc(symbols, func(a, b S) int { return strings.Compare(formatSymbol(opts, a), formatSymbol(opts, b)) })
+}
+
+// Returns the labels of symbols in a deterministic order (see [Sort]).
+// If enabled in opts, runs of consecutive symbols of an integer type are merged into ranges.
+func symbolLabels[S comparable, V any](opts Options[S, V], symbols []S) []string {
+	symbols = slices.Clone(symbols)
+	Sort(opts, symbols)
+
+	labels := make([]string, 0, len(symbols))
+
+	for idx := 0; idx < len(symbols); {
+		end := idx + 1
+
+		if opts.CollapseRanges {
+			for end < len(symbols) && consecutive(symbols[end-1], symbols[end]) {
+				end++
+			}
+		}
+
+		if end-idx >= 3 {
+			labels = append(labels, formatSymbol(opts, symbols[idx])+"-"+formatSymbol(opts, symbols[end-1]))
+			idx = end
+
+			continue
+		}
+
+		labels = append(labels, formatSymbol(opts, symbols[idx]))
+		idx++
+	}
+
+	return labels
+}
+
+// Reports whether a and b are of an integer type and b directly follows a.
+func consecutive[S comparable](a, b S) bool {
+	aValue, aOk := integer(a)
+	bValue, bOk := integer(b)
+
+	return aOk && bOk && aValue+1 == bValue
+}
+
+// Returns the value of symbol if it's of an integer type.
+func integer[S comparable](symbol S) (int64, bool) {
+	value := reflect.ValueOf(symbol)
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(value.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+// Returns the label of symbol.
+func formatSymbol[S comparable, V any](opts Options[S, V], symbol S) string {
+	if opts.FormatSymbol != nil {
+		return opts.FormatSymbol(symbol)
+	}
+
+	switch symbol := any(symbol).(type) {
+	case rune:
+		return strconv.QuoteRune(symbol)
+	case byte:
+		if symbol >= utf8.RuneSelf {
+			return fmt.Sprintf("0x%02X", symbol)
+		}
+
+		return strconv.QuoteRune(rune(symbol))
+	default:
+		return fmt.Sprint(symbol)
+	}
+}
+
+// Returns the label of value.
+func formatValue[S comparable, V any](opts Options[S, V], value V) string {
+	if opts.FormatValue != nil {
+		return opts.FormatValue(value)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// Returns str, escaped for use in a quoted string in the DOT language.
+func escape(str string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(str)
+}
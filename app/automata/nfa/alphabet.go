@@ -0,0 +1,280 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Alphabet partitions the symbols of type S into equivalence classes: symbols in the same class are consumed by exactly
+// the same transitions, so an automaton never needs to distinguish them.
+//
+// Reasoning:
+// Most symbols of a lexer behave identically (every letter except the ones that start a keyword, say). An automaton
+// over the classes of its alphabet, instead of over its symbols, has far fewer transitions and is built faster, since
+// every class is expanded once, instead of every symbol.
+//
+// Class 0 holds the symbols that no transition consumes. The symbols with a concrete transition share a class if
+// they're consumed by exactly the same concrete transitions and are members of exactly the same classes of the
+// predicate transitions (see [Class]). The other symbols are split by the boundaries of those classes: the intervals
+// between two boundaries that are members of the same classes share a class.
+type Alphabet[S cmp.Ordered] struct {
+	symbols         map[S]int // The class of every symbol with a concrete transition.
+	boundaries      []S       // The boundaries of the intervals of the other symbols, in ascending order.
+	intervals       []int     // The class of every interval, by boundary.
+	representatives []S       // The representative of every class, by class (the zero value of S for class 0).
+	concrete        []bool    // True for the classes of the symbols with a concrete transition, by class.
+}
+
+// AlphabetOf returns the [Alphabet] of machine or false if any of its predicate transitions isn't built from a [Class].
+func AlphabetOf[S cmp.Ordered, V any](machine *Nfa[S, V]) (*Alphabet[S], bool) {
+	var classes []Class[S]
+
+	states := reachableStates(machine)
+
+	for _, state := range states {
+		for _, transition := range state.predicateTransitions {
+			if transition.Class == nil {
+				return nil, false
+			}
+
+			classes = append(classes, transition.Class)
+		}
+	}
+
+	return NewAlphabet(concreteSets(states), classes), true
+}
+
+// SymbolClasses partitions the symbols with a concrete transition in machine into classes: symbols share a class if
+// they're consumed by exactly the same concrete transitions and every predicate transition holds for either all or
+// none of them. It returns the class of every such symbol.
+//
+// Unlike [AlphabetOf], this works for predicates that aren't built from a [Class] and for unordered symbols, but it
+// doesn't classify the symbols without a concrete transition.
+func SymbolClasses[S comparable, V any](machine *Nfa[S, V]) map[S]int {
+	var predicates []func(S) bool
+
+	states := reachableStates(machine)
+
+	for _, state := range states {
+		for _, transition := range state.predicateTransitions {
+			predicates = append(predicates, transition.Fn)
+		}
+	}
+
+	symbols, signatures := concreteSignatures(concreteSets(states), predicates)
+	classes := make(map[S]int, len(symbols))
+	classBySignature := make(map[string]int)
+
+	for _, symbol := range symbols {
+		class, ok := classBySignature[signatures[symbol]]
+
+		if !ok {
+			class = len(classBySignature)
+			classBySignature[signatures[symbol]] = class
+		}
+
+		classes[symbol] = class
+	}
+
+	return classes
+}
+
+// Returns the symbols of the concrete transitions leaving states, grouped per pair of a state and a target.
+func concreteSets[S comparable, V any](states []*State[S, V]) [][]S {
+	var sets [][]S
+
+	for _, state := range states {
+		symbolsByTarget := make(map[*State[S, V]][]S)
+		var targets []*State[S, V]
+
+		for _, symbol := range state.OutgoingSymbols() {
+			for _, target := range state.OutgoingFor(symbol) {
+				if _, ok := symbolsByTarget[target]; !ok {
+					targets = append(targets, target)
+				}
+
+				symbolsByTarget[target] = append(symbolsByTarget[target], symbol)
+			}
+		}
+
+		for _, target := range targets {
+			sets = append(sets, symbolsByTarget[target])
+		}
+	}
+
+	return sets
+}
+
+// Returns the members of sets (without duplicates, in order of their first appearance) and the signature of every
+// member: the sets it's a member of, followed by the predicates that hold for it. Members with the same signature are
+// consumed by exactly the same transitions.
+func concreteSignatures[S comparable](sets [][]S, predicates []func(S) bool) ([]S, map[S]string) {
+	var symbols []S
+
+	signatures := make(map[S][]byte)
+
+	for idx, members := range sets {
+		for _, symbol := range members {
+			signature, ok := signatures[symbol]
+
+			if !ok {
+				symbols = append(symbols, symbol)
+			}
+
+			signatures[symbol] = append(strconv.AppendInt(signature, int64(idx), 10), ',')
+		}
+	}
+
+	result := make(map[S]string, len(symbols))
+
+	for _, symbol := range symbols {
+		signature := append(signatures[symbol], '|')
+
+		for _, fn := range predicates {
+			if fn(symbol) {
+				signature = append(signature, '1')
+			} else {
+				signature = append(signature, '0')
+			}
+		}
+
+		result[symbol] = string(signature)
+	}
+
+	return symbols, result
+}
+
+// NewAlphabet returns the coarsest [Alphabet] in which the members of every set of sets (e.g., the symbols of the
+// concrete transitions between two states) and the members of every class of classes are unions of classes.
+func NewAlphabet[S cmp.Ordered](sets [][]S, classes []Class[S]) *Alphabet[S] {
+	alphabet := &Alphabet[S]{
+		symbols:         make(map[S]int),
+		representatives: make([]S, 1),
+		concrete:        make([]bool, 1),
+	}
+
+	predicates := make([]func(S) bool, len(classes))
+
+	for idx, class := range classes {
+		predicates[idx] = class.Contains
+	}
+
+	symbols, signatures := concreteSignatures(sets, predicates)
+	slices.Sort(symbols)
+
+	classBySignature := make(map[string]int)
+
+	for _, symbol := range symbols {
+		class, ok := classBySignature[signatures[symbol]]
+
+		if !ok {
+			class = alphabet.add(symbol, true)
+			classBySignature[signatures[symbol]] = class
+		}
+
+		alphabet.symbols[symbol] = class
+	}
+
+	for _, class := range classes {
+		alphabet.boundaries = append(alphabet.boundaries, class.Boundaries()...)
+	}
+
+	slices.Sort(alphabet.boundaries)
+	alphabet.boundaries = slices.Compact(alphabet.boundaries)
+
+	classByMembership := map[string]int{"": 0}
+
+	for _, boundary := range alphabet.boundaries {
+		membership := membershipOf(classes, boundary)
+		class, ok := classByMembership[membership]
+
+		if !ok {
+			class = alphabet.add(boundary, false)
+			classByMembership[membership] = class
+		}
+
+		alphabet.intervals = append(alphabet.intervals, class)
+	}
+
+	return alphabet
+}
+
+// Adds a class with representative to the alphabet and returns it.
+func (alphabet *Alphabet[S]) add(representative S, concrete bool) int {
+	alphabet.representatives = append(alphabet.representatives, representative)
+	alphabet.concrete = append(alphabet.concrete, concrete)
+
+	return len(alphabet.representatives) - 1
+}
+
+// Returns the classes that symbol is a member of, encoded as a string ("" if it isn't a member of any class).
+func membershipOf[S comparable](classes []Class[S], symbol S) string {
+	membership := make([]byte, len(classes))
+
+	for idx, class := range classes {
+		membership[idx] = '0'
+
+		if class.Contains(symbol) {
+			membership[idx] = '1'
+		}
+	}
+
+	return strings.TrimRight(string(membership), "0")
+}
+
+// NumClasses returns the number of classes of the alphabet (including class 0).
+func (alphabet *Alphabet[S]) NumClasses() int { return len(alphabet.representatives) }
+
+// ClassOf returns the class of symbol.
+func (alphabet *Alphabet[S]) ClassOf(symbol S) int {
+	if class, ok := alphabet.symbols[symbol]; ok {
+		return class
+	}
+
+	idx := sort.Search(len(alphabet.boundaries), func(idx int) bool { return alphabet.boundaries[idx] > symbol }) - 1
+
+	if idx < 0 {
+		return 0
+	}
+
+	return alphabet.intervals[idx]
+}
+
+// Representative returns a symbol that's a member of exactly the same [Class]es as every member of class. Unless class
+// holds the symbols with a concrete transition (see [Alphabet.Symbol]), the representative may have concrete
+// transitions of its own, so only the predicate transitions should be evaluated on it.
+func (alphabet *Alphabet[S]) Representative(class int) S { return alphabet.representatives[class] }
+
+// Symbol returns a member of class with a concrete transition, which is consumed by exactly the same transitions as
+// every other member of class, or false if class doesn't hold symbols with a concrete transition.
+func (alphabet *Alphabet[S]) Symbol(class int) (S, bool) {
+	return alphabet.representatives[class], alphabet.concrete[class]
+}
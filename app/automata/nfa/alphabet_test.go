@@ -0,0 +1,184 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// UT: Partition the alphabet of an 'Nfa' into equivalence classes.
+func TestAlphabetOf(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := nfa.New[int, int]()
+
+	machine.AddAcceptingEpsilonTransition(machine.Add(machine.Start(), 5), 1)
+	machine.AddAcceptingEpsilonTransition(machine.Add(machine.Start(), -3), 2)
+	machine.AddClassTransition(machine.Start(), machine.AddAcceptingEpsilonTransition(machine.NewState(), 3), positive{})
+
+	// Act.
+	alphabet, ok := nfa.AlphabetOf(machine)
+
+	// Assert.
+	assert.Truef(t, ok && alphabet.NumClasses() == 4, "\n\n"+
+		"UT Name:  When the predicates are built from classes, the alphabet is partitioned.\n"+
+		"\033[32mExpected: true (4 classes).\033[0m\n"+
+		"\033[31mActual:   %t.\033[0m\n\n", ok)
+
+	for _, tc := range []struct {
+		name  string
+		a, b  int
+		equal bool
+	}{
+		{"symbols in the same interval share a class", 1, 100, true},
+		{"symbols that no transition consumes share a class", 0, -7, true},
+		{"only one of two symbols has a concrete transition", 5, 6, false},
+		{"only one of two symbols has a concrete transition", -3, -4, false},
+		{"two symbols with a concrete transition lead to different states", 5, -3, false},
+	} {
+		got := alphabet.ClassOf(tc.a) == alphabet.ClassOf(tc.b)
+
+		assert.Equalf(t, got, tc.equal, "\n\n"+
+			"UT Name:  When %s, %d and %d are (NOT) in the same class.\n"+
+			"\033[32mExpected: %t.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", tc.name, tc.a, tc.b, tc.equal, got)
+	}
+
+	t.Run("When no transition consumes a symbol, it's in class 0.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		got := alphabet.ClassOf(0)
+
+		// Assert.
+		assert.Equalf(t, got, 0, "\n\n"+
+			"UT Name:  When no transition consumes a symbol, it's in class 0.\n"+
+			"\033[32mExpected: 0.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", got)
+	})
+
+	t.Run("When a class holds a symbol with a concrete transition, that symbol is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		symbol, ok := alphabet.Symbol(alphabet.ClassOf(5))
+		_, intervalOk := alphabet.Symbol(alphabet.ClassOf(100))
+
+		// Assert.
+		assert.Truef(t, ok && symbol == 5 && !intervalOk, "\n\n"+
+			"UT Name:  When a class holds a symbol with a concrete transition, that symbol is returned.\n"+
+			"\033[32mExpected: 5 (true), false.\033[0m\n"+
+			"\033[31mActual:   %d (%t), %t.\033[0m\n\n", symbol, ok, intervalOk)
+	})
+
+	t.Run("When a predicate isn't built from a class, the alphabet can't be partitioned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		opaque := nfa.New[int, int]()
+
+		opaque.AddPredicateTransition(opaque.Start(), opaque.NewState(), func(i int) bool { return i > 0 })
+
+		// Act.
+		_, ok := nfa.AlphabetOf(opaque)
+
+		// Assert.
+		assert.Falsef(t, ok, "\n\n"+
+			"UT Name:  When a predicate isn't built from a class, the alphabet can't be partitioned.\n"+
+			"\033[32mExpected: false.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", ok)
+	})
+	t.Run("When symbols are consumed by the same transitions, they share a class.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		shared := nfa.New[int, int]()
+		end := shared.AddAcceptingEpsilonTransition(shared.NewState(), 1)
+
+		shared.Connect(shared.Start(), end, 7)
+		shared.Connect(shared.Start(), end, 8)
+		shared.AddClassTransition(shared.Start(), end, positive{})
+
+		// Act.
+		alphabet, _ := nfa.AlphabetOf(shared)
+
+		// Assert.
+		assert.Truef(t, alphabet.ClassOf(7) == alphabet.ClassOf(8) && alphabet.NumClasses() == 3, "\n\n"+
+			"UT Name:  When symbols are consumed by the same transitions, they share a class.\n"+
+			"\033[32mExpected: true (3 classes).\033[0m\n"+
+			"\033[31mActual:   %t (%d classes).\033[0m\n\n", alphabet.ClassOf(7) == alphabet.ClassOf(8),
+			alphabet.NumClasses())
+	})
+}
+
+// UT: Partition the symbols with a concrete transition of an 'Nfa' into equivalence classes.
+func TestSymbolClasses(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := nfa.New[rune, int]()
+	word := machine.AddAcceptingEpsilonTransition(machine.NewState(), 1)
+
+	for _, r := range "abcxyz" {
+		machine.Connect(machine.Start(), word, r)
+	}
+
+	machine.AddAcceptingEpsilonTransition(machine.Add(machine.Start(), 'i'), 2)
+	machine.AddPredicateTransition(machine.Start(), word, func(r rune) bool { return r >= 'x' })
+
+	// Act.
+	classes := nfa.SymbolClasses(machine)
+
+	// Assert.
+	for _, tc := range []struct {
+		name  string
+		a, b  rune
+		equal bool
+	}{
+		{"two symbols are consumed by the same transitions", 'a', 'c', true},
+		{"a predicate holds for both symbols", 'x', 'z', true},
+		{"a predicate holds for only one of two symbols", 'a', 'x', false},
+		{"two symbols lead to different states", 'a', 'i', false},
+	} {
+		got := classes[tc.a] == classes[tc.b]
+
+		assert.Equalf(t, got, tc.equal, "\n\n"+
+			"UT Name:  When %s, %q and %q are (NOT) in the same class.\n"+
+			"\033[32mExpected: %t.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", tc.name, tc.a, tc.b, tc.equal, got)
+	}
+
+	_, ok := classes['q']
+
+	assert.Falsef(t, ok, "\n\n"+
+		"UT Name:  When a symbol has no concrete transition, it has no class.\n"+
+		"\033[32mExpected: false.\033[0m\n"+
+		"\033[31mActual:   %t.\033[0m\n\n", ok)
+}
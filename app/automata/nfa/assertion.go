@@ -0,0 +1,166 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+// SymbolKind classifies the symbol on either side of a position in the input, as far as an [Assertion] is concerned.
+type SymbolKind int
+
+const (
+	KindNone    SymbolKind = iota // There's no symbol (the start or the end of the input).
+	KindNewline                   // The symbol is a newline ('\n').
+	KindWord                      // The symbol is an ASCII word character ([0-9A-Za-z_]).
+	KindOther                     // Any other symbol.
+)
+
+// NumSymbolKinds is the number of different [SymbolKind]s.
+const NumSymbolKinds = 4
+
+// KindOf returns the [SymbolKind] of symbol.
+//
+// Only symbols of type rune and byte can be a newline or a word character. Every symbol of another type is classified
+// as [KindOther], so that only the anchors for the start and the end of the input can be asserted for those.
+func KindOf[S comparable](symbol S) SymbolKind {
+	var r rune
+
+	switch s := any(symbol).(type) {
+	case rune:
+		r = s
+	case byte:
+		r = rune(s)
+	default:
+		return KindOther
+	}
+
+	switch {
+	case r == '\n':
+		return KindNewline
+	case r == '_', '0' <= r && r <= '9', 'A' <= r && r <= 'Z', 'a' <= r && r <= 'z':
+		return KindWord
+	default:
+		return KindOther
+	}
+}
+
+// String returns the name of the kind (e.g., "newline").
+func (kind SymbolKind) String() string {
+	switch kind {
+	case KindNone:
+		return "none"
+	case KindNewline:
+		return "newline"
+	case KindWord:
+		return "word"
+	case KindOther:
+		return "other"
+	default:
+		return "?"
+	}
+}
+
+// KindAt returns the [SymbolKind] of input[idx] or [KindNone] if idx is outside input.
+func KindAt[S comparable](input []S, idx int) SymbolKind {
+	if idx < 0 || idx >= len(input) {
+		return KindNone
+	}
+
+	return KindOf(input[idx])
+}
+
+// Assertion is a condition on the symbols surrounding a position in the input that doesn't consume any symbol.
+type Assertion int
+
+const (
+	BeginText      Assertion = iota // Holds at the start of the input.
+	EndText                         // Holds at the end of the input.
+	BeginLine                       // Holds at the start of the input or after a newline.
+	EndLine                         // Holds at the end of the input or before a newline.
+	WordBoundary                    // Holds between a word character and something that isn't.
+	NoWordBoundary                  // Holds where [WordBoundary] doesn't.
+)
+
+// Holds reports whether the assertion holds between a symbol of kind prev and a symbol of kind next.
+func (a Assertion) Holds(prev, next SymbolKind) bool {
+	switch a {
+	case BeginText:
+		return prev == KindNone
+	case EndText:
+		return next == KindNone
+	case BeginLine:
+		return prev == KindNone || prev == KindNewline
+	case EndLine:
+		return next == KindNone || next == KindNewline
+	case WordBoundary:
+		return (prev == KindWord) != (next == KindWord)
+	case NoWordBoundary:
+		return (prev == KindWord) == (next == KindWord)
+	default:
+		return false
+	}
+}
+
+// String returns the regular expression syntax of the assertion.
+func (a Assertion) String() string {
+	switch a {
+	case BeginText:
+		return `\A`
+	case EndText:
+		return `\z`
+	case BeginLine:
+		return "^"
+	case EndLine:
+		return "$"
+	case WordBoundary:
+		return `\b`
+	case NoWordBoundary:
+		return `\B`
+	default:
+		return "?"
+	}
+}
+
+// AssertionTransition is a transition that doesn't consume any symbol, but can only be taken where its assertion holds.
+type AssertionTransition[S comparable, V any] struct {
+	EndState  *State[S, V] // The [State] that is reached when the transition is taken.
+	Assertion Assertion    // The condition on the surrounding symbols.
+}
+
+// AddAssertion adds a transition from startState to endState that can only be taken where a holds.
+func (machine *Nfa[S, V]) AddAssertion(startState, endState *State[S, V], a Assertion) {
+	transition := AssertionTransition[S, V]{
+		EndState:  endState,
+		Assertion: a,
+	}
+
+	startState.assertions = append(startState.assertions, transition)
+	machine.hasAssertions = true
+}
+
+// HasAssertions reports whether an assertion transition was added to the nfa.
+func (machine *Nfa[S, V]) HasAssertions() bool { return machine.hasAssertions }
+
+// Assertions returns the assertion transitions starting from the state.
+// Note: The returned slice is the one stored inside the state; callers should NOT modify it.
+func (state *State[S, V]) Assertions() []AssertionTransition[S, V] { return state.assertions }
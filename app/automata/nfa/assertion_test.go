@@ -0,0 +1,124 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// UT: Classify a symbol for the assertions.
+func TestKindOf(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name string
+		got  nfa.SymbolKind
+		want nfa.SymbolKind
+	}{
+		{"a newline rune", nfa.KindOf('\n'), nfa.KindNewline},
+		{"a word rune", nfa.KindOf('_'), nfa.KindWord},
+		{"a non-ASCII letter", nfa.KindOf('é'), nfa.KindOther},
+		{"a word byte", nfa.KindOf(byte('7')), nfa.KindWord},
+		{"a symbol of another type", nfa.KindOf("a"), nfa.KindOther},
+	} {
+		// Assert.
+		assert.Equalf(t, tc.got, tc.want, "\n\n"+
+			"UT Name:  When the symbol is %s, its kind is correct.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", tc.name, tc.want, tc.got)
+	}
+}
+
+// UT: Check whether an assertion holds between two symbols.
+func TestAssertion_Holds(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		assertion  nfa.Assertion
+		prev, next nfa.SymbolKind
+		want       bool
+	}{
+		{nfa.BeginText, nfa.KindNone, nfa.KindWord, true},
+		{nfa.BeginText, nfa.KindNewline, nfa.KindWord, false},
+		{nfa.EndText, nfa.KindWord, nfa.KindNone, true},
+		{nfa.EndText, nfa.KindWord, nfa.KindNewline, false},
+		{nfa.BeginLine, nfa.KindNewline, nfa.KindWord, true},
+		{nfa.BeginLine, nfa.KindOther, nfa.KindWord, false},
+		{nfa.EndLine, nfa.KindWord, nfa.KindNewline, true},
+		{nfa.EndLine, nfa.KindWord, nfa.KindOther, false},
+		{nfa.WordBoundary, nfa.KindWord, nfa.KindNone, true},
+		{nfa.WordBoundary, nfa.KindWord, nfa.KindWord, false},
+		{nfa.NoWordBoundary, nfa.KindOther, nfa.KindNewline, true},
+		{nfa.NoWordBoundary, nfa.KindOther, nfa.KindWord, false},
+	} {
+		// Act.
+		got := tc.assertion.Holds(tc.prev, tc.next)
+
+		// Assert.
+		assert.Equalf(t, got, tc.want, "\n\n"+
+			"UT Name:  When checking %s between kind %d and kind %d, the result is correct.\n"+
+			"\033[32mExpected: %t.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", tc.assertion, tc.prev, tc.next, tc.want, got)
+	}
+}
+
+// UT: Match input against an 'Nfa' with assertion transitions.
+func TestNfa_MatchAt(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	// Rule 0: "ab" at the start of a line, as a whole word.
+	machine := nfa.New[rune, int]()
+
+	begin, end := machine.NewState(), machine.NewState()
+	machine.AddAssertion(machine.Start(), begin, nfa.BeginLine)
+	machine.AddAssertion(machine.Add(machine.Add(begin, 'a'), 'b'), end, nfa.WordBoundary)
+	machine.AddAcceptingEpsilonTransition(end, 0)
+
+	for _, tc := range []struct {
+		input string
+		start int
+		want  bool
+	}{
+		{"ab", 0, true},
+		{"ab-", 0, true},
+		{"abc", 0, false},
+		{"x\nab", 2, true},
+		{"xab", 1, false},
+	} {
+		// Act.
+		_, got := machine.MatchAt([]rune(tc.input), tc.start)
+
+		// Assert.
+		assert.Equalf(t, got, tc.want, "\n\n"+
+			"UT Name:  When matching %q at offset %d, the assertions are taken into account.\n"+
+			"\033[32mExpected: %t.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", tc.input, tc.start, tc.want, got)
+	}
+}
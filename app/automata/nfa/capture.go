@@ -0,0 +1,282 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+import "slices"
+
+// NewCapture registers a capture group named name and returns its index.
+// Group 0 is the whole match, so the first capture group has index 1. The start and the end of group g are recorded
+// in the slots 2*g and 2*g+1 (see [Nfa.AddTag]).
+func (machine *Nfa[S, V]) NewCapture(name string) int {
+	if machine.captureNames == nil {
+		machine.captureNames = []string{""}
+	}
+
+	machine.captureNames = append(machine.captureNames, name)
+
+	return len(machine.captureNames) - 1
+}
+
+// CaptureFor returns the index of the capture group registered for key. The first time key is seen, a new capture
+// group named name is registered (see [Nfa.NewCapture]).
+//
+// This way, a part of an automaton that's built multiple times (e.g., because it's repeated) records its offsets in a
+// single capture group, which holds the offsets of the last repetition that participated in the match.
+// Panics if key isn't comparable.
+func (machine *Nfa[S, V]) CaptureFor(key any, name string) int {
+	if group, ok := machine.captureKeys[key]; ok {
+		return group
+	}
+
+	if machine.captureKeys == nil {
+		machine.captureKeys = make(map[any]int)
+	}
+
+	group := machine.NewCapture(name)
+	machine.captureKeys[key] = group
+
+	return group
+}
+
+// CaptureNames returns the names of the capture groups, indexed by group (the name of group 0 is empty).
+func (machine *Nfa[S, V]) CaptureNames() []string {
+	if machine.captureNames == nil {
+		return []string{""}
+	}
+
+	return slices.Clone(machine.captureNames)
+}
+
+// CaptureIndex returns the index of the first capture group named name or -1 if there's no such group.
+func (machine *Nfa[S, V]) CaptureIndex(name string) int {
+	for idx, captureName := range machine.captureNames {
+		if idx > 0 && captureName == name {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// AddTag adds and returns a new [State], reached from startState with an epsilon transition, that records the offset
+// in the input in slot when it's entered. Tags are ignored by everything but [Nfa.MatchSubmatch].
+// Panics if slot isn't the slot of a registered capture group (see [Nfa.NewCapture]).
+func (machine *Nfa[S, V]) AddTag(startState *State[S, V], slot int) *State[S, V] {
+	if slot < 2 || slot >= 2*len(machine.captureNames) {
+		panic("AddTag: slot doesn't belong to a capture group")
+	}
+
+	state := machine.AddEpsilonTransition(startState)
+	state.tag = slot
+
+	return state
+}
+
+// Tag returns the slot that the state records the offset in when it's entered or false if the state isn't tagged.
+func (state *State[S, V]) Tag() (int, bool) { return state.tag, state.tag > 0 }
+
+// Disambiguation decides which match [Nfa.MatchSubmatch] returns when input can be matched in multiple ways.
+//
+// The paths through an [Nfa] are ordered by priority: at every [State], the epsilon transitions are preferred in the
+// order they were added (followed by the assertion transitions), while consuming a symbol, the concrete transitions
+// are preferred over the predicate transitions.
+type Disambiguation int
+
+const (
+	// LeftmostLongest returns the longest match, like [Nfa.Match] does. When the longest match is accepted by multiple
+	// accepting [State]s, the one with the lowest acceptance index wins. Its submatches are taken from the path with
+	// the highest priority that leads to that [State].
+	LeftmostLongest Disambiguation = iota
+
+	// LeftmostFirst returns the match of the path with the highest priority that reaches an accepting [State] (like
+	// Perl and Go's "regexp" package do), even if a longer match exists. A path ends at the first accepting [State] it
+	// reaches.
+	LeftmostFirst
+)
+
+// Submatch is a [Match] together with the offsets of its capture groups.
+type Submatch[V any] struct {
+	Match[V]
+
+	// The start and the end offset in the input of every capture group g are stored in Slots[2*g] and Slots[2*g+1].
+	// Both are -1 if the group didn't participate in the match.
+	Slots []int
+}
+
+// Group returns the start and the end offset in the input of capture group g or false if it didn't participate in the
+// match.
+func (m Submatch[V]) Group(g int) (int, int, bool) {
+	if 2*g+1 >= len(m.Slots) || m.Slots[2*g] < 0 || m.Slots[2*g+1] < 0 {
+		return -1, -1, false
+	}
+
+	return m.Slots[2*g], m.Slots[2*g+1], true
+}
+
+// MatchSubmatch matches a prefix of input[start:] against the nfa, just like [Nfa.MatchAt], but also returns the
+// offsets of the capture groups. When input can be matched in multiple ways, disambiguation decides which match is
+// returned.
+//
+// The nfa is simulated with a Pike VM: like Thompson's algorithm, every [State] is active at most once per offset, but
+// every active [State] carries the slots of the path with the highest priority that reached it.
+func (machine *Nfa[S, V]) MatchSubmatch(input []S, start int, disambiguation Disambiguation) (Submatch[V], bool) {
+	vm := &pikeVM[S, V]{
+		input: input,
+		seen:  make([]int, machine.nextStateID),
+	}
+
+	var match Submatch[V]
+	found := false
+
+	slots := make([]int, 2*max(len(machine.captureNames), 1))
+
+	for idx := range slots {
+		slots[idx] = -1
+	}
+
+	slots[0] = start
+
+	vm.generation++
+	current := vm.add(nil, machine.startState, slots, start)
+
+	for offset := start; len(current) > 0; offset++ {
+		var next []pikeThread[S, V]
+		var best *pikeThread[S, V]
+
+		vm.generation++
+
+		for idx := range current {
+			thread := &current[idx]
+
+			if thread.state.IsAccepting() {
+				if best == nil || thread.state.acceptIdx < best.state.acceptIdx {
+					best = thread
+				}
+
+				if disambiguation == LeftmostFirst {
+					break // NOTE: The paths with a lower priority are cut.
+				}
+			}
+
+			if offset < len(input) {
+				next = vm.step(next, thread, offset)
+			}
+		}
+
+		if best != nil {
+			match = Submatch[V]{
+				Match: Match[V]{
+					Value:     best.state.value,
+					AcceptIdx: best.state.acceptIdx,
+					Length:    offset - start,
+				},
+				Slots: slices.Clone(best.slots),
+			}
+
+			match.Slots[1] = offset
+			found = true
+		}
+
+		if offset == len(input) {
+			break
+		}
+
+		current = next
+	}
+
+	return match, found
+}
+
+// A 'pikeThread' is an active [State] together with the slots of the path that reached it.
+type pikeThread[S comparable, V any] struct {
+	state *State[S, V]
+	slots []int // Shared between threads, so it's copied before it's modified.
+}
+
+// A 'pikeVM' holds the state of [Nfa.MatchSubmatch] for a single input.
+type pikeVM[S comparable, V any] struct {
+	input      []S
+	seen       []int // The generation in which every [State] was last added, by ID.
+	generation int
+}
+
+// Appends the threads for state and all the [State]s reachable from it through epsilon and assertion transitions at
+// offset to threads, in order of priority, and returns the result.
+func (vm *pikeVM[S, V]) add(
+	threads []pikeThread[S, V], state *State[S, V], slots []int, offset int,
+) []pikeThread[S, V] {
+	stack := []pikeThread[S, V]{{state: state, slots: slots}}
+
+	for len(stack) > 0 {
+		thread := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if vm.seen[thread.state.id] == vm.generation {
+			continue
+		}
+
+		vm.seen[thread.state.id] = vm.generation
+
+		if thread.state.tag > 0 {
+			thread.slots = slices.Clone(thread.slots)
+			thread.slots[thread.state.tag] = offset
+		}
+
+		threads = append(threads, thread)
+
+		// NOTE: The transitions are pushed in reverse order, so that the one with the highest priority is popped first.
+		assertions := thread.state.assertions
+
+		for idx := len(assertions) - 1; idx >= 0; idx-- {
+			if assertions[idx].Assertion.Holds(KindAt(vm.input, offset-1), KindAt(vm.input, offset)) {
+				stack = append(stack, pikeThread[S, V]{state: assertions[idx].EndState, slots: thread.slots})
+			}
+		}
+
+		for idx := len(thread.state.eTransitions) - 1; idx >= 0; idx-- {
+			stack = append(stack, pikeThread[S, V]{state: thread.state.eTransitions[idx], slots: thread.slots})
+		}
+	}
+
+	return threads
+}
+
+// Appends the threads that are reached from thread by consuming the symbol at offset to threads and returns the result.
+func (vm *pikeVM[S, V]) step(threads []pikeThread[S, V], thread *pikeThread[S, V], offset int) []pikeThread[S, V] {
+	symbol := vm.input[offset]
+
+	for _, target := range thread.state.OutgoingFor(symbol) {
+		threads = vm.add(threads, target, thread.slots, offset+1)
+	}
+
+	for _, transition := range thread.state.predicateTransitions {
+		if transition.Fn(symbol) {
+			threads = vm.add(threads, transition.EndState, thread.slots, offset+1)
+		}
+	}
+
+	return threads
+}
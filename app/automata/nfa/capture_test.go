@@ -0,0 +1,114 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Returns an 'Nfa' for (a|ab)(c|bcd)? where the alternatives are captured in the groups "x" and "y".
+func newCaptureMachine() *nfa.Nfa[rune, int] {
+	machine := nfa.New[rune, int]()
+
+	x := machine.NewCapture("x")
+	y := machine.NewCapture("y")
+
+	xStart, xEnd := machine.AddTag(machine.Start(), 2*x), machine.NewState()
+	machine.ConnectEpsilon(machine.Add(machine.AddEpsilonTransition(xStart), 'a'), xEnd)
+	machine.ConnectEpsilon(machine.Add(machine.Add(machine.AddEpsilonTransition(xStart), 'a'), 'b'), xEnd)
+
+	afterX, end := machine.AddTag(xEnd, 2*x+1), machine.NewState()
+	yStart, yEnd := machine.AddTag(afterX, 2*y), machine.NewState()
+	machine.ConnectEpsilon(afterX, end)
+	machine.ConnectEpsilon(machine.Add(machine.AddEpsilonTransition(yStart), 'c'), yEnd)
+	bcd := machine.Add(machine.Add(machine.Add(machine.AddEpsilonTransition(yStart), 'b'), 'c'), 'd')
+	machine.ConnectEpsilon(bcd, yEnd)
+	machine.ConnectEpsilon(machine.AddTag(yEnd, 2*y+1), end)
+	machine.AddAcceptingEpsilonTransition(end, 1)
+
+	return machine
+}
+
+// UT: Match input against an 'Nfa' with capture groups.
+func TestNfa_MatchSubmatch(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := newCaptureMachine()
+
+	for _, tc := range []struct {
+		name           string
+		input          string
+		disambiguation nfa.Disambiguation
+		want           []int
+	}{
+		{"the longest match is preferred", "abcd", nfa.LeftmostLongest, []int{0, 4, 0, 1, 1, 4}},
+		{"the first alternatives are preferred", "abc", nfa.LeftmostFirst, []int{0, 1, 0, 1, -1, -1}},
+		{"only the longest match is considered", "abc", nfa.LeftmostLongest, []int{0, 3, 0, 2, 2, 3}},
+		{"an optional group doesn't participate", "ab", nfa.LeftmostLongest, []int{0, 2, 0, 2, -1, -1}},
+	} {
+		// Act.
+		match, ok := machine.MatchSubmatch([]rune(tc.input), 0, tc.disambiguation)
+
+		// Assert.
+		assert.Truef(t, ok, "\n\n"+
+			"UT Name:  When %s, %q is matched.\n"+
+			"\033[32mExpected: true.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", tc.name, tc.input, ok)
+
+		assert.EqualSf(t, match.Slots, tc.want, "\n\n"+
+			"UT Name:  When %s, the offsets of the capture groups of %q are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", tc.name, tc.input, tc.want, match.Slots)
+	}
+
+	t.Run("When a group doesn't participate in the match, it isn't reported.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		match, _ := machine.MatchSubmatch([]rune("ab"), 0, nfa.LeftmostLongest)
+		_, _, got := match.Group(machine.CaptureIndex("y"))
+
+		// Assert.
+		assert.Falsef(t, got, "\n\n"+
+			"UT Name:  When a group doesn't participate in the match, it isn't reported.\n"+
+			"\033[32mExpected: false.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", got)
+	})
+
+	t.Run("When the slot of a tag doesn't belong to a capture group, a panic is raised.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Assert.
+		assert.Panicf(t, func() { nfa.New[rune, int]().AddTag(machine.Start(), 2) }, "\n\n"+
+			"UT Name:  When the slot of a tag doesn't belong to a capture group, a panic is raised.\n"+
+			"\033[32mExpected: panic.\033[0m\n"+
+			"\033[31mActual:   NO panic.\033[0m\n\n")
+	})
+}
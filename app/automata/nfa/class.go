@@ -0,0 +1,42 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+// Class is a set of symbols that, unlike the function of a predicate transition, can be inspected.
+//
+// Reasoning:
+// When the predicates leaving a state are opaque functions, it's impossible to know which combinations of them can hold
+// for the same symbol, so every combination must be accounted for when determinizing. The boundaries of a class make it
+// possible to find exactly the combinations that can hold, by evaluating all the classes on every boundary.
+type Class[S comparable] interface {
+	// Contains reports whether symbol is a member of the class.
+	Contains(symbol S) bool
+
+	// Boundaries returns the symbols where the membership of the class may change: the class either contains all the
+	// symbols from one boundary up to (but excluding) the next one, or none of them. The smallest possible symbol must be
+	// included, so that every symbol belongs to the interval of a boundary.
+	Boundaries() []S
+}
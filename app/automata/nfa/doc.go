@@ -30,7 +30,19 @@
 //   - Transitions on concrete symbols.
 //   - Epsilon transitions.
 //   - Predicate-based transitions via functions of type func(S) bool.
+//   - Assertion transitions that don't consume a symbol, but check the symbols around the current position.
 //
 // States can be marked as accepting and carry an associated value of type V, which can later be used by a matcher or
 // engine built on top of this package.
+//
+// Input can be matched against an [Nfa] directly, without converting it into a deterministic automaton, using
+// [Nfa.Match]. Tagged states record where the capture groups of a match start and end, which [Nfa.MatchSubmatch]
+// reports.
+//
+// The symbols of an [Nfa] over an ordered symbol type can be partitioned into equivalence classes of symbols that no
+// transition distinguishes with [AlphabetOf] (or, for the symbols with a concrete transition, with [SymbolClasses]),
+// so that automata can be built over the classes instead of the symbols.
+//
+// The structure of an [Nfa] can be inspected with [Nfa.Reachable] and [State.Edges], which walk its reachable states
+// and their transitions without exposing its internals.
 package nfa
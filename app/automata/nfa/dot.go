@@ -0,0 +1,125 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/kdeconinck/realign/automata/internal/dot"
+)
+
+// DOTOptions configures the output of [WriteDOT].
+type DOTOptions[S comparable, V any] = dot.Options[S, V]
+
+// WriteDOT writes machine to w in the DOT language of Graphviz.
+//
+// Every [State] that's reachable from the start state is drawn, labeled with its ID. Accepting states are drawn as a
+// double circle, labeled with their accepting value and acceptance index. Epsilon and assertion transitions are drawn
+// dashed (the latter labeled with their assertion) and predicate transitions dotted, labeled with their class if it
+// implements [fmt.Stringer]. The output only depends on the structure of machine, so it can be compared against a
+// golden file.
+func WriteDOT[S comparable, V any](w io.Writer, machine *Nfa[S, V], opts DOTOptions[S, V]) error {
+	name := opts.Name
+
+	if name == "" {
+		name = "nfa"
+	}
+
+	graph := dot.New(name, machine.Start().ID())
+	states := reachableStates(machine)
+
+	for _, state := range states {
+		dot.State(graph, opts, state.id, state.acceptIdx, state.value)
+	}
+
+	for _, state := range states {
+		symbolsByTarget := make(map[*State[S, V]][]S)
+		var targets []*State[S, V]
+
+		for _, symbol := range state.OutgoingSymbols() {
+			for _, target := range state.OutgoingFor(symbol) {
+				if _, ok := symbolsByTarget[target]; !ok {
+					targets = append(targets, target)
+				}
+
+				symbolsByTarget[target] = append(symbolsByTarget[target], symbol)
+			}
+		}
+
+		slices.SortFunc(targets, func(a, b *State[S, V]) int { return a.id - b.id })
+
+		for _, target := range targets {
+			dot.Symbols(graph, opts, state.id, target.id, symbolsByTarget[target])
+		}
+
+		for _, transition := range state.predicateTransitions {
+			label := "predicate"
+
+			if stringer, ok := transition.Class.(fmt.Stringer); ok {
+				label = stringer.String()
+			}
+
+			dot.Edge(graph, state.id, transition.EndState.id, label, "dotted")
+		}
+
+		for _, target := range state.eTransitions {
+			dot.Edge(graph, state.id, target.id, "ε", "dashed")
+		}
+
+		for _, transition := range state.assertions {
+			dot.Edge(graph, state.id, transition.EndState.id, transition.Assertion.String(), "dashed")
+		}
+	}
+
+	_, err := graph.WriteTo(w)
+
+	return err
+}
+
+// Returns all the [State]s of machine that are reachable from its start state, ordered by their ID.
+func reachableStates[S comparable, V any](machine *Nfa[S, V]) []*State[S, V] {
+	seen := map[*State[S, V]]bool{machine.Start(): true}
+	states := []*State[S, V]{machine.Start()}
+
+	visit := func(state *State[S, V]) {
+		if !seen[state] {
+			seen[state] = true
+			states = append(states, state)
+		}
+	}
+
+	for idx := 0; idx < len(states); idx++ {
+		for edge := range states[idx].Edges() {
+			visit(edge.Target)
+		}
+	}
+
+	slices.SortFunc(states, func(a, b *State[S, V]) int { return a.id - b.id })
+
+	return states
+}
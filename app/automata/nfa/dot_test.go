@@ -0,0 +1,84 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// UT: Write an 'Nfa' in the DOT language.
+func TestWriteDOT(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := nfa.New[rune, string]()
+	end := machine.NewState()
+
+	machine.ConnectEpsilon(machine.Add(machine.Start(), 'a'), end)
+	machine.ConnectEpsilon(machine.Add(machine.Start(), '"'), end)
+	machine.AddPredicateTransition(machine.Start(), end, unicode.IsDigit)
+	machine.AddAcceptingEpsilonTransition(end, "ID")
+
+	var sb strings.Builder
+
+	// Act.
+	err := nfa.WriteDOT(&sb, machine, nfa.DOTOptions[rune, string]{Name: "golden"})
+
+	// Assert.
+	assert.Nilf(t, err, "\n\n"+
+		"UT Name:  When writing an NFA in the DOT language, NO error is returned.\n"+
+		"\033[32mExpected: <nil>.\033[0m\n"+
+		"\033[31mActual:   %v.\033[0m\n\n", err)
+
+	want := `digraph "golden" {
+	rankdir=LR;
+	node [shape=circle];
+	__start [shape=point];
+	__start -> 0;
+	0;
+	1;
+	2;
+	3;
+	4 [shape=doublecircle, label="4\nID #0"];
+	0 -> 2 [label="'a'"];
+	0 -> 3 [label="'\"'"];
+	0 -> 1 [style=dotted, label="predicate"];
+	1 -> 4 [style=dashed, label="ε"];
+	2 -> 1 [style=dashed, label="ε"];
+	3 -> 1 [style=dashed, label="ε"];
+}
+`
+
+	assert.Equalf(t, sb.String(), want, "\n\n"+
+		"UT Name:  When writing an NFA in the DOT language, the output is correct.\n"+
+		"\033[32mExpected: %s.\033[0m\n"+
+		"\033[31mActual:   %s.\033[0m\n\n", want, sb.String())
+}
@@ -0,0 +1,176 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+// Match is the result of matching input against an [Nfa].
+type Match[V any] struct {
+	Value     V   // The accepting value of the match.
+	AcceptIdx int // The acceptance index of the accepting [State] that produced the match.
+	Length    int // The number of symbols that were consumed.
+}
+
+// Match matches the longest prefix of input against the nfa, without converting it into a deterministic automaton.
+//
+// The nfa is simulated using Thompson's algorithm: the set of active [State]s is tracked while input is consumed
+// symbol by symbol, following concrete, predicate and epsilon transitions. The simulation stops as soon as no [State]
+// is active anymore or when the whole input has been consumed.
+//
+// Assertion transitions are taken where their assertion holds between the last consumed symbol and the next one. The
+// start of input is the start of the input as far as the assertions are concerned (see [Nfa.MatchAt]).
+//
+// When the longest prefix is matched by multiple accepting [State]s, the one with the lowest acceptance index wins.
+// If no prefix of input (including the empty one) is accepted, Match returns false.
+func (machine *Nfa[S, V]) Match(input []S) (Match[V], bool) {
+	return machine.MatchAt(input, 0)
+}
+
+// MatchAt matches the longest prefix of input[start:] against the nfa, just like [Nfa.Match], except that the symbols
+// before start are taken into account by the assertion transitions.
+func (machine *Nfa[S, V]) MatchAt(input []S, start int) (Match[V], bool) {
+	sim := newSimulation(machine)
+
+	var match Match[V]
+	found := false
+
+	sim.closure(machine.startState)
+
+	for length := 0; ; length++ {
+		if machine.hasAssertions {
+			sim.resolve(KindAt(input, start+length-1), KindAt(input, start+length))
+		}
+
+		if state := sim.bestAccepting(); state != nil {
+			match = Match[V]{
+				Value:     state.value,
+				AcceptIdx: state.acceptIdx,
+				Length:    length,
+			}
+
+			found = true
+		}
+
+		if start+length == len(input) || !sim.step(input[start+length]) {
+			break
+		}
+	}
+
+	return match, found
+}
+
+// A 'simulation' tracks the set of active [State]s of an [Nfa] while input is consumed.
+//
+// Reasoning:
+// The identifiers of the [State]s of an [Nfa] are dense (they start at 0 and increase by 1), so instead of hashing
+// [State]s in a map, a slice indexed by identifier is used to record in which generation a [State] was last added to
+// the active set. Starting a new generation clears the set without touching the slice.
+type simulation[S comparable, V any] struct {
+	current    []*State[S, V]
+	next       []*State[S, V]
+	seen       []int
+	generation int
+}
+
+func newSimulation[S comparable, V any](machine *Nfa[S, V]) *simulation[S, V] {
+	return &simulation[S, V]{
+		current:    nil,
+		next:       nil,
+		seen:       make([]int, machine.nextStateID),
+		generation: 1,
+	}
+}
+
+// Adds state and all the [State]s reachable from it by following zero or more epsilon transitions to the active set.
+// Epsilon cycles are safe since every [State] is visited at most once per generation.
+func (sim *simulation[S, V]) closure(states ...*State[S, V]) {
+	stack := states
+
+	for len(stack) > 0 {
+		state := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if sim.seen[state.id] == sim.generation {
+			continue
+		}
+
+		sim.seen[state.id] = sim.generation
+		sim.current = append(sim.current, state)
+
+		stack = append(stack, state.eTransitions...)
+	}
+}
+
+// Adds the [State]s that are reachable from the active set through assertion transitions that hold between a symbol of
+// kind prev and one of kind next (and epsilon transitions) to the active set.
+func (sim *simulation[S, V]) resolve(prev, next SymbolKind) {
+	// NOTE: The active set grows while it's being traversed, so that the [State]s that are added are resolved as well.
+	for idx := 0; idx < len(sim.current); idx++ {
+		for _, transition := range sim.current[idx].assertions {
+			if transition.Assertion.Holds(prev, next) {
+				sim.closure(transition.EndState)
+			}
+		}
+	}
+}
+
+// Consumes symbol and reports whether at least one [State] is still active afterwards.
+func (sim *simulation[S, V]) step(symbol S) bool {
+	targets := sim.next[:0]
+
+	for _, state := range sim.current {
+		targets = append(targets, state.OutgoingFor(symbol)...)
+
+		for _, transition := range state.predicateTransitions {
+			if transition.Fn(symbol) {
+				targets = append(targets, transition.EndState)
+			}
+		}
+	}
+
+	sim.next = targets
+	sim.current = sim.current[:0]
+	sim.generation++
+
+	sim.closure(targets...)
+
+	return len(sim.current) > 0
+}
+
+// Returns the active accepting [State] with the lowest acceptance index or nil if no active [State] is accepting.
+func (sim *simulation[S, V]) bestAccepting() *State[S, V] {
+	var best *State[S, V]
+
+	for _, state := range sim.current {
+		if !state.IsAccepting() {
+			continue
+		}
+
+		if best == nil || state.acceptIdx < best.acceptIdx {
+			best = state
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,202 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// UT: Match input against an 'Nfa'.
+func TestNfa_Match(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When no prefix of the input is accepted, false is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[rune, int]()
+
+		machine.AddAcceptingEpsilonTransition(machine.Add(machine.Start(), 'a'), 1)
+
+		// Act.
+		_, got := machine.Match([]rune("b"))
+
+		// Assert.
+		assert.Falsef(t, got, "\n\n"+
+			"UT Name:  When no prefix of the input is accepted, false is returned.\n"+
+			"\033[32mExpected: false.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", got)
+	})
+
+	t.Run("When multiple prefixes are accepted, the longest one is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[rune, int]()
+
+		s1 := machine.Add(machine.Start(), 'a')
+		s2 := machine.Add(s1, 'b')
+
+		machine.AddAcceptingEpsilonTransition(s1, 1)
+		machine.AddAcceptingEpsilonTransition(s2, 2)
+
+		// Act.
+		match, ok := machine.Match([]rune("abc"))
+
+		// Assert.
+		assert.Truef(t, ok, "\n\n"+
+			"UT Name:  When multiple prefixes are accepted, true is returned.\n"+
+			"\033[32mExpected: true.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", ok)
+
+		assert.Equalf(t, match.Length, 2, "\n\n"+
+			"UT Name:  When multiple prefixes are accepted, the length of the longest one is returned.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 2, match.Length)
+
+		assert.Equalf(t, match.Value, 2, "\n\n"+
+			"UT Name:  When multiple prefixes are accepted, the value of the longest one is returned.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 2, match.Value)
+	})
+
+	t.Run("When the longest prefix is accepted multiple times, the lowest acceptance index wins.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[rune, string]()
+
+		s1 := machine.Add(machine.Start(), 'a')
+		s2 := machine.Add(machine.Start(), 'a')
+
+		machine.AddAcceptingEpsilonTransition(s2, "WIN")
+		machine.AddAcceptingEpsilonTransition(s1, "LOSE")
+
+		// Act.
+		match, _ := machine.Match([]rune("a"))
+
+		// Assert.
+		assert.Equalf(t, match.Value, "WIN", "\n\n"+
+			"UT Name:  When the longest prefix is accepted multiple times, the lowest acceptance index wins.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", "WIN", match.Value)
+
+		assert.Equalf(t, match.AcceptIdx, 0, "\n\n"+
+			"UT Name:  When the longest prefix is accepted multiple times, the acceptance index is returned.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 0, match.AcceptIdx)
+	})
+
+	t.Run("When the nfa contains predicate transitions, they are honoured.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[rune, int]()
+		digit := machine.NewState()
+
+		machine.AddPredicateTransition(machine.Start(), digit, func(r rune) bool { return r >= '0' && r <= '9' })
+		machine.AddPredicateTransition(digit, digit, func(r rune) bool { return r >= '0' && r <= '9' })
+		machine.AddAcceptingEpsilonTransition(digit, 1)
+
+		// Act.
+		match, _ := machine.Match([]rune("2025-01-01"))
+
+		// Assert.
+		assert.Equalf(t, match.Length, 4, "\n\n"+
+			"UT Name:  When the nfa contains predicate transitions, they are honoured.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 4, match.Length)
+	})
+
+	t.Run("When the nfa contains epsilon cycles, the simulation terminates.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[rune, int]()
+
+		s1 := machine.AddEpsilonTransition(machine.Start())
+		s2 := machine.AddEpsilonTransition(s1)
+
+		machine.ConnectEpsilon(s2, machine.Start())
+		machine.ConnectEpsilon(machine.Add(s2, 'a'), s1)
+		machine.AddAcceptingEpsilonTransition(s2, 1)
+
+		// Act.
+		match, _ := machine.Match([]rune("aaa"))
+
+		// Assert.
+		assert.Equalf(t, match.Length, 3, "\n\n"+
+			"UT Name:  When the nfa contains epsilon cycles, the simulation terminates.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 3, match.Length)
+	})
+
+	t.Run("When the start state is accepting, the empty prefix is accepted.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[rune, int]()
+
+		machine.AddAcceptingEpsilonTransition(machine.Start(), 1)
+
+		// Act.
+		match, ok := machine.Match([]rune("a"))
+
+		// Assert.
+		assert.Truef(t, ok && match.Length == 0, "\n\n"+
+			"UT Name:  When the start state is accepting, the empty prefix is accepted.\n"+
+			"\033[32mExpected: true (length 0).\033[0m\n"+
+			"\033[31mActual:   %t (length %d).\033[0m\n\n", ok, match.Length)
+	})
+}
+
+var benchmarkMatchOutput nfa.Match[int] // Output of the benchmark(s).
+
+// Benchmark(s): Match input against an nfa with a Kleene star.
+func BenchmarkNfa_Match_10(b *testing.B)    { benchmarkNfa_Match(10, b) }
+func BenchmarkNfa_Match_1000(b *testing.B)  { benchmarkNfa_Match(1_000, b) }
+func BenchmarkNfa_Match_10000(b *testing.B) { benchmarkNfa_Match(10_000, b) }
+
+func benchmarkNfa_Match(count int, b *testing.B) {
+	machine := nfa.New[int, int]()
+	loop := machine.AddEpsilonTransition(machine.Start())
+
+	machine.ConnectEpsilon(machine.Add(loop, 1), loop)
+	machine.AddAcceptingEpsilonTransition(loop, 1)
+
+	input := make([]int, count)
+
+	for idx := range input {
+		input[idx] = 1
+	}
+
+	for b.Loop() {
+		benchmarkMatchOutput, _ = machine.Match(input)
+	}
+}
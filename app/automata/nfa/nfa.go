@@ -30,6 +30,9 @@ type Nfa[S comparable, V any] struct {
 	startState      *State[S, V]
 	nextStateID     int
 	nextAcceptIndex int
+	hasAssertions   bool
+	captureNames    []string    // The names of the capture groups, indexed by group (nil if there are none).
+	captureKeys     map[any]int // The capture group registered for every key (see [Nfa.CaptureFor]).
 }
 
 // New creates a new [Nfa] with an initial start [State].
@@ -77,7 +80,7 @@ func (machine *Nfa[S, V]) AddAcceptingEpsilonTransition(startState *State[S, V],
 // AddPredicateTransition adds and returns a new predicate transition from startState to endState.
 // The predicate function fn is used to determine if the transition is valid for a given symbol.
 func (machine *Nfa[S, V]) AddPredicateTransition(startState, endState *State[S, V], fn func(S) bool) {
-	transition := predicateTransition[S, V]{
+	transition := PredicateTransition[S, V]{
 		EndState: endState,
 		Fn:       fn,
 	}
@@ -85,6 +88,23 @@ func (machine *Nfa[S, V]) AddPredicateTransition(startState, endState *State[S,
 	startState.predicateTransitions = append(startState.predicateTransitions, transition)
 }
 
+// Connect adds a transition from startState to endState for symbol.
+func (machine *Nfa[S, V]) Connect(startState, endState *State[S, V], symbol S) {
+	startState.put(symbol, endState)
+}
+
+// AddClassTransition adds a new predicate transition from startState to endState for the symbols of class.
+// Unlike a predicate function, a class can be inspected, which makes it possible to determinize it exactly.
+func (machine *Nfa[S, V]) AddClassTransition(startState, endState *State[S, V], class Class[S]) {
+	transition := PredicateTransition[S, V]{
+		EndState: endState,
+		Fn:       class.Contains,
+		Class:    class,
+	}
+
+	startState.predicateTransitions = append(startState.predicateTransitions, transition)
+}
+
 // ConnectEpsilon adds an epsilon transition from from to to.
 func (machine *Nfa[S, V]) ConnectEpsilon(startState *State[S, V], endState *State[S, V]) {
 	startState.eTransitions = append(startState.eTransitions, endState)
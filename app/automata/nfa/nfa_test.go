@@ -26,6 +26,7 @@
 package nfa_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/kdeconinck/realign/assert"
@@ -170,6 +171,65 @@ func TestNfa_AddPredicateTransition(t *testing.T) {
 		"\033[31mActual:   panic.\033[0m\n\n")
 }
 
+// A 'positive' is the class of positive integers.
+type positive struct{}
+
+func (positive) Contains(i int) bool { return i > 0 }
+func (positive) Boundaries() []int   { return []int{math.MinInt, 1} }
+
+// UT: Add a class transition to an `Nfa`.
+func TestNfa_AddClassTransition(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := nfa.New[int, int]()
+	startState := machine.Start()
+	endState := machine.NewState()
+
+	// Act.
+	machine.AddClassTransition(startState, endState, positive{})
+
+	// Assert.
+	got := startState.Predicates()
+
+	assert.Equalf(t, len(got), 1, "\n\n"+
+		"UT Name:  When adding a class transition, a predicate transition is added.\n"+
+		"\033[32mExpected: 1.\033[0m\n"+
+		"\033[31mActual:   %d.\033[0m\n\n", len(got))
+
+	assert.Equalf(t, got[0].Class, nfa.Class[int](positive{}), "\n\n"+
+		"UT Name:  When adding a class transition, the predicate transition holds the class.\n"+
+		"\033[32mExpected: %v.\033[0m\n"+
+		"\033[31mActual:   %v.\033[0m\n\n", positive{}, got[0].Class)
+
+	assert.Truef(t, got[0].Fn(1) && !got[0].Fn(0), "\n\n"+
+		"UT Name:  When adding a class transition, the predicate holds for the members of the class.\n"+
+		"\033[32mExpected: true.\033[0m\n"+
+		"\033[31mActual:   false.\033[0m\n\n")
+}
+
+// UT: Connect two 'State's with a transition for a symbol.
+func TestNfa_Connect(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := nfa.New[int, int]()
+	startState := machine.Start()
+	endState := machine.NewState()
+
+	// Act.
+	machine.Connect(startState, endState, 1)
+	machine.Connect(startState, startState, 1)
+
+	// Assert.
+	got := startState.OutgoingFor(1)
+
+	assert.EqualSf(t, got, []*nfa.State[int, int]{endState, startState}, "\n\n"+
+		"UT Name:  When connecting 'State's with a transition for a symbol, the 'State's are reachable by that symbol.\n"+
+		"\033[32mExpected: [%v %v].\033[0m\n"+
+		"\033[31mActual:   %v.\033[0m\n\n", endState, startState, got)
+}
+
 // UT: Connect two 'State's with an epsilon transition.
 func TestNfa_ConnectEpsilon(t *testing.T) {
 	t.Parallel() // Enable parallel execution.
@@ -25,14 +25,15 @@
 
 package nfa
 
-// A 'predicate' transition is a transition that is based on a function.
+// PredicateTransition is a transition that is based on a function.
 // The function is used to determine if the transition is valid for a given symbol.
 //
 // Reasoning: This mechanism significantly simplifies the state machine definition when a transition should be triggered
 // by a set of input symbols (e.g., "any digit," "any letter"). It provides a concise alternative to defining numerous,
 // explicit transitions for each possible symbol in the set or relying on epsilon transitions, thereby reducing the
 // overall complexity of the state machine.
-type predicateTransition[S comparable, V any] struct {
-	EndState *State[S, V]
-	Fn       func(S) bool
+type PredicateTransition[S comparable, V any] struct {
+	EndState *State[S, V] // The [State] that is reached when the transition is taken.
+	Fn       func(S) bool // Reports whether the transition is valid for a given symbol.
+	Class    Class[S]     // The class that Fn was taken from (nil if Fn is an opaque function).
 }
@@ -33,15 +33,19 @@ import "github.com/kdeconinck/realign/collections/mvmap"
 //   - Zero or more outgoing transitions on concrete symbols.
 //   - Zero or more predicate-based transitions.
 //   - Zero or more epsilon transitions.
+//   - Zero or more assertion transitions.
 //   - An optional accepting index and value.
 type State[S comparable, V any] struct {
 	id                   int
 	edge                 edge[S, V]
 	transitions          *mvmap.MvMap[S, *State[S, V]]
-	predicateTransitions []predicateTransition[S, V]
+	predicateTransitions []PredicateTransition[S, V]
 	eTransitions         []*State[S, V]
+	assertions           []AssertionTransition[S, V]
 	acceptIdx            int
-	value                V // The accepting value (if any).
+	value                V                   // The accepting value (if any).
+	trailing             *TrailingContext[S] // The trailing context of the matches of the state (if any).
+	tag                  int                 // The slot that's recorded when the state is entered (0 if none).
 }
 
 // NewState returns a new, non-accepting [State].
@@ -85,6 +89,12 @@ func (state *State[S, V]) Epsilon() []*State[S, V] {
 	return state.eTransitions
 }
 
+// Predicates returns the predicate transitions starting from the state.
+// Note: The returned slice is the one stored inside the state; callers should NOT modify it.
+func (state *State[S, V]) Predicates() []PredicateTransition[S, V] {
+	return state.predicateTransitions
+}
+
 // OutgoingSymbols returns all the symbols that have at least one outgoing transition from this state.
 // Note: The order is undefined.
 func (state *State[S, V]) OutgoingSymbols() []S {
@@ -0,0 +1,62 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+// TrailingContext describes the trailing context of an accepting [State]: the symbols at the end of a match that must
+// be present for the match to be accepted, but that aren't part of the token (like flex's r/s rules).
+//
+// When the length of the token or the length of the trailing context is fixed, the end of the token is computed from
+// that length. Otherwise, Split is used to find it.
+type TrailingContext[S comparable] struct {
+	HeadLen int                 // The length of the token if it's fixed (-1 otherwise).
+	TailLen int                 // The length of the trailing context if it's fixed (-1 otherwise).
+	Split   func(match []S) int // Returns the length of the token in match (only used if neither length is fixed).
+}
+
+// TokenLen returns the length of the token in match, which is a complete match of the accepting [State].
+func (tc *TrailingContext[S]) TokenLen(match []S) int {
+	switch {
+	case tc.HeadLen >= 0:
+		return tc.HeadLen
+	case tc.TailLen >= 0:
+		return len(match) - tc.TailLen
+	default:
+		return tc.Split(match)
+	}
+}
+
+// SetTrailingContext records that the matches of the accepting state end in the trailing context tc.
+// Panics if state isn't accepting.
+func (machine *Nfa[S, V]) SetTrailingContext(state *State[S, V], tc *TrailingContext[S]) {
+	if !state.IsAccepting() {
+		panic("SetTrailingContext: state must be accepting")
+	}
+
+	state.trailing = tc
+}
+
+// TrailingContext returns the trailing context of the state or nil if its matches don't end in trailing context.
+func (state *State[S, V]) TrailingContext() *TrailingContext[S] { return state.trailing }
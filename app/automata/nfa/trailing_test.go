@@ -0,0 +1,99 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// UT: Compute the length of the token in a match that ends in trailing context.
+func TestTrailingContext_TokenLen(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	split := func(match []int) int { return len(match) / 2 }
+
+	for _, tc := range []struct {
+		name    string
+		context nfa.TrailingContext[int]
+		want    int
+	}{
+		{"a fixed head", nfa.TrailingContext[int]{HeadLen: 1, TailLen: -1}, 1},
+		{"a fixed tail", nfa.TrailingContext[int]{HeadLen: -1, TailLen: 1}, 5},
+		{"a variable head and tail", nfa.TrailingContext[int]{HeadLen: -1, TailLen: -1, Split: split}, 3},
+	} {
+		// Act.
+		got := tc.context.TokenLen([]int{1, 2, 3, 4, 5, 6})
+
+		// Assert.
+		assert.Equalf(t, got, tc.want, "\n\n"+
+			"UT Name:  When the trailing context has %s, the length of the token is correct.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Set the trailing context of a 'State'.
+func TestNfa_SetTrailingContext(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When the state is accepting, the trailing context is recorded.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[int, int]()
+		state := machine.AddAcceptingEpsilonTransition(machine.Start(), 1)
+		context := &nfa.TrailingContext[int]{HeadLen: 1, TailLen: -1}
+
+		// Act.
+		machine.SetTrailingContext(state, context)
+
+		// Assert.
+		assert.Truef(t, state.TrailingContext() == context && machine.Start().TrailingContext() == nil, "\n\n"+
+			"UT Name:  When the state is accepting, the trailing context is recorded.\n"+
+			"\033[32mExpected: true.\033[0m\n"+
+			"\033[31mActual:   false.\033[0m\n\n")
+	})
+
+	t.Run("When the state isn't accepting, the function panics.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[int, int]()
+
+		// Act.
+		fn := func() { machine.SetTrailingContext(machine.Start(), &nfa.TrailingContext[int]{}) }
+
+		// Assert.
+		assert.Panicf(t, fn, "\n\n"+
+			"UT Name:  When the state isn't accepting, the function panics.\n"+
+			"\033[32mExpected: panic.\033[0m\n"+
+			"\033[31mActual:   NOT panic.\033[0m\n\n")
+	})
+}
@@ -0,0 +1,105 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa
+
+import (
+	"iter"
+	"slices"
+)
+
+// EdgeKind is the kind of an [Edge].
+type EdgeKind int
+
+const (
+	SymbolEdge    EdgeKind = iota // A transition on a concrete symbol.
+	EpsilonEdge                   // An epsilon transition.
+	PredicateEdge                 // A predicate transition.
+	AssertionEdge                 // An assertion transition.
+)
+
+// Edge is a transition leaving a [State], as reported by [State.Edges].
+type Edge[S comparable, V any] struct {
+	Kind      EdgeKind
+	Symbol    S                         // The symbol of a [SymbolEdge].
+	Predicate PredicateTransition[S, V] // The transition of a [PredicateEdge].
+	Assertion Assertion                 // The assertion of an [AssertionEdge].
+	Target    *State[S, V]              // The [State] that the transition leads to.
+}
+
+// Reachable returns an iterator over the [State]s that are reachable from the start state of the nfa, ordered by
+// their ID.
+func (machine *Nfa[S, V]) Reachable() iter.Seq[*State[S, V]] {
+	return slices.Values(reachableStates(machine))
+}
+
+// Edges returns an iterator over the transitions leaving the state: the transitions on concrete symbols, followed by
+// the predicate, the epsilon and the assertion transitions. The order of the symbols is undefined.
+func (state *State[S, V]) Edges() iter.Seq[Edge[S, V]] {
+	return func(yield func(Edge[S, V]) bool) {
+		for _, symbol := range state.OutgoingSymbols() {
+			for _, target := range state.OutgoingFor(symbol) {
+				if !yield(Edge[S, V]{Kind: SymbolEdge, Symbol: symbol, Target: target}) {
+					return
+				}
+			}
+		}
+
+		for _, transition := range state.predicateTransitions {
+			if !yield(Edge[S, V]{Kind: PredicateEdge, Predicate: transition, Target: transition.EndState}) {
+				return
+			}
+		}
+
+		for _, target := range state.eTransitions {
+			if !yield(Edge[S, V]{Kind: EpsilonEdge, Target: target}) {
+				return
+			}
+		}
+
+		for _, transition := range state.assertions {
+			if !yield(Edge[S, V]{Kind: AssertionEdge, Assertion: transition.Assertion, Target: transition.EndState}) {
+				return
+			}
+		}
+	}
+}
+
+// NumStates returns the number of [State]s that are reachable from the start state of the nfa.
+func (machine *Nfa[S, V]) NumStates() int { return len(reachableStates(machine)) }
+
+// NumTransitions returns the number of transitions (of any kind) leaving the [State]s that are reachable from the
+// start state of the nfa.
+func (machine *Nfa[S, V]) NumTransitions() int {
+	count := 0
+
+	for _, state := range reachableStates(machine) {
+		for range state.Edges() {
+			count++
+		}
+	}
+
+	return count
+}
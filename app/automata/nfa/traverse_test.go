@@ -0,0 +1,102 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package nfa_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// UT: Walk the reachable 'State's of an 'Nfa' and their transitions.
+func TestNfa_Reachable(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	machine := nfa.New[rune, int]()
+	s0 := machine.Start()
+
+	s1 := machine.Add(s0, 'a')
+	machine.AddAcceptingEpsilonTransition(s1, 1)
+
+	s3 := machine.NewState()
+	machine.AddPredicateTransition(s0, s3, func(r rune) bool { return r > 'a' })
+	machine.AddAssertion(s3, machine.NewState(), nfa.EndText)
+
+	machine.Add(machine.NewState(), 'z') // NOTE: This part isn't reachable.
+
+	// Act.
+	var ids []int
+
+	kinds := make(map[nfa.EdgeKind]int)
+
+	for state := range machine.Reachable() {
+		ids = append(ids, state.ID())
+
+		for edge := range state.Edges() {
+			kinds[edge.Kind]++
+		}
+	}
+
+	// Assert.
+	assert.EqualSf(t, ids, []int{0, 1, 2, 3, 4}, "\n\n"+
+		"UT Name:  When walking an 'Nfa', only the reachable 'State's are visited, ordered by their ID.\n"+
+		"\033[32mExpected: %v.\033[0m\n"+
+		"\033[31mActual:   %v.\033[0m\n\n", []int{0, 1, 2, 3, 4}, ids)
+
+	for kind, want := range map[nfa.EdgeKind]int{nfa.SymbolEdge: 1, nfa.EpsilonEdge: 1, nfa.PredicateEdge: 1,
+		nfa.AssertionEdge: 1} {
+		assert.Equalf(t, kinds[kind], want, "\n\n"+
+			"UT Name:  When walking an 'Nfa', every transition of kind %d is reported.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", kind, want, kinds[kind])
+	}
+
+	assert.Truef(t, machine.NumStates() == 5 && machine.NumTransitions() == 4, "\n\n"+
+		"UT Name:  When counting the reachable 'State's and transitions, the unreachable ones are ignored.\n"+
+		"\033[32mExpected: 5, 4.\033[0m\n"+
+		"\033[31mActual:   %d, %d.\033[0m\n\n", machine.NumStates(), machine.NumTransitions())
+
+	t.Run("When the walk is stopped early, no more edges are reported.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		count := 0
+
+		for range s0.Edges() {
+			count++
+
+			break
+		}
+
+		// Assert.
+		assert.Equalf(t, count, 1, "\n\n"+
+			"UT Name:  When the walk is stopped early, no more edges are reported.\n"+
+			"\033[32mExpected: 1.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", count)
+	})
+}
@@ -0,0 +1,43 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package codegen generates standalone Go source code for lexers.
+//
+// A lexer is described by a set of [Rule]s, each of which associates a name with a regular expression (see package
+// regex for the supported syntax). The rules are compiled into a minimal [dfa.Dfa], which is then written as a
+// direct-coded state machine: every state becomes a label and every transition a goto, so recognizing a token doesn't
+// involve a single map lookup. The generated code only depends on Go's standard library.
+//
+// The rules can be read from a file where every line holds the name of a rule followed by its pattern:
+//
+//	# Comments and blank lines are ignored.
+//	IF     if
+//	IDENT  [A-Za-z_][A-Za-z0-9_]*
+//	SPACE  [ \t\n]+
+//
+// When multiple rules match the same (longest) input, the rule that's declared first wins.
+package codegen
+
+import _ "github.com/kdeconinck/realign/automata/dfa"
@@ -0,0 +1,446 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"slices"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kdeconinck/realign/automata/dfa"
+)
+
+// Config configures the code written by [Generate].
+type Config struct {
+	Package string   // The name of the package of the generated file.
+	Names   []string // The names of the kinds of tokens, where the kind with accepting value v is named Names[v-1].
+}
+
+// The identifiers that are declared (or imported) at the package level of the generated code.
+// Since the kinds of tokens are declared as constants in the same package, they can't use these names.
+var reserved = map[string]bool{
+	"_":            true,
+	"ErrNoMatch":   true,
+	"Kind":         true,
+	"Lexer":        true,
+	"NewLexer":     true,
+	"Token":        true,
+	"errors":       true,
+	"io":           true,
+	"lexerAccepts": true,
+	"utf8":         true,
+}
+
+// The first and last surrogate halves, which are never produced when decoding UTF-8.
+const (
+	surrogateMin = 0xD800
+	surrogateMax = 0xDFFF
+)
+
+// Generate writes the Go source code of a lexer that recognizes the same tokens as machine to w.
+//
+// The generated file declares a Kind constant for every name in cfg, a Token type and a Lexer type that tokenizes UTF-8
+// encoded input using the longest match. The output is formatted with gofmt and only depends on the structure of
+// machine, so generating code for equivalent (minimal) machines always produces the same output.
+//
+// An error is returned if the accepting value of a state of machine doesn't correspond to a name in cfg or if machine
+// has assertions, trailing context or predicates that aren't built from classes (see [dfa.State.GuardBoundaries]),
+// which the generated lexer doesn't support.
+func Generate(w io.Writer, machine *dfa.Dfa[rune, int], cfg Config) error {
+	if machine.HasAssertions() {
+		return errors.New("codegen: assertions aren't supported")
+	}
+
+	for state := range machine.Reachable() {
+		if state.TrailingContext() != nil {
+			return fmt.Errorf("codegen: can't generate code for state %d, since it has trailing context", state.ID())
+		}
+
+		if _, ok := state.GuardBoundaries(); state.HasGuard() && !ok {
+			return fmt.Errorf("codegen: can't generate code for state %d, since it has opaque predicates", state.ID())
+		}
+	}
+
+	states := number(machine)
+
+	for _, state := range states {
+		if state.accept < 0 || state.accept > len(cfg.Names) {
+			return fmt.Errorf("codegen: no name for the accepting value %d", state.accept)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	writeHeader(&buf, cfg)
+	writeNext(&buf, cfg, states)
+
+	src, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return fmt.Errorf("codegen: format generated code: %w", err)
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+// A 'runeRange' is an inclusive range of runes.
+type runeRange struct {
+	lo rune
+	hi rune
+}
+
+// An 'edge' holds all the ranges of runes leading from a state to the same target.
+type edge struct {
+	ranges []runeRange
+	target int // The number of the target state.
+}
+
+// A 'genState' is a [dfa.State] in the form required to generate code for it.
+type genState struct {
+	accept int    // The accepting value of the state (0 if the state isn't accepting).
+	edges  []edge // The outgoing edges, ordered by the number of their target.
+}
+
+// Returns the states of machine that are reachable from its start state, numbered in breadth-first order.
+//
+// Reasoning:
+// The IDs of the states of a [dfa.Dfa] depend on the order in which they were constructed, which isn't guaranteed to
+// be stable. Visiting the outgoing transitions of every state in the order of their runes results in a numbering that
+// only depends on the structure of the machine.
+func number(machine *dfa.Dfa[rune, int]) []*genState {
+	numbers := map[*dfa.State[rune, int]]int{machine.Start(): 0}
+	order := []*dfa.State[rune, int]{machine.Start()}
+
+	var states []*genState
+
+	for idx := 0; idx < len(order); idx++ {
+		state := &genState{}
+
+		if order[idx].IsAccepting() {
+			state.accept = order[idx].AcceptValue()
+		}
+
+		edgeByTarget := make(map[int]int)
+
+		for _, a := range outgoing(order[idx]) {
+			target, ok := numbers[a.target]
+
+			if !ok {
+				target = len(order)
+				numbers[a.target] = target
+				order = append(order, a.target)
+			}
+
+			edgeIdx, ok := edgeByTarget[target]
+
+			if !ok {
+				edgeIdx = len(state.edges)
+				edgeByTarget[target] = edgeIdx
+				state.edges = append(state.edges, edge{target: target})
+			}
+
+			state.edges[edgeIdx].ranges = append(state.edges[edgeIdx].ranges, a.runeRange)
+		}
+
+		slices.SortFunc(state.edges, func(a, b edge) int { return a.target - b.target })
+
+		states = append(states, state)
+	}
+
+	return states
+}
+
+// An 'arc' is a range of runes leading from a state to target.
+type arc struct {
+	runeRange
+	target *dfa.State[rune, int]
+}
+
+// Returns the outgoing transitions of state as ranges of runes, ordered by their first rune.
+//
+// The transitions of a state with a guard (which is built from classes) are discovered by splitting the runes into
+// segments that lead to the same target: the boundaries of the guard, the concrete runes and the runes directly after
+// them. Surrogate halves are skipped, since they are never produced when decoding UTF-8. For the same reason, ranges
+// are merged across the surrogates.
+func outgoing(state *dfa.State[rune, int]) []arc {
+	var arcs []arc
+
+	add := func(lo, hi rune) {
+		target := state.OutgoingFor(lo)
+
+		if target == nil {
+			return
+		}
+
+		if n := len(arcs); n > 0 && arcs[n-1].target == target &&
+			(arcs[n-1].hi+1 == lo || (arcs[n-1].hi == surrogateMin-1 && lo == surrogateMax+1)) {
+			arcs[n-1].hi = hi
+
+			return
+		}
+
+		arcs = append(arcs, arc{runeRange: runeRange{lo: lo, hi: hi}, target: target})
+	}
+
+	symbols := state.Symbols()
+	slices.Sort(symbols)
+
+	if !state.HasGuard() {
+		for _, r := range symbols {
+			if utf8.ValidRune(r) {
+				add(r, r)
+			}
+		}
+
+		return arcs
+	}
+
+	for _, segment := range segments(state, symbols) {
+		add(segment.lo, segment.hi)
+	}
+
+	return arcs
+}
+
+// Returns the ranges of valid runes that lead to the same target from state, which has a guard that's built from
+// classes, in ascending order. The concrete runes of state are passed as symbols, in ascending order.
+func segments(state *dfa.State[rune, int], symbols []rune) []runeRange {
+	boundaries, _ := state.GuardBoundaries()
+
+	starts := append([]rune{0, surrogateMin, surrogateMax + 1}, boundaries...)
+
+	for _, r := range symbols {
+		starts = append(starts, r, r+1)
+	}
+
+	slices.Sort(starts)
+	starts = slices.Compact(starts)
+
+	var result []runeRange
+
+	for idx, lo := range starts {
+		hi := rune(unicode.MaxRune)
+
+		if idx+1 < len(starts) {
+			hi = starts[idx+1] - 1
+		}
+
+		if lo > unicode.MaxRune || (lo >= surrogateMin && lo <= surrogateMax) {
+			continue
+		}
+
+		result = append(result, runeRange{lo: lo, hi: hi})
+	}
+
+	return result
+}
+
+// Writes the declarations of the generated file, up to the state machine, to buf.
+func writeHeader(buf *bytes.Buffer, cfg Config) {
+	fmt.Fprintf(buf, `// Code generated by "realign gen"; DO NOT EDIT.
+
+package %s
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// Kind identifies the rule that recognized a [Token].
+type Kind int
+`, cfg.Package)
+
+	if len(cfg.Names) > 0 {
+		buf.WriteString("\n// The kinds of tokens.\nconst (\n")
+
+		for idx, name := range cfg.Names {
+			fmt.Fprintf(buf, "\t%s Kind = %d\n", name, idx+1)
+		}
+
+		buf.WriteString(")\n")
+	}
+
+	buf.WriteString(`
+// ErrNoMatch is returned by [Lexer.Next] when no rule matches the input.
+var ErrNoMatch = errors.New("no rule matches the input")
+
+// Token is a single lexeme recognized by a [Lexer].
+type Token struct {
+	Kind   Kind   // The rule that recognized the token.
+	Start  int    // The offset (in bytes) of the first byte of the token.
+	End    int    // The offset (in bytes) directly after the last byte of the token.
+	Lexeme []byte // The bytes that make up the token.
+}
+
+// Lexer splits UTF-8 encoded input into [Token]s.
+//
+// When multiple rules match, the one that matches the longest input wins. When multiple rules match the same input,
+// the one that was declared first wins.
+type Lexer struct {
+	input  []byte
+	offset int
+}
+
+// NewLexer creates a new [Lexer] that tokenizes input.
+func NewLexer(input []byte) *Lexer { return &Lexer{input: input} }
+
+// Offset returns the offset (in bytes) of the first byte that hasn't been consumed yet.
+func (lexer *Lexer) Offset() int { return lexer.offset }
+`)
+}
+
+// Writes the accept table and the state machine of the Next method to buf.
+func writeNext(buf *bytes.Buffer, cfg Config, states []*genState) {
+	targeted := make([]bool, len(states))
+	decodes := false
+
+	for _, state := range states {
+		for _, e := range state.edges {
+			targeted[e.target] = true
+			decodes = true
+		}
+	}
+
+	buf.WriteString("\n// The kind of token accepted by each state (0 if the state isn't accepting).\n")
+	buf.WriteString("var lexerAccepts = [...]Kind{")
+
+	for idx, state := range states {
+		if idx > 0 {
+			buf.WriteString(", ")
+		}
+
+		if state.accept == 0 {
+			buf.WriteString("0")
+		} else {
+			buf.WriteString(cfg.Names[state.accept-1])
+		}
+	}
+
+	buf.WriteString(`}
+
+// Next returns the next [Token] of the input.
+//
+// When all the input has been consumed, Next returns [io.EOF]. When no rule matches at the current offset, Next returns
+// [ErrNoMatch] and the offset is left untouched.
+func (lexer *Lexer) Next() (Token, error) {
+	if lexer.offset >= len(lexer.input) {
+		return Token{}, io.EOF
+	}
+
+	start, pos := lexer.offset, lexer.offset
+	lastState, lastEnd := 0, start
+`)
+
+	if decodes {
+		buf.WriteString("\n\tvar (\n\t\tr    rune\n\t\tsize int\n\t)\n")
+	}
+
+	for idx, state := range states {
+		buf.WriteString("\n")
+
+		if targeted[idx] {
+			fmt.Fprintf(buf, "s%d:\n", idx)
+		}
+
+		if state.accept != 0 {
+			fmt.Fprintf(buf, "\tlastState, lastEnd = %d, pos\n\n", idx)
+		}
+
+		if len(state.edges) == 0 {
+			buf.WriteString("\tgoto done\n")
+
+			continue
+		}
+
+		buf.WriteString("\tif pos >= len(lexer.input) {\n\t\tgoto done\n\t}\n\n")
+		buf.WriteString("\tr, size = utf8.DecodeRune(lexer.input[pos:])\n\tpos += size\n\n\tswitch {\n")
+
+		for _, e := range state.edges {
+			buf.WriteString("\tcase ")
+
+			for rangeIdx, rr := range e.ranges {
+				if rangeIdx > 0 {
+					buf.WriteString(", ")
+				}
+
+				buf.WriteString(condition(rr))
+			}
+
+			fmt.Fprintf(buf, ":\n\t\tgoto s%d\n", e.target)
+		}
+
+		buf.WriteString("\t}\n\n\tgoto done\n")
+	}
+
+	buf.WriteString(`
+done:
+	if lastEnd == start {
+		return Token{}, ErrNoMatch
+	}
+
+	lexer.offset = lastEnd
+
+	return Token{
+		Kind:   lexerAccepts[lastState],
+		Start:  start,
+		End:    lastEnd,
+		Lexeme: lexer.input[start:lastEnd],
+	}, nil
+}
+`)
+}
+
+// Returns a Go expression that reports whether the rune r is in rr.
+func condition(rr runeRange) string {
+	switch {
+	case rr.lo == rr.hi:
+		return "r == " + runeLiteral(rr.lo)
+	case rr.lo == 0:
+		return "r <= " + runeLiteral(rr.hi)
+	case rr.hi == unicode.MaxRune:
+		return "r >= " + runeLiteral(rr.lo)
+	default:
+		return runeLiteral(rr.lo) + " <= r && r <= " + runeLiteral(rr.hi)
+	}
+}
+
+// Returns a Go literal for r: a quoted rune for printable ASCII and a hexadecimal number otherwise.
+func runeLiteral(r rune) string {
+	if r < utf8.RuneSelf && unicode.IsPrint(r) {
+		return strconv.QuoteRune(r)
+	}
+
+	return fmt.Sprintf("0x%04X", r)
+}
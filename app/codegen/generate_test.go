@@ -0,0 +1,196 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package codegen_test
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/codegen"
+)
+
+// The rules used by the tests in this file.
+var generateRules = []codegen.Rule{
+	{Name: "IF", Pattern: "if"},
+	{Name: "IDENT", Pattern: "[A-Za-z_][A-Za-z0-9_]*"},
+	{Name: "NUMBER", Pattern: `\d+`},
+	{Name: "SPACE", Pattern: `\s+`},
+	{Name: "ANY", Pattern: "."},
+}
+
+// Returns the code generated for rules.
+func generate(t *testing.T, rules []codegen.Rule) string {
+	t.Helper()
+
+	machine, err := codegen.Compile(rules)
+
+	assert.Nilf(t, err, "\n\n"+
+		"UT Name:  When compiling valid rules, NO error is returned.\n"+
+		"\033[32mExpected: <nil>.\033[0m\n"+
+		"\033[31mActual:   %v.\033[0m\n\n", err)
+
+	names := make([]string, len(rules))
+
+	for idx, rule := range rules {
+		names[idx] = rule.Name
+	}
+
+	var buf bytes.Buffer
+
+	err = codegen.Generate(&buf, machine, codegen.Config{Package: "lexer", Names: names})
+
+	assert.Nilf(t, err, "\n\n"+
+		"UT Name:  When generating code for valid rules, NO error is returned.\n"+
+		"\033[32mExpected: <nil>.\033[0m\n"+
+		"\033[31mActual:   %v.\033[0m\n\n", err)
+
+	return buf.String()
+}
+
+// UT: Generate the code of a lexer.
+func TestGenerate(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When generating code, the output is formatted.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		src := generate(t, generateRules)
+
+		// Assert.
+		formatted, err := format.Source([]byte(src))
+
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When generating code, the output is valid Go code.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		assert.Equalf(t, string(formatted), src, "\n\n"+
+			"UT Name:  When generating code, the output is formatted.\n"+
+			"\033[32mExpected: formatted code.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", src)
+	})
+
+	t.Run("When generating code twice, the output is identical.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		first, second := generate(t, generateRules), generate(t, generateRules)
+
+		// Assert.
+		assert.Equalf(t, first, second, "\n\n"+
+			"UT Name:  When generating code twice, the output is identical.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", first, second)
+	})
+
+	t.Run("When generating code, the kinds and the accept table are declared.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		src := generate(t, generateRules)
+
+		// Assert.
+		for _, want := range []string{
+			"// Code generated by \"realign gen\"; DO NOT EDIT.",
+			"package lexer",
+			"IF     Kind = 1",
+			"ANY    Kind = 5",
+			"var lexerAccepts = [...]Kind{0, ",
+			"func (lexer *Lexer) Next() (Token, error) {",
+		} {
+			assert.Truef(t, strings.Contains(src, want), "\n\n"+
+				"UT Name:  When generating code, the output contains %q.\n"+
+				"\033[32mExpected: true.\033[0m\n"+
+				"\033[31mActual:   false.\033[0m\n\n", want)
+		}
+	})
+
+	t.Run("When an accepting value has no name, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine, _ := codegen.Compile(generateRules)
+
+		// Act.
+		err := codegen.Generate(&bytes.Buffer{}, machine, codegen.Config{Package: "lexer", Names: []string{"IF"}})
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When an accepting value has no name, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+
+	t.Run("When a state has trailing context, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[rune, int]()
+		accepting := nMachine.AddAcceptingEpsilonTransition(nMachine.Add(nMachine.Add(nMachine.Start(), 'a'), 'b'), 1)
+
+		nMachine.SetTrailingContext(accepting, &nfa.TrailingContext[rune]{HeadLen: 1, TailLen: -1})
+
+		cfg := codegen.Config{Package: "lexer", Names: []string{"A"}}
+
+		// Act.
+		err := codegen.Generate(&bytes.Buffer{}, dfa.FromNfa(nMachine), cfg)
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When a state has trailing context, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+
+	t.Run("When a state has opaque predicates, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		nMachine := nfa.New[rune, int]()
+		digit := nMachine.NewState()
+
+		nMachine.AddPredicateTransition(nMachine.Start(), digit, unicode.IsDigit)
+		nMachine.AddAcceptingEpsilonTransition(digit, 1)
+
+		cfg := codegen.Config{Package: "lexer", Names: []string{"D"}}
+
+		// Act.
+		err := codegen.Generate(&bytes.Buffer{}, dfa.FromNfa(nMachine), cfg)
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When a state has opaque predicates, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n")
+	})
+}
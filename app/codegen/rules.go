@@ -0,0 +1,112 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package codegen
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/regex"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// Rule associates the name of a kind of token with the pattern that recognizes it.
+type Rule struct {
+	Name    string // The name of the kind of token, which must be a valid Go identifier.
+	Pattern string // The regular expression that recognizes the token.
+}
+
+// ParseRules reads rules from r, one rule per line.
+//
+// Every line holds the name of the rule, followed by white space and the pattern of the rule. Leading and trailing
+// white space is ignored, as well as blank lines and lines starting with '#'. Since the pattern is trimmed, a pattern
+// that starts or ends with a space must use a character class (e.g., "[ ]").
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+
+	lines := bufio.NewScanner(r)
+
+	for lineNo := 1; lines.Scan(); lineNo++ {
+		line := strings.TrimSpace(lines.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, pattern := line, ""
+
+		if idx := strings.IndexAny(line, " \t"); idx >= 0 {
+			name, pattern = line[:idx], strings.TrimSpace(line[idx+1:])
+		}
+
+		if pattern == "" {
+			return nil, fmt.Errorf("codegen: line %d: missing pattern for rule %q", lineNo, name)
+		}
+
+		rules = append(rules, Rule{Name: name, Pattern: pattern})
+	}
+
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Compile returns a minimal [dfa.Dfa] that recognizes the tokens described by rules.
+//
+// The accepting value of the rule at index i is i+1, so that 0 can be used to indicate the absence of a token.
+// An error is returned if a rule has an invalid name or pattern, or if a name is used more than once.
+func Compile(rules []Rule) (*dfa.Dfa[rune, int], error) {
+	set := scanner.NewRules[rune, int]()
+	seen := make(map[string]bool, len(rules))
+
+	for idx, rule := range rules {
+		if !token.IsIdentifier(rule.Name) || reserved[rule.Name] {
+			return nil, fmt.Errorf("codegen: invalid rule name %q", rule.Name)
+		}
+
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("codegen: duplicate rule name %q", rule.Name)
+		}
+
+		seen[rule.Name] = true
+
+		fragment, err := regex.Parse[int](rule.Pattern)
+
+		if err != nil {
+			return nil, fmt.Errorf("codegen: rule %q: %w", rule.Name, err)
+		}
+
+		set.Add(fragment, idx+1)
+	}
+
+	return set.CompileDfa()
+}
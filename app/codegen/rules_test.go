@@ -0,0 +1,108 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package codegen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/codegen"
+)
+
+// UT: Parse rules.
+func TestParseRules(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When the input is valid, the rules are returned in order.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		input := "# Keywords.\nIF if\n\n  IDENT\t[a-z]+  \nSPACE [ ]\n"
+
+		// Act.
+		rules, err := codegen.ParseRules(strings.NewReader(input))
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When the input is valid, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		want := []codegen.Rule{{"IF", "if"}, {"IDENT", "[a-z]+"}, {"SPACE", "[ ]"}}
+
+		assert.EqualSf(t, rules, want, "\n\n"+
+			"UT Name:  When the input is valid, the rules are returned in order.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, rules)
+	})
+
+	t.Run("When a rule has no pattern, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		_, err := codegen.ParseRules(strings.NewReader("IF if\nIDENT\n"))
+
+		// Assert.
+		want := `codegen: line 2: missing pattern for rule "IDENT"`
+
+		assert.Equalf(t, err.Error(), want, "\n\n"+
+			"UT Name:  When a rule has no pattern, an error is returned.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, err)
+	})
+}
+
+// UT: Compile rules.
+func TestCompile(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name  string
+		rules []codegen.Rule
+		want  string
+	}{
+		{"an invalid name", []codegen.Rule{{"1x", "a"}}, `codegen: invalid rule name "1x"`},
+		{"a reserved name", []codegen.Rule{{"Token", "a"}}, `codegen: invalid rule name "Token"`},
+		{"a duplicate name", []codegen.Rule{{"A", "a"}, {"A", "b"}}, `codegen: duplicate rule name "A"`},
+		{"an invalid pattern", []codegen.Rule{{"A", "a("}},
+			`codegen: rule "A": regex: missing closing ) at offset 1 in "a("`},
+	} {
+		// Act.
+		_, err := codegen.Compile(tc.rules)
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When compiling a rule with %s, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n", tc.name)
+
+		assert.Equalf(t, err.Error(), tc.want, "\n\n"+
+			"UT Name:  When compiling a rule with %s, the error is correct.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", tc.name, tc.want, err.Error())
+	}
+}
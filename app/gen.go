@@ -0,0 +1,99 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kdeconinck/realign/codegen"
+)
+
+// Runs the "gen" command, which generates a Go lexer from a file with rules.
+func runGen(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: realign gen [flags] <rules file>")
+		flags.PrintDefaults()
+	}
+
+	output := flags.String("o", "", "write the generated code to `file` instead of the standard output")
+	pkg := flags.String("package", "lexer", "the `name` of the package of the generated code")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return fmt.Errorf("gen: expected exactly 1 rules file")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	rules, err := codegen.ParseRules(file)
+
+	if err != nil {
+		return err
+	}
+
+	machine, err := codegen.Compile(rules)
+
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(rules))
+
+	for idx, rule := range rules {
+		names[idx] = rule.Name
+	}
+
+	var src bytes.Buffer
+
+	if err := codegen.Generate(&src, machine, codegen.Config{Package: *pkg, Names: names}); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(src.Bytes())
+
+		return err
+	}
+
+	return os.WriteFile(*output, src.Bytes(), 0o644)
+}
@@ -26,7 +26,41 @@
 // Package main implements "realign", a language-agnostic, highly configurable static code analyzer & formatter.
 package main
 
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// The usage of the application.
+const usage = `Usage: realign <command> [arguments]
+
+Commands:
+  gen    generate a Go lexer from a file with rules
+`
+
 // The "main" entry point for the application.
 func main() {
-	// TODO: Implement this function.
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "realign:", err)
+		os.Exit(1)
+	}
+}
+
+// Runs the command described by args.
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+
+		return fmt.Errorf("missing command")
+	}
+
+	switch args[0] {
+	case "gen":
+		return runGen(args[1:], stdout, stderr)
+	default:
+		fmt.Fprint(stderr, usage)
+
+		return fmt.Errorf("unknown command %q", args[0])
+	}
 }
@@ -0,0 +1,139 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package regex
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// A 'runeRange' is an inclusive range of runes.
+type runeRange struct {
+	lo rune
+	hi rune
+}
+
+// A 'charClass' is a set of runes, represented by sorted, non-overlapping ranges.
+type charClass struct {
+	ranges  []runeRange
+	negated bool
+}
+
+// Adds the runes between lo and hi (inclusive) to the class.
+// After adding ranges, normalize must be called before using the class.
+func (class *charClass) add(lo, hi rune) {
+	class.ranges = append(class.ranges, runeRange{lo: lo, hi: hi})
+}
+
+// Adds all the runes of other to the class.
+// After merging classes, normalize must be called before using the class.
+func (class *charClass) merge(other *charClass) {
+	if !other.negated {
+		class.ranges = append(class.ranges, other.ranges...)
+
+		return
+	}
+
+	next := rune(0)
+
+	for _, r := range other.ranges {
+		if r.lo > next {
+			class.add(next, r.lo-1)
+		}
+
+		next = r.hi + 1
+	}
+
+	if next <= maxRune {
+		class.add(next, maxRune)
+	}
+}
+
+// Sorts the ranges of the class and merges overlapping and adjacent ones.
+func (class *charClass) normalize() {
+	sort.Slice(class.ranges, func(i, j int) bool { return class.ranges[i].lo < class.ranges[j].lo })
+
+	merged := class.ranges[:0]
+
+	for _, r := range class.ranges {
+		if n := len(merged); n > 0 && r.lo <= merged[n-1].hi+1 {
+			merged[n-1].hi = max(merged[n-1].hi, r.hi)
+
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	class.ranges = slices.Clip(merged)
+}
+
+// Returns the class as a [scanner.RuneSet].
+func (class *charClass) runeSet() scanner.RuneSet {
+	ranges := make([]scanner.RuneRange, 0, len(class.ranges))
+
+	for _, r := range class.ranges {
+		ranges = append(ranges, scanner.RuneRange{Lo: r.lo, Hi: r.hi})
+	}
+
+	set := scanner.NewRuneSet(ranges...)
+
+	if class.negated {
+		return set.Negate()
+	}
+
+	return set
+}
+
+// The largest valid rune.
+const maxRune = '\U0010FFFF'
+
+// Returns the class for the Perl escape \d, \w, \s or their negations \D, \W and \S.
+// Like Go's "regexp" package, these classes only contain ASCII runes.
+func perlClass(escape rune) *charClass {
+	class := &charClass{}
+
+	switch escape {
+	case 'd', 'D':
+		class.add('0', '9')
+	case 'w', 'W':
+		class.add('0', '9')
+		class.add('A', 'Z')
+		class.add('_', '_')
+		class.add('a', 'z')
+	case 's', 'S':
+		class.add('\t', '\n')
+		class.add('\f', '\r')
+		class.add(' ', ' ')
+	}
+
+	class.negated = escape == 'D' || escape == 'W' || escape == 'S'
+	class.normalize()
+
+	return class
+}
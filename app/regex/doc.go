@@ -0,0 +1,57 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package regex parses regular expressions into [scanner.Fragment]s.
+//
+// The supported syntax is a familiar subset of the one of Go's "regexp" package:
+//
+//	x y       concatenation
+//	x|y       alternation
+//	x*        zero or more x
+//	x+        one or more x
+//	x?        zero or one x
+//	x{m,n}    between m and n x (x{m} and x{m,} are supported as well)
+//	(x)       grouping ((?:x) is accepted as well)
+//	(?P<n>x)  a capture group named n (see [scanner.Capture]); (?<n>x) is accepted as well
+//	.         any rune except a newline
+//	[a-z_]    a character class (a leading ^ negates the class)
+//	\d \w \s  the ASCII digit, word and white space classes (\D, \W and \S negate them)
+//	\n \t ... escape sequences (\a \f \n \r \t \v \x7F \x{10FFFF} and escaped punctuation)
+//	^ $       the start and the end of a line (or of the input)
+//	\A \z     the start and the end of the input
+//	\b \B     an ASCII word boundary and its negation
+//
+// Unlike Go's "regexp" package, ^ and $ always match at line boundaries, since a lexer tokenizes the input line by line
+// more often than not.
+//
+// Parsing never panics on invalid input. Instead, a [*SyntaxError] is returned that records the offset (in bytes) in
+// the pattern where the problem was detected.
+//
+// Typical usage:
+//
+//	identifier, err := regex.Parse[Token](`[A-Za-z_][A-Za-z0-9_]*`)
+package regex
+
+import _ "github.com/kdeconinck/realign/scanner"
@@ -0,0 +1,600 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package regex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// The maximum count of a repetition (e.g., x{1000}).
+// Every repetition is expanded into copies of the repeated fragment, so unbounded counts would exhaust the memory.
+const maxRepeat = 1000
+
+// Parse parses pattern and returns a [scanner.Fragment] that matches the same input.
+// If pattern is invalid, a [*SyntaxError] is returned.
+func Parse[V any](pattern string) (scanner.Fragment[rune, V], error) {
+	p := &parser[V]{
+		pattern: pattern,
+		pos:     0,
+	}
+
+	fragment, err := p.parseAlternation()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.eof() {
+		return nil, p.errorf(p.pos, "unexpected )")
+	}
+
+	return fragment, nil
+}
+
+// MustParse is like [Parse] but panics if pattern is invalid.
+// It simplifies the initialization of global variables holding fragments.
+func MustParse[V any](pattern string) scanner.Fragment[rune, V] {
+	fragment, err := Parse[V](pattern)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return fragment
+}
+
+// The runes matched by '.'.
+var anyButNewline = scanner.NewRuneSet(scanner.RuneRange{Lo: '\n', Hi: '\n'}).Negate()
+
+// A 'parser' is a recursive descent parser for regular expressions.
+type parser[V any] struct {
+	pattern string
+	pos     int // The offset (in bytes) of the next rune in the pattern.
+}
+
+// An 'atom' is the result of parsing a single element of a regular expression.
+//
+// Reasoning:
+// Consecutive literal runes are merged into a single [scanner.Literal] instead of a [scanner.Sequence] of literals of
+// one rune each. Therefore, literal runes are kept as is until it's known whether they are repeated or not.
+type atom[V any] struct {
+	isLiteral bool
+	literal   rune
+	fragment  scanner.Fragment[rune, V]
+}
+
+// Returns the atom as a [scanner.Fragment].
+func (a atom[V]) toFragment() scanner.Fragment[rune, V] {
+	if a.isLiteral {
+		return scanner.Literal[rune, V](a.literal)
+	}
+
+	return a.fragment
+}
+
+func (p *parser[V]) eof() bool { return p.pos >= len(p.pattern) }
+
+// Returns the next rune (and its width) without consuming it.
+func (p *parser[V]) peek() (rune, int) {
+	return utf8.DecodeRuneInString(p.pattern[p.pos:])
+}
+
+// Consumes and returns the next rune.
+func (p *parser[V]) next() rune {
+	r, width := p.peek()
+	p.pos += width
+
+	return r
+}
+
+func (p *parser[V]) errorf(offset int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{
+		Pattern: p.pattern,
+		Offset:  offset,
+		Msg:     fmt.Sprintf(format, args...),
+	}
+}
+
+// alternation := concatenation ('|' concatenation)*
+func (p *parser[V]) parseAlternation() (scanner.Fragment[rune, V], error) {
+	var branches []scanner.Fragment[rune, V]
+
+	for {
+		branch, err := p.parseConcatenation()
+
+		if err != nil {
+			return nil, err
+		}
+
+		branches = append(branches, branch)
+
+		if r, _ := p.peek(); p.eof() || r != '|' {
+			break
+		}
+
+		p.next()
+	}
+
+	if len(branches) == 1 {
+		return branches[0], nil
+	}
+
+	return scanner.AnyOf(branches...), nil
+}
+
+// concatenation := repetition*
+func (p *parser[V]) parseConcatenation() (scanner.Fragment[rune, V], error) {
+	var fragments []scanner.Fragment[rune, V]
+	var literal []rune
+
+	flushLiteral := func() {
+		if len(literal) > 0 {
+			fragments = append(fragments, scanner.Literal[rune, V](literal...))
+			literal = nil
+		}
+	}
+
+	for !p.eof() {
+		if r, _ := p.peek(); r == '|' || r == ')' {
+			break
+		}
+
+		a, err := p.parseRepetition()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if a.isLiteral {
+			literal = append(literal, a.literal)
+
+			continue
+		}
+
+		flushLiteral()
+		fragments = append(fragments, a.fragment)
+	}
+
+	flushLiteral()
+
+	if len(fragments) == 1 {
+		return fragments[0], nil
+	}
+
+	// NOTE: An empty sequence matches the empty input.
+	return scanner.Sequence(fragments...), nil
+}
+
+// repetition := atom ('*' | '+' | '?' | '{' m (',' n?)? '}')?
+func (p *parser[V]) parseRepetition() (atom[V], error) {
+	a, err := p.parseAtom()
+
+	if err != nil {
+		return a, err
+	}
+
+	repeated := false
+
+	for !p.eof() {
+		start := p.pos
+		r, _ := p.peek()
+
+		var fragment scanner.Fragment[rune, V]
+
+		switch r {
+		case '*':
+			p.next()
+			fragment = scanner.RepeatAtLeast(0, a.toFragment())
+		case '+':
+			p.next()
+			fragment = scanner.RepeatAtLeast(1, a.toFragment())
+		case '?':
+			p.next()
+			fragment = scanner.RepeatBetween(0, 1, a.toFragment())
+		case '{':
+			minCount, maxCount, ok := p.parseRepeatCounts()
+
+			if !ok {
+				return a, nil // NOTE: A '{' that doesn't start a repetition is a literal.
+			}
+
+			if maxCount != -1 && (maxCount < minCount || maxCount > maxRepeat) || minCount > maxRepeat {
+				return a, p.errorf(start, "invalid repeat count")
+			}
+
+			if maxCount == -1 {
+				fragment = scanner.RepeatAtLeast(minCount, a.toFragment())
+			} else {
+				fragment = scanner.RepeatBetween(minCount, maxCount, a.toFragment())
+			}
+		default:
+			return a, nil
+		}
+
+		if repeated {
+			return a, p.errorf(start, "invalid nested repetition operator")
+		}
+
+		repeated = true
+		a = atom[V]{fragment: fragment}
+	}
+
+	return a, nil
+}
+
+// Parses the counts of a repetition of the form {m}, {m,} or {m,n}.
+// A maximum count of -1 means that there's no maximum. If the input doesn't have that form, false is returned and no
+// input is consumed.
+func (p *parser[V]) parseRepeatCounts() (int, int, bool) {
+	start := p.pos
+	p.next() // NOTE: Consume the '{'.
+
+	minCount, ok := p.parseNumber()
+
+	if !ok {
+		p.pos = start
+
+		return 0, 0, false
+	}
+
+	maxCount := minCount
+
+	if r, _ := p.peek(); !p.eof() && r == ',' {
+		p.next()
+
+		if maxCount, ok = p.parseNumber(); !ok {
+			maxCount = -1
+		}
+	}
+
+	if r, _ := p.peek(); p.eof() || r != '}' {
+		p.pos = start
+
+		return 0, 0, false
+	}
+
+	p.next()
+
+	return minCount, maxCount, true
+}
+
+// Parses a decimal number. If there's no number, false is returned and no input is consumed.
+func (p *parser[V]) parseNumber() (int, bool) {
+	start := p.pos
+
+	for !p.eof() && p.pattern[p.pos] >= '0' && p.pattern[p.pos] <= '9' {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(p.pattern[start:p.pos])
+
+	if err != nil {
+		n = maxRepeat + 1 // NOTE: The number is too large to be represented, so it's reported as an invalid count.
+	}
+
+	return n, true
+}
+
+// The assertions for the escape sequences that don't match a rune.
+var escapedAssertions = map[rune]nfa.Assertion{
+	'A': nfa.BeginText,
+	'z': nfa.EndText,
+	'b': nfa.WordBoundary,
+	'B': nfa.NoWordBoundary,
+}
+
+// atom := '(' ('?:' | '?' 'P'? '<' name '>')? alternation ')' | '[' class ']' | '.' | '^' | '$' | '\' escape | literal
+func (p *parser[V]) parseAtom() (atom[V], error) {
+	start := p.pos
+	r := p.next()
+
+	switch r {
+	case '(':
+		name, named, err := p.parseCaptureName(start)
+
+		if err != nil {
+			return atom[V]{}, err
+		}
+
+		fragment, err := p.parseAlternation()
+
+		if err != nil {
+			return atom[V]{}, err
+		}
+
+		if r, _ := p.peek(); p.eof() || r != ')' {
+			return atom[V]{}, p.errorf(start, "missing closing )")
+		}
+
+		p.next()
+
+		if named {
+			fragment = scanner.Capture(name, fragment)
+		}
+
+		return atom[V]{fragment: fragment}, nil
+
+	case '[':
+		class, err := p.parseClass(start)
+
+		if err != nil {
+			return atom[V]{}, err
+		}
+
+		return atom[V]{fragment: scanner.Set[V](class.runeSet())}, nil
+
+	case '.':
+		return atom[V]{fragment: scanner.Set[V](anyButNewline)}, nil
+
+	case '\\':
+		if r, _ := p.peek(); !p.eof() && strings.ContainsRune("AzbB", r) {
+			p.next()
+
+			return atom[V]{fragment: scanner.Assert[rune, V](escapedAssertions[r])}, nil
+		}
+
+		literal, class, err := p.parseEscape(start)
+
+		if err != nil {
+			return atom[V]{}, err
+		}
+
+		if class != nil {
+			return atom[V]{fragment: scanner.Set[V](class.runeSet())}, nil
+		}
+
+		return atom[V]{isLiteral: true, literal: literal}, nil
+
+	case '*', '+', '?':
+		return atom[V]{}, p.errorf(start, "missing argument to repetition operator")
+
+	case '^':
+		return atom[V]{fragment: scanner.BeginLine[rune, V]()}, nil
+
+	case '$':
+		return atom[V]{fragment: scanner.EndLine[rune, V]()}, nil
+
+	default:
+		// NOTE: A literal U+FFFD is valid, only a byte that can't be decoded is invalid UTF-8.
+		if r == utf8.RuneError && p.pos-start == 1 {
+			return atom[V]{}, p.errorf(start, "invalid UTF-8")
+		}
+
+		return atom[V]{isLiteral: true, literal: r}, nil
+	}
+}
+
+// Parses the name of a named capture group of the form (?P<name>x) or (?<name>x), directly after the '(' (at offset
+// start). It returns false if the group isn't named, which includes a non-capturing group of the form (?:x).
+func (p *parser[V]) parseCaptureName(start int) (string, bool, error) {
+	if !strings.HasPrefix(p.pattern[p.pos:], "?") {
+		return "", false, nil
+	}
+
+	if strings.HasPrefix(p.pattern[p.pos:], "?:") {
+		p.pos += len("?:")
+
+		return "", false, nil
+	}
+
+	if !strings.HasPrefix(p.pattern[p.pos:], "?P") && !strings.HasPrefix(p.pattern[p.pos:], "?<") {
+		return "", false, p.errorf(start, "unsupported group flag")
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(p.pattern[p.pos:], "?"), "P")
+	end := strings.IndexByte(rest, '>')
+
+	if !strings.HasPrefix(rest, "<") || end < 0 {
+		return "", false, p.errorf(start, "invalid named capture")
+	}
+
+	name := rest[1:end]
+
+	if name == "" || strings.IndexFunc(name, func(r rune) bool { return !isAlphaNumeric(r) && r != '_' }) >= 0 {
+		return "", false, p.errorf(start, "invalid named capture")
+	}
+
+	p.pos = len(p.pattern) - len(rest) + end + 1
+
+	return name, true, nil
+}
+
+// class := '^'? (item ('-' item)?)+
+// The opening '[' (at offset start) has already been consumed.
+func (p *parser[V]) parseClass(start int) (*charClass, error) {
+	class := &charClass{}
+
+	if r, _ := p.peek(); !p.eof() && r == '^' {
+		p.next()
+		class.negated = true
+	}
+
+	first := true
+
+	for {
+		if p.eof() {
+			return nil, p.errorf(start, "missing closing ]")
+		}
+
+		itemStart := p.pos
+		r := p.next()
+
+		// NOTE: A ']' directly after the opening '[' (or '[^') is a literal.
+		if r == ']' && !first {
+			break
+		}
+
+		first = false
+
+		lo, err := p.parseClassRune(r, itemStart, class)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if lo < 0 {
+			continue // NOTE: The item was a class escape (e.g., \d), which can't start a range.
+		}
+
+		if next, _ := p.peek(); p.eof() || next != '-' || p.pos+1 >= len(p.pattern) || p.pattern[p.pos+1] == ']' {
+			class.add(lo, lo)
+
+			continue
+		}
+
+		p.next() // NOTE: Consume the '-'.
+
+		hiStart := p.pos
+
+		hi, err := p.parseClassRune(p.next(), hiStart, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hi < lo {
+			return nil, p.errorf(itemStart, "invalid character class range")
+		}
+
+		class.add(lo, hi)
+	}
+
+	class.normalize()
+
+	return class, nil
+}
+
+// Parses a single rune r (at offset start) inside a character class.
+// If r starts a class escape (e.g., \d), the escape's ranges are added to class and -1 is returned. When class is nil,
+// class escapes are not allowed.
+func (p *parser[V]) parseClassRune(r rune, start int, class *charClass) (rune, error) {
+	// NOTE: A literal U+FFFD is valid, only a byte that can't be decoded is invalid UTF-8.
+	if r == utf8.RuneError && p.pos-start == 1 {
+		return 0, p.errorf(start, "invalid UTF-8")
+	}
+
+	if r != '\\' {
+		return r, nil
+	}
+
+	literal, escaped, err := p.parseEscape(start)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if escaped == nil {
+		return literal, nil
+	}
+
+	if class == nil {
+		return 0, p.errorf(start, "invalid character class range")
+	}
+
+	class.merge(escaped)
+
+	return -1, nil
+}
+
+// Parses an escape sequence. The '\' (at offset start) has already been consumed.
+// Either a literal rune or a class (for \d, \w, \s and their negations) is returned.
+func (p *parser[V]) parseEscape(start int) (rune, *charClass, error) {
+	if p.eof() {
+		return 0, nil, p.errorf(start, "trailing backslash at end of expression")
+	}
+
+	r := p.next()
+
+	switch r {
+	case 'a':
+		return '\a', nil, nil
+	case 'f':
+		return '\f', nil, nil
+	case 'n':
+		return '\n', nil, nil
+	case 'r':
+		return '\r', nil, nil
+	case 't':
+		return '\t', nil, nil
+	case 'v':
+		return '\v', nil, nil
+	case 'd', 'D', 'w', 'W', 's', 'S':
+		return 0, perlClass(r), nil
+	case 'x':
+		return p.parseHexEscape(start)
+	}
+
+	if r < utf8.RuneSelf && !isAlphaNumeric(r) {
+		return r, nil, nil
+	}
+
+	return 0, nil, p.errorf(start, "invalid escape sequence")
+}
+
+// Parses a hexadecimal escape of the form \x7F or \x{10FFFF}. The '\x' (at offset start) has already been consumed.
+func (p *parser[V]) parseHexEscape(start int) (rune, *charClass, error) {
+	var digits string
+
+	if strings.HasPrefix(p.pattern[p.pos:], "{") {
+		end := strings.IndexByte(p.pattern[p.pos:], '}')
+
+		if end < 0 {
+			return 0, nil, p.errorf(start, "invalid escape sequence")
+		}
+
+		digits = p.pattern[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+	} else {
+		if p.pos+2 > len(p.pattern) {
+			return 0, nil, p.errorf(start, "invalid escape sequence")
+		}
+
+		digits = p.pattern[p.pos : p.pos+2]
+		p.pos += 2
+	}
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+
+	if err != nil || value > utf8.MaxRune {
+		return 0, nil, p.errorf(start, "invalid escape sequence")
+	}
+
+	return rune(value), nil, nil
+}
+
+func isAlphaNumeric(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,233 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package regex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/regex"
+)
+
+// Reports whether the complete input is matched by pattern.
+func fullMatch(t *testing.T, pattern, input string) bool {
+	t.Helper()
+
+	fragment, err := regex.Parse[int](pattern)
+
+	assert.Nilf(t, err, "\n\n"+
+		"UT Name:  When parsing a valid pattern (%q), NO error is returned.\n"+
+		"\033[32mExpected: <nil>.\033[0m\n"+
+		"\033[31mActual:   %v.\033[0m\n\n", pattern, err)
+
+	machine := nfa.New[rune, int]()
+	machine.AddAcceptingEpsilonTransition(fragment.Build(machine, machine.Start()), 1)
+
+	runes := []rune(input)
+	match, ok := machine.Match(runes)
+
+	return ok && match.Length == len(runes)
+}
+
+// UT: Parse a valid pattern.
+func TestParse(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		pattern  string
+		accepted []string
+		rejected []string
+	}{
+		{`abc`, []string{"abc"}, []string{"", "ab", "abcd"}},
+		{`a|bc|`, []string{"a", "bc", ""}, []string{"b", "abc"}},
+		{`ab*`, []string{"a", "ab", "abbb"}, []string{"", "b", "aba"}},
+		{`(ab)+`, []string{"ab", "abab"}, []string{"", "a", "aba"}},
+		{`(?:ab|c)+d`, []string{"abd", "cabd"}, []string{"d", "ab"}},
+		{`colou?r`, []string{"color", "colour"}, []string{"colouur"}},
+		{`a{2}`, []string{"aa"}, []string{"a", "aaa"}},
+		{`a{2,}`, []string{"aa", "aaaa"}, []string{"a"}},
+		{`a{1,3}`, []string{"a", "aa", "aaa"}, []string{"", "aaaa"}},
+		{`a{,3}`, []string{"a{,3}"}, []string{"a", "aaa"}},
+		{`[A-Za-z_][A-Za-z0-9_]*`, []string{"x", "_x1", "Foo_Bar9"}, []string{"", "1x", "a-b"}},
+		{`[^a-c]`, []string{"d", "é"}, []string{"a", "b", "c"}},
+		{`[]a]`, []string{"]", "a"}, []string{"b"}},
+		{`[a-]`, []string{"a", "-"}, []string{"b"}},
+		{`[\d\s]`, []string{"1", " ", "\n"}, []string{"a"}},
+		{`[^\D]`, []string{"7"}, []string{"a"}},
+		{`\d+\.\d+`, []string{"3.14"}, []string{"3", "3.", ".5"}},
+		{`\w\W\S`, []string{"a-b"}, []string{"ab "}},
+		{`.`, []string{"a", "é"}, []string{"\n", ""}},
+		{`\x41\x{1F600}\t`, []string{"A😀\t"}, []string{"A"}},
+		{`é+`, []string{"é", "éé"}, []string{"e"}},
+		{"\uFFFD+", []string{"\uFFFD", "\uFFFD\uFFFD"}, []string{"a"}},
+		{"[a\uFFFD-\uFFFF]", []string{"a", "\uFFFD", "\uFFFF"}, []string{"b"}},
+		{`()`, []string{""}, []string{"a"}},
+		{`(a*)*`, []string{"", "aaa"}, []string{"b"}},
+		{`^a$\n^b$`, []string{"a\nb"}, []string{"a\n"}},
+		{`a^b|a$b`, nil, []string{"ab"}},
+		{`\Aa+\z`, []string{"a", "aa"}, []string{""}},
+		{`\bx\b-x\By`, []string{"x-xy"}, []string{"x-x"}},
+		{`a\bb`, nil, []string{"ab"}},
+	} {
+		for _, input := range tc.accepted {
+			got := fullMatch(t, tc.pattern, input)
+
+			assert.Truef(t, got, "\n\n"+
+				"UT Name:  When parsing %q, the input %q is accepted.\n"+
+				"\033[32mExpected: true.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.pattern, input, got)
+		}
+
+		for _, input := range tc.rejected {
+			got := fullMatch(t, tc.pattern, input)
+
+			assert.Falsef(t, got, "\n\n"+
+				"UT Name:  When parsing %q, the input %q is rejected.\n"+
+				"\033[32mExpected: false.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.pattern, input, got)
+		}
+	}
+}
+
+// UT: Parse an invalid pattern.
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		pattern string
+		offset  int
+		msg     string
+	}{
+		{`ab(c`, 2, "missing closing )"},
+		{`ab)c`, 2, "unexpected )"},
+		{`*a`, 0, "missing argument to repetition operator"},
+		{`a|+`, 2, "missing argument to repetition operator"},
+		{`a**`, 2, "invalid nested repetition operator"},
+		{`a{3,2}`, 1, "invalid repeat count"},
+		{`a{1001}`, 1, "invalid repeat count"},
+		{`[a-z`, 0, "missing closing ]"},
+		{`x[z-a]`, 2, "invalid character class range"},
+		{`[a-\d]`, 3, "invalid character class range"},
+		{`a\`, 1, "trailing backslash at end of expression"},
+		{`\q`, 0, "invalid escape sequence"},
+		{`\x{110000}`, 0, "invalid escape sequence"},
+		{`\x4`, 0, "invalid escape sequence"},
+		{`[\b]`, 1, "invalid escape sequence"},
+		{`a(?i)b`, 1, "unsupported group flag"},
+		{`(?=a)`, 0, "unsupported group flag"},
+		{`(?P=a)`, 0, "invalid named capture"},
+		{`(?P<a-b>c)`, 0, "invalid named capture"},
+		{"a\xff", 1, "invalid UTF-8"},
+		{"[a\xff]", 2, "invalid UTF-8"},
+		{"[a-\xff]", 3, "invalid UTF-8"},
+	} {
+		// Act.
+		_, err := regex.Parse[int](tc.pattern)
+
+		// Assert.
+		var syntaxErr *regex.SyntaxError
+
+		assert.Truef(t, errors.As(err, &syntaxErr), "\n\n"+
+			"UT Name:  When parsing an invalid pattern (%q), a 'SyntaxError' is returned.\n"+
+			"\033[32mExpected: *regex.SyntaxError.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", tc.pattern, err)
+
+		assert.Equalf(t, syntaxErr.Offset, tc.offset, "\n\n"+
+			"UT Name:  When parsing an invalid pattern (%q), the offset of the error is correct.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", tc.pattern, tc.offset, syntaxErr.Offset)
+
+		assert.Equalf(t, syntaxErr.Msg, tc.msg, "\n\n"+
+			"UT Name:  When parsing an invalid pattern (%q), the message of the error is correct.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", tc.pattern, tc.msg, syntaxErr.Msg)
+	}
+}
+
+// UT: Parse a pattern with named capture groups.
+func TestParse_Captures(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	fragment := regex.MustParse[int](`(?P<key>\w+)=(?<value>\d*)`)
+	machine := nfa.New[rune, int]()
+	machine.AddAcceptingEpsilonTransition(fragment.Build(machine, machine.Start()), 1)
+
+	input := []rune("port=8080")
+
+	// Act.
+	match, _ := machine.MatchSubmatch(input, 0, nfa.LeftmostLongest)
+
+	// Assert.
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"key", "port"},
+		{"value", "8080"},
+	} {
+		start, end, _ := match.Group(machine.CaptureIndex(tc.name))
+		got := string(input[start:end])
+
+		assert.Equalf(t, got, tc.want, "\n\n"+
+			"UT Name:  When parsing a named capture group, the group %q captures its part of the match.\n"+
+			"\033[32mExpected: %q.\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Parse an invalid pattern, which must panic.
+func TestMustParse(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Act.
+	fn := func() { regex.MustParse[int](`(`) }
+
+	// Assert.
+	assert.Panicf(t, fn, "\n\n"+
+		"UT Name:  When parsing an invalid pattern, 'MustParse' panics.\n"+
+		"\033[32mExpected: panic.\033[0m\n"+
+		"\033[31mActual:   NOT panic.\033[0m\n\n")
+}
+
+// UT: Format a 'SyntaxError'.
+func TestSyntaxError_Error(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	_, err := regex.Parse[int](`a(b`)
+
+	// Act.
+	got, want := err.Error(), `regex: missing closing ) at offset 1 in "a(b"`
+
+	// Assert.
+	assert.Equalf(t, got, want, "\n\n"+
+		"UT Name:  When formatting a 'SyntaxError', the position is included.\n"+
+		"\033[32mExpected: %s.\033[0m\n"+
+		"\033[31mActual:   %s.\033[0m\n\n", want, got)
+}
@@ -0,0 +1,72 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import "github.com/kdeconinck/realign/automata/nfa"
+
+// A [Fragment] that matches the empty input where an assertion holds.
+type fragAssertion[S comparable, V any] struct {
+	assertion nfa.Assertion
+}
+
+// Assert creates a [Fragment] that doesn't consume any symbol, but only matches where assertion holds between the
+// symbol before it and the symbol after it.
+//
+// A lexer takes the symbols around a token into account: the symbol before the start of a token is the last symbol of
+// the previous token and the symbol after its end is the first symbol of the lookahead.
+func Assert[S comparable, V any](assertion nfa.Assertion) Fragment[S, V] {
+	return fragAssertion[S, V]{
+		assertion: assertion,
+	}
+}
+
+// BeginText creates a [Fragment] that only matches at the start of the input.
+func BeginText[S comparable, V any]() Fragment[S, V] { return Assert[S, V](nfa.BeginText) }
+
+// EndText creates a [Fragment] that only matches at the end of the input.
+func EndText[S comparable, V any]() Fragment[S, V] { return Assert[S, V](nfa.EndText) }
+
+// BeginLine creates a [Fragment] that only matches at the start of a line (or at the start of the input).
+func BeginLine[S comparable, V any]() Fragment[S, V] { return Assert[S, V](nfa.BeginLine) }
+
+// EndLine creates a [Fragment] that only matches at the end of a line (or at the end of the input).
+func EndLine[S comparable, V any]() Fragment[S, V] { return Assert[S, V](nfa.EndLine) }
+
+// WordBoundary creates a [Fragment] that only matches between a word character and a symbol that isn't one (see
+// [nfa.KindOf]).
+func WordBoundary[S comparable, V any]() Fragment[S, V] { return Assert[S, V](nfa.WordBoundary) }
+
+// NoWordBoundary creates a [Fragment] that only matches where [WordBoundary] doesn't.
+func NoWordBoundary[S comparable, V any]() Fragment[S, V] { return Assert[S, V](nfa.NoWordBoundary) }
+
+// Build adds an assertion transition to a new state.
+func (frag fragAssertion[S, V]) Build(machine *nfa.Nfa[S, V], startState *nfa.State[S, V]) *nfa.State[S, V] {
+	endState := machine.NewState()
+
+	machine.AddAssertion(startState, endState, frag.assertion)
+
+	return endState
+}
@@ -0,0 +1,134 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// The kinds of tokens of a log line, where the meaning of a token depends on the symbols around it.
+const (
+	tokLevel = iota + 1
+	tokNumber
+	tokDone
+	tokMessage
+	tokGap
+)
+
+// Returns the rules of a log classifier that relies on line anchors and word boundaries.
+func newLogRules() *scanner.Rules[rune, int] {
+	letters := scanner.RepeatAtLeast(1, scanner.Set[int](scanner.NewRuneSet(
+		scanner.RuneRange{Lo: 'A', Hi: 'Z'}, scanner.RuneRange{Lo: 'a', Hi: 'z'},
+	)))
+
+	level := scanner.AnyOf(scanner.Literal[rune, int]([]rune("ERROR")...), scanner.Literal[rune, int]([]rune("WARN")...))
+	digits := scanner.RepeatAtLeast(1, scanner.Range[int]('0', '9'))
+
+	return scanner.NewRules[rune, int]().
+		Add(scanner.Sequence(scanner.BeginLine[rune, int](), level), tokLevel).
+		Add(scanner.Sequence(digits, scanner.WordBoundary[rune, int]()), tokNumber).
+		Add(scanner.Sequence(scanner.Literal[rune, int]([]rune("done")...), scanner.EndLine[rune, int]()), tokDone).
+		Add(letters, tokMessage).
+		Add(scanner.Literal[rune, int](' '), tokGap).
+		Add(scanner.Literal[rune, int]('\n'), tokGap)
+}
+
+// Returns the tokens returned by next until it fails, as "value:lexeme" (or "<lexeme>" for an error token).
+func classify(next func() (scanner.Token[rune, int], error)) []string {
+	names := map[int]string{tokLevel: "level", tokNumber: "number", tokDone: "done", tokMessage: "message", tokGap: "gap"}
+
+	var result []string
+
+	for {
+		token, err := next()
+
+		if err != nil {
+			return result
+		}
+
+		if token.Err != nil {
+			result = append(result, "<"+string(token.Lexeme)+">")
+
+			continue
+		}
+
+		result = append(result, names[token.Value]+":"+string(token.Lexeme))
+	}
+}
+
+// UT: Tokenize input with rules that have assertions.
+func TestAssert(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	machine, _ := newLogRules().CompileDfa()
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			"line anchors",
+			"ERROR disk done\nWARN ERROR done now",
+			[]string{
+				"level:ERROR", "gap: ", "message:disk", "gap: ", "done:done", "gap:\n",
+				"level:WARN", "gap: ", "message:ERROR", "gap: ", "message:done", "gap: ", "message:now",
+			},
+		},
+		{
+			"word boundaries",
+			"x 42 7z",
+			[]string{"message:x", "gap: ", "number:42", "gap: ", "<7>", "message:z"},
+		},
+	} {
+		t.Run("When the rules have "+tc.name+", the symbols around a token are taken into account.", func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Act.
+			got := classify(scanner.NewLexer(machine, []rune(tc.input), scanner.WithErrorTokens()).Next)
+
+			// Assert.
+			assert.EqualSf(t, got, tc.want, "\n\n"+
+				"UT Name:  When the rules have %s, the 'Lexer' takes the symbols around a token into account.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+
+			// Act.
+			reader := strings.NewReader(tc.input)
+			got = classify(scanner.NewReaderLexer(machine, reader, scanner.WithErrorTokens()).Next)
+
+			// Assert.
+			assert.EqualSf(t, got, tc.want, "\n\n"+
+				"UT Name:  When the rules have %s, the 'ReaderLexer' takes the symbols around a token into account.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+		})
+	}
+}
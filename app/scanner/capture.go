@@ -0,0 +1,65 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import "github.com/kdeconinck/realign/automata/nfa"
+
+// A [Fragment] that matches a [Fragment] and records the offsets of its match in a capture group.
+type fragCapture[S comparable, V any] struct {
+	name     string
+	fragment Fragment[S, V]
+	key      *captureKey // Identifies the capture group in an Nfa, so every copy of the fragment shares it.
+}
+
+// A 'captureKey' identifies the capture group of a [Capture] fragment.
+// NOTE: It isn't empty, since pointers to distinct zero-size variables may be equal.
+type captureKey struct {
+	name string
+}
+
+// Capture creates a [Fragment] that matches the same input as fragment and records where that input starts and ends
+// in a capture group named name.
+//
+// The capture group is registered in the Nfa when the fragment is first built (see [nfa.Nfa.CaptureFor]), so its index
+// follows the order in which the fragments of a rule (and the rules of [Rules]) are built. When the fragment is built
+// multiple times (e.g., when it's repeated), every copy records its offsets in the same capture group, so the group
+// holds the offsets of the last repetition, like in Go's "regexp" package. Only a matcher that tracks submatches
+// reports the offsets of a capture group (see [nfa.Nfa.MatchSubmatch]); other matchers ignore it.
+func Capture[S comparable, V any](name string, fragment Fragment[S, V]) Fragment[S, V] {
+	return fragCapture[S, V]{
+		name:     name,
+		fragment: fragment,
+		key:      &captureKey{name: name},
+	}
+}
+
+// Build surrounds the nfa part built by fragment with the tags of the capture group of the fragment.
+func (frag fragCapture[S, V]) Build(machine *nfa.Nfa[S, V], startState *nfa.State[S, V]) *nfa.State[S, V] {
+	group := machine.CaptureFor(frag.key, frag.name)
+	endState := frag.fragment.Build(machine, machine.AddTag(startState, 2*group))
+
+	return machine.AddTag(endState, 2*group+1)
+}
@@ -0,0 +1,107 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// UT: Extract the parts of a match with capture groups.
+func TestCapture(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	word := scanner.RepeatAtLeast(1, scanner.Range[int]('a', 'z'))
+	value := scanner.RepeatAtLeast(0, scanner.Set[int](scanner.NewRuneSet(scanner.RuneRange{Lo: ' ', Hi: '~'})))
+	equals := scanner.Literal[rune, int]('=')
+	pair := scanner.Sequence(scanner.Capture("key", word), equals, scanner.Capture("value", value))
+	machine := scanner.NewRules[rune, int]().Add(scanner.CaseInsensitive(pair), 1).Compile()
+
+	input := []rune("Level=warn=high")
+
+	// Act.
+	match, ok := machine.MatchSubmatch(input, 0, nfa.LeftmostFirst)
+
+	// Assert.
+	assert.Truef(t, ok, "\n\n"+
+		"UT Name:  When the input matches, the match is returned.\n"+
+		"\033[32mExpected: true.\033[0m\n"+
+		"\033[31mActual:   %t.\033[0m\n\n", ok)
+
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"key", "Level"},
+		{"value", "warn=high"},
+	} {
+		start, end, _ := match.Group(machine.CaptureIndex(tc.name))
+		got := string(input[start:end])
+
+		assert.Equalf(t, got, tc.want, "\n\n"+
+			"UT Name:  When the input matches, the capture group %q holds its part of the match.\n"+
+			"\033[32mExpected: %q.\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Extract the parts of a match with repeated capture groups.
+func TestCapture_Repeated(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	digit := scanner.Capture("d", scanner.Range[int]('0', '9'))
+
+	for _, tc := range []struct {
+		name     string
+		fragment scanner.Fragment[rune, int]
+	}{
+		{"a fixed number of times", scanner.RepeatBetween(3, 3, digit)},
+		{"at least once", scanner.RepeatAtLeast(1, digit)},
+	} {
+		// Arrange.
+		machine := scanner.NewRules[rune, int]().Add(tc.fragment, 1).Compile()
+		input := []rune("123")
+
+		// Act.
+		match, _ := machine.MatchSubmatch(input, 0, nfa.LeftmostFirst)
+		start, end, _ := match.Group(machine.CaptureIndex("d"))
+
+		// Assert.
+		assert.EqualSf(t, machine.CaptureNames(), []string{"", "d"}, "\n\n"+
+			"UT Name:  When a capture group is repeated %s, it's registered once.\n"+
+			"\033[32mExpected: %q.\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", tc.name, []string{"", "d"}, machine.CaptureNames())
+
+		assert.Equalf(t, string(input[start:end]), "3", "\n\n"+
+			"UT Name:  When a capture group is repeated %s, it holds the last repetition.\n"+
+			"\033[32mExpected: \"3\".\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", tc.name, string(input[start:end]))
+	}
+}
@@ -27,6 +27,27 @@
 //
 // A [Fragment] represents a partial Nfa construction strategy (e.g., matching a literal, a sequence, etc.).
 // Fragments can be composed to build complex matching logic, which is then compiled into an [nfa.Nfa].
+//
+// Sets of runes are best described with a [RuneSet] (e.g., through [Range], [Category] or [Script]) rather than with an
+// opaque function passed to [SymbolSet], since a RuneSet can be inspected, determinized exactly and printed.
+//
+// Fragments over runes can be matched ignoring case with [CaseInsensitive]. A rule can require its token to be followed
+// by trailing context that isn't part of the token with [FollowedBy]. Fragments like [BeginLine], [EndLine] and
+// [WordBoundary] don't match any symbol, but assert a condition on the symbols around them (see [Assert]). The parts
+// of a match can be extracted by wrapping fragments in a [Capture].
+//
+// A set of [Rules] associates fragments with the values they produce and compiles them into a single automaton, where
+// rules that are declared first take priority over later ones.
+//
+// Rules can also be partitioned into [Modes], where every mode is compiled into its own automaton and the rules switch
+// between modes while a [ModalLexer] tokenizes the input.
+//
+// A [ReaderLexer] (for runes) or a [ByteReaderLexer] (for bytes) tokenizes a stream instead of input that's held in
+// memory. It only buffers the current token and its lookahead, up to a maximum (see [WithMaxLookahead]).
+//
+// When no rule matches the input, a lexer returns an [*Error] describing where the input failed to match and which
+// symbols would have been accepted. Alternatively, it can recover by returning error tokens and continue lexing (see
+// [WithErrorTokens] and [Lexer.SkipUntil]).
 package scanner
 
 import _ "github.com/kdeconinck/realign/automata/nfa"
@@ -0,0 +1,217 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"sync"
+	"unicode"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// CaseInsensitive creates a [Fragment] that matches the same input as fragment, ignoring case.
+//
+// Every rune is replaced by its case-fold orbit, as defined by [unicode.SimpleFold] (e.g., 'k' matches 'k', 'K' and
+// the Kelvin sign 'K'). Literals become a chain of states with a transition for every rune of an orbit, and the runes
+// of a [RuneSet] are extended with their orbits, so the size of the resulting Nfa is linear in the size of fragment.
+//
+// The fragments of this package are rewritten recursively. A [SymbolSet] matches a rune if its function holds for any
+// rune of the orbit. Fragments of other types (e.g., a [dfa.Dfa] or a custom [Fragment]) are left untouched.
+func CaseInsensitive[V any](fragment Fragment[rune, V]) Fragment[rune, V] {
+	switch frag := fragment.(type) {
+	case fragLiteral[rune, V]:
+		return fragFoldedLiteral[V]{
+			runes: frag.symbols,
+		}
+
+	case fragSequence[rune, V]:
+		return fragSequence[rune, V]{
+			fragments: foldAll(frag.fragments),
+		}
+
+	case fragAnyOf[rune, V]:
+		return fragAnyOf[rune, V]{
+			fragments: foldAll(frag.fragments),
+		}
+
+	case fragRepeat[rune, V]:
+		frag.fragment = CaseInsensitive(frag.fragment)
+
+		return frag
+
+	case fragRuneSet[V]:
+		return fragRuneSet[V]{
+			set: foldSet(frag.set),
+		}
+
+	case fragSymbolSet[rune, V]:
+		return fragSymbolSet[rune, V]{
+			fn: foldFunc(frag.fn),
+		}
+
+	case fragTrailing[rune, V]:
+		return fragTrailing[rune, V]{
+			head: CaseInsensitive(frag.head),
+			tail: CaseInsensitive(frag.tail),
+		}
+
+	case fragCapture[rune, V]:
+		frag.fragment = CaseInsensitive(frag.fragment)
+
+		return frag
+
+	case fragProduct[rune, V]:
+		frag.a = CaseInsensitive(frag.a)
+
+		if frag.b != nil {
+			frag.b = CaseInsensitive(frag.b)
+		}
+
+		frag.alphabet = foldRunes(frag.alphabet)
+
+		return frag
+
+	default:
+		return fragment
+	}
+}
+
+// Returns the case-insensitive version of every fragment of fragments.
+func foldAll[V any](fragments []Fragment[rune, V]) []Fragment[rune, V] {
+	result := make([]Fragment[rune, V], len(fragments))
+
+	for idx, fragment := range fragments {
+		result[idx] = CaseInsensitive(fragment)
+	}
+
+	return result
+}
+
+// Returns the runes of the case-fold orbit of r, starting with r itself.
+func orbit(r rune) []rune {
+	runes := []rune{r}
+
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		runes = append(runes, f)
+	}
+
+	return runes
+}
+
+// Returns runes, extended with the runes of their case-fold orbits (without duplicates).
+func foldRunes(runes []rune) []rune {
+	var result []rune
+
+	seen := make(map[rune]bool, len(runes))
+
+	for _, r := range runes {
+		for _, f := range orbit(r) {
+			if !seen[f] {
+				seen[f] = true
+				result = append(result, f)
+			}
+		}
+	}
+
+	return result
+}
+
+// The runes that have a case-fold orbit with more than one rune, in ascending order.
+//
+// Reasoning:
+// A [RuneSet] may hold all the runes (e.g., when it's negated), so folding it rune by rune is too expensive. Since only
+// a few thousand runes have other runes in their orbit, only those runes have to be checked.
+var foldableRunes = sync.OnceValue(func() []rune {
+	var runes []rune
+
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if unicode.SimpleFold(r) != r {
+			runes = append(runes, r)
+		}
+	}
+
+	return runes
+})
+
+// Returns set, extended with the runes of the case-fold orbits of its runes.
+// Like Go's "regexp" package, a negated set is folded before it's negated, so [^a] matches neither 'a' nor 'A'.
+func foldSet(set RuneSet) RuneSet {
+	if set.negated {
+		return foldSet(set.Negate()).Negate()
+	}
+
+	var ranges []RuneRange
+
+	for _, r := range foldableRunes() {
+		if set.Contains(r) {
+			for _, f := range orbit(r) {
+				ranges = append(ranges, RuneRange{Lo: f, Hi: f})
+			}
+		}
+	}
+
+	return set.Union(NewRuneSet(ranges...))
+}
+
+// Returns a function that holds for a rune if fn holds for any rune of its case-fold orbit.
+func foldFunc(fn func(rune) bool) func(rune) bool {
+	return func(r rune) bool {
+		if fn(r) {
+			return true
+		}
+
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if fn(f) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// A [Fragment] that matches a fixed sequence of runes, ignoring case.
+type fragFoldedLiteral[V any] struct {
+	runes []rune
+}
+
+// Build creates a chain of nfa states, where every rune of the case-fold orbit of a rune of the literal leads to the
+// next state.
+func (frag fragFoldedLiteral[V]) Build(machine *nfa.Nfa[rune, V], startState *nfa.State[rune, V]) *nfa.State[rune, V] {
+	last := startState
+
+	for _, r := range frag.runes {
+		next := machine.NewState()
+
+		for _, f := range orbit(r) {
+			machine.Connect(last, next, f)
+		}
+
+		last = next
+	}
+
+	return last
+}
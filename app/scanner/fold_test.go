@@ -0,0 +1,131 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// UT: Match 'Fragment's, ignoring case.
+func TestCaseInsensitive(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	keyword := scanner.Literal[rune, int]([]rune("select")...)
+	identifier := scanner.RepeatAtLeast(1, scanner.Range[int]('a', 'z'))
+
+	for _, tc := range []struct {
+		name     string
+		fragment scanner.Fragment[rune, int]
+		accepted []string
+		rejected []string
+	}{
+		{
+			"a literal",
+			scanner.CaseInsensitive(keyword),
+			[]string{"select", "SELECT", "SeLeCt", "ſelect"},
+			[]string{"", "selec", "selects"},
+		},
+		{
+			"a literal with runes outside of ASCII",
+			scanner.CaseInsensitive(scanner.Literal[rune, int]([]rune("kσ")...)),
+			[]string{"kσ", "KΣ", "Kς"},
+			[]string{"kx"},
+		},
+		{
+			"a range",
+			scanner.CaseInsensitive(identifier),
+			[]string{"abc", "ABC", "aBc", "K"},
+			[]string{"", "a1", "é"},
+		},
+		{
+			"a negated set",
+			scanner.CaseInsensitive(scanner.Set[int](scanner.NewRuneSet(scanner.RuneRange{Lo: 'a', Hi: 'a'}).Negate())),
+			[]string{"b", "B", "1"},
+			[]string{"a", "A", "bb"},
+		},
+		{
+			"a symbol set",
+			scanner.CaseInsensitive(scanner.SymbolSet[rune, int](unicode.IsLower)),
+			[]string{"a", "A", "É"},
+			[]string{"1", "ab"},
+		},
+		{
+			"a composition of fragments",
+			scanner.CaseInsensitive(scanner.Sequence(
+				scanner.AnyOf(keyword, scanner.Literal[rune, int]('x')),
+				scanner.Difference(identifier, scanner.Literal[rune, int]('i', 'f')),
+			)),
+			[]string{"SELECTa", "XyZ", "xiff"},
+			[]string{"selectIF", "xIf", "X"},
+		},
+		{
+			"a complement",
+			scanner.CaseInsensitive(scanner.Complement(keyword, []rune("selct")...)),
+			[]string{"", "Sel", "TESTS"},
+			[]string{"SELECT", "select", "x"},
+		},
+	} {
+		for _, input := range tc.accepted {
+			got := fullMatch(tc.fragment, input)
+
+			assert.Truef(t, got, "\n\n"+
+				"UT Name:  When matching %s ignoring case, the input %q is accepted.\n"+
+				"\033[32mExpected: true.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.name, input, got)
+		}
+
+		for _, input := range tc.rejected {
+			got := fullMatch(tc.fragment, input)
+
+			assert.Falsef(t, got, "\n\n"+
+				"UT Name:  When matching %s ignoring case, the input %q is rejected.\n"+
+				"\033[32mExpected: false.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.name, input, got)
+		}
+	}
+
+	t.Run("When a literal is matched ignoring case, its automaton has a state per rune.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		rules := scanner.NewRules[rune, int]().Add(scanner.CaseInsensitive(keyword), 1)
+
+		// Act.
+		machine, _ := rules.CompileDfa()
+		got := len(machine.States())
+
+		// Assert.
+		assert.Equalf(t, got, 7, "\n\n"+
+			"UT Name:  When a literal is matched ignoring case, its automaton has a state per rune.\n"+
+			"\033[32mExpected: 7.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", got)
+	})
+}
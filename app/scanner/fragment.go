@@ -171,34 +171,32 @@ func (frag fragRepeat[S, V]) Build(machine *nfa.Nfa[S, V], startState *nfa.State
 	}
 
 	endState := machine.NewState()
-	machine.ConnectEpsilon(currentState, endState)
 
+	// NOTE: The epsilon transitions that repeat the fragment are added before the ones that skip it, so that a matcher
+	// that prefers the first transition (see [nfa.LeftmostFirst]) repeats the fragment as often as possible.
 	// If there's NO maximal amount of required of occurences, loop back to the beginning.
 	if !frag.hasMax {
-		machine.ConnectEpsilon(currentState, endState)
-
 		bodyStartState := machine.AddEpsilonTransition(currentState)
 		bodyEndState := frag.fragment.Build(machine, bodyStartState)
 
 		machine.ConnectEpsilon(bodyEndState, bodyStartState)
 		machine.ConnectEpsilon(bodyEndState, endState)
+		machine.ConnectEpsilon(currentState, endState)
 
 		return endState
 	}
 
-	if frag.maxOccurence-frag.minOccurence == 0 {
-		return endState
-	}
-
 	for idx := 0; idx < frag.maxOccurence-frag.minOccurence; idx++ {
 		optStartState := machine.AddEpsilonTransition(currentState)
 		optEndState := frag.fragment.Build(machine, optStartState)
 
-		machine.ConnectEpsilon(optEndState, endState)
+		machine.ConnectEpsilon(currentState, endState)
 
 		currentState = optEndState
 	}
 
+	machine.ConnectEpsilon(currentState, endState)
+
 	return endState
 }
 
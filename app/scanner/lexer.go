@@ -0,0 +1,201 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"errors"
+	"io"
+
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// ErrNoMatch is returned by [Lexer.Next] when no rule matches the input at the current offset.
+var ErrNoMatch = errors.New("scanner: no rule matches the input")
+
+// Token is a single lexeme recognized by a [Lexer].
+type Token[S comparable, V any] struct {
+	Value  V   // The accepting value of the rule that produced the token.
+	Start  int // The offset of the first symbol of the token.
+	End    int // The offset directly after the last symbol of the token.
+	Lexeme []S // The symbols that make up the token.
+
+	// The error for the symbols of the token if it's an error token, which is only returned when the lexer recovers from
+	// input that no rule matches (see [WithErrorTokens]).
+	Err *Error[S]
+
+	// The position of the first symbol of the token.
+	// It's only set when the tracking of positions is enabled (see [WithPositions]).
+	Pos Position
+}
+
+// Len returns the number of symbols in the token.
+func (token Token[S, V]) Len() int { return token.End - token.Start }
+
+// Lexer splits a sequence of symbols into [Token]s by driving a [dfa.Dfa] over it.
+//
+// Tokens are recognized using the "maximal munch" rule: starting from the current offset, the lexer follows the
+// transitions of the dfa symbol by symbol for as long as possible and remembers the last accepting state it went
+// through. The token ends where that accepting state was reached. When multiple rules match the same (longest) input,
+// the dfa resolves the conflict beforehand by keeping the accepting value with the lowest acceptance index.
+//
+// Matches of zero symbols are never reported, since they would prevent the lexer from making progress.
+type Lexer[S comparable, V any] struct {
+	machine  *dfa.Dfa[S, V]
+	input    []S
+	offset   int
+	tracker  *tracker // Tracks the position in the input (nil when disabled).
+	advance  func(S)  // Advances the tracker past a single symbol.
+	recovery recoveryMode
+	sync     map[S]bool // The synchronisation set when skipping input that no rule matches.
+}
+
+// NewLexer creates a new [Lexer] that tokenizes input using machine.
+func NewLexer[S comparable, V any](machine *dfa.Dfa[S, V], input []S, opts ...Option) *Lexer[S, V] {
+	config := newOptions(opts)
+
+	lexer := &Lexer[S, V]{
+		machine:  machine,
+		input:    input,
+		offset:   0,
+		tracker:  newTracker(config),
+		recovery: config.recovery,
+	}
+
+	if lexer.tracker != nil {
+		lexer.advance = newAdvanceFunc[S](lexer.tracker)
+	}
+
+	return lexer
+}
+
+// Offset returns the offset of the first symbol that hasn't been consumed yet.
+func (lexer *Lexer[S, V]) Offset() int { return lexer.offset }
+
+// Position returns the [Position] of the first symbol that hasn't been consumed yet.
+// If the tracking of positions isn't enabled, the zero (invalid) position is returned.
+func (lexer *Lexer[S, V]) Position() Position {
+	if lexer.tracker == nil {
+		return Position{}
+	}
+
+	return lexer.tracker.pos
+}
+
+// Next returns the next [Token] of the input.
+//
+// When all the symbols have been consumed, Next returns [io.EOF]. When no rule matches at the current offset, Next
+// returns an [*Error] (which wraps [ErrNoMatch]) and the offset is left untouched, unless the lexer recovers from such
+// input by returning an error token (see [WithErrorTokens] and [Lexer.SkipUntil]).
+func (lexer *Lexer[S, V]) Next() (Token[S, V], error) {
+	if lexer.offset >= len(lexer.input) {
+		return Token[S, V]{}, io.EOF
+	}
+
+	start := lexer.offset
+	m := longestMatch(lexer.machine, lexer.input, start)
+
+	if m.accepting == nil {
+		return lexer.fail(start, m)
+	}
+
+	return lexer.token(start, m.tokenEnd(lexer.input, start), m.accepting, nil), nil
+}
+
+// Returns the [*Error] for m, which failed at offset start, or an error token if the lexer recovers from such input.
+func (lexer *Lexer[S, V]) fail(start int, m match[S, V]) (Token[S, V], error) {
+	err := m.error(start, lexer.Position())
+
+	if lexer.recovery == recoverAbort {
+		return Token[S, V]{}, err
+	}
+
+	return lexer.token(start, skipEnd(lexer.input, start, lexer.recovery, lexer.sync), nil, err), nil
+}
+
+// Consumes the symbols from start up to end and returns them as a token, accepted by accepting or described by err.
+func (lexer *Lexer[S, V]) token(start, end int, accepting *dfa.State[S, V], err *Error[S]) Token[S, V] {
+	lexer.offset = end
+
+	token := Token[S, V]{
+		Start:  start,
+		End:    end,
+		Lexeme: lexer.input[start:end],
+		Pos:    lexer.track(lexer.input[start:end]),
+		Err:    err,
+	}
+
+	if accepting != nil {
+		token.Value = accepting.AcceptValue()
+	}
+
+	return token
+}
+
+// Advances the tracker (if any) past lexeme and returns the position of its first symbol.
+func (lexer *Lexer[S, V]) track(lexeme []S) Position {
+	if lexer.tracker == nil {
+		return Position{}
+	}
+
+	pos := lexer.tracker.pos
+
+	for _, symbol := range lexeme {
+		lexer.advance(symbol)
+	}
+
+	return pos
+}
+
+// Returns the result of following the transitions of machine over input, starting at offset start, for as long as
+// possible.
+// The symbols around the match are taken into account by the assertions of machine (if any).
+func longestMatch[S comparable, V any](machine *dfa.Dfa[S, V], input []S, start int) match[S, V] {
+	m := match[S, V]{
+		end:     -1,
+		stuck:   machine.StartAfter(nfa.KindAt(input, start-1)),
+		stuckAt: len(input),
+	}
+
+	for idx := start; idx < len(input); idx++ {
+		state := m.stuck.OutgoingFor(input[idx])
+
+		if state == nil {
+			m.stuckAt = idx
+
+			break
+		}
+
+		m.stuck = state
+
+		if accepting := state.AcceptingBefore(nfa.KindAt(input, idx+1)); accepting != nil {
+			m.accepting = accepting
+			m.end = idx + 1
+		}
+	}
+
+	return m
+}
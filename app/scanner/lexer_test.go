@@ -0,0 +1,205 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// The tokens produced by the rules in this file.
+const (
+	tokKeyword = iota + 1
+	tokIdent
+	tokSpace
+)
+
+// Returns a [dfa.Dfa] recognizing the keyword "if", identifiers made of 'i', 'f' and 'x' and a single space.
+// The keyword is declared before the identifier, so it has the highest priority.
+func newLexerMachine() *dfa.Dfa[rune, int] {
+	letters := scanner.AnyOf(scanner.Literal[rune, int]('i'), scanner.Literal[rune, int]('f'),
+		scanner.Literal[rune, int]('x'))
+
+	machine, _ := scanner.NewRules[rune, int]().
+		Add(scanner.Literal[rune, int]('i', 'f'), tokKeyword).
+		Add(scanner.RepeatBetween(1, 8, letters), tokIdent).
+		Add(scanner.Literal[rune, int](' '), tokSpace).
+		CompileDfa()
+
+	return machine
+}
+
+// UT: Tokenize input using a 'Lexer'.
+func TestLexer_Next(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When the input is valid, the correct tokens are returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lexer := scanner.NewLexer(newLexerMachine(), []rune("if iff x"))
+
+		want := []struct {
+			value  int
+			start  int
+			end    int
+			lexeme string
+		}{
+			{tokKeyword, 0, 2, "if"},
+			{tokSpace, 2, 3, " "},
+			{tokIdent, 3, 6, "iff"},
+			{tokSpace, 6, 7, " "},
+			{tokIdent, 7, 8, "x"},
+		}
+
+		for _, w := range want {
+			// Act.
+			got, err := lexer.Next()
+
+			// Assert.
+			assert.Nilf(t, err, "\n\n"+
+				"UT Name:  When the input is valid, NO error is returned.\n"+
+				"\033[32mExpected: <nil>.\033[0m\n"+
+				"\033[31mActual:   %v.\033[0m\n\n", err)
+
+			assert.Equalf(t, got.Value, w.value, "\n\n"+
+				"UT Name:  When the input is valid, the value of the token is correct.\n"+
+				"\033[32mExpected: %d.\033[0m\n"+
+				"\033[31mActual:   %d.\033[0m\n\n", w.value, got.Value)
+
+			assert.Equalf(t, got.Start, w.start, "\n\n"+
+				"UT Name:  When the input is valid, the start offset of the token is correct.\n"+
+				"\033[32mExpected: %d.\033[0m\n"+
+				"\033[31mActual:   %d.\033[0m\n\n", w.start, got.Start)
+
+			assert.Equalf(t, got.End, w.end, "\n\n"+
+				"UT Name:  When the input is valid, the end offset of the token is correct.\n"+
+				"\033[32mExpected: %d.\033[0m\n"+
+				"\033[31mActual:   %d.\033[0m\n\n", w.end, got.End)
+
+			assert.Equalf(t, string(got.Lexeme), w.lexeme, "\n\n"+
+				"UT Name:  When the input is valid, the lexeme of the token is correct.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", w.lexeme, string(got.Lexeme))
+		}
+
+		_, err := lexer.Next()
+
+		assert.Errorf(t, err, io.EOF, "\n\n"+
+			"UT Name:  When all the input is consumed, 'io.EOF' is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", io.EOF, err)
+	})
+
+	t.Run("When a longer match fails halfway, the lexer backtracks to the last accepting state.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := nfa.New[rune, int]()
+
+		machine.AddAcceptingEpsilonTransition(scanner.Literal[rune, int]('a').Build(machine, machine.Start()), 1)
+		machine.AddAcceptingEpsilonTransition(scanner.Literal[rune, int]('a', 'b', 'c').Build(machine,
+			machine.Start()), 2)
+
+		lexer := scanner.NewLexer(dfa.FromNfa(machine), []rune("aba"))
+
+		// Act.
+		got, err := lexer.Next()
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When a longer match fails halfway, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		assert.Equalf(t, got.Value, 1, "\n\n"+
+			"UT Name:  When a longer match fails halfway, the value of the shorter match is returned.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 1, got.Value)
+
+		assert.Equalf(t, lexer.Offset(), 1, "\n\n"+
+			"UT Name:  When a longer match fails halfway, the lexer continues after the shorter match.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 1, lexer.Offset())
+	})
+
+	t.Run("When no rule matches, 'ErrNoMatch' is returned and the offset is unchanged.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lexer := scanner.NewLexer(newLexerMachine(), []rune("if?"))
+
+		_, _ = lexer.Next()
+
+		// Act.
+		_, err := lexer.Next()
+
+		// Assert.
+		assert.Errorf(t, err, scanner.ErrNoMatch, "\n\n"+
+			"UT Name:  When no rule matches, 'ErrNoMatch' is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", scanner.ErrNoMatch, err)
+
+		assert.Equalf(t, lexer.Offset(), 2, "\n\n"+
+			"UT Name:  When no rule matches, the offset is unchanged.\n"+
+			"\033[32mExpected: %d.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", 2, lexer.Offset())
+	})
+}
+
+var benchmarkTokenOutput scanner.Token[rune, int] // Output of the benchmark(s).
+
+// Benchmark(s): Tokenize input.
+func BenchmarkLexer_Next_100(b *testing.B)   { benchmarkLexer_Next(100, b) }
+func BenchmarkLexer_Next_10000(b *testing.B) { benchmarkLexer_Next(10_000, b) }
+
+func benchmarkLexer_Next(count int, b *testing.B) {
+	machine := newLexerMachine()
+	input := make([]rune, 0, count*4)
+
+	for range count {
+		input = append(input, []rune("if x")...)
+	}
+
+	for b.Loop() {
+		lexer := scanner.NewLexer(machine, input)
+
+		for {
+			token, err := lexer.Next()
+
+			if err != nil {
+				break
+			}
+
+			benchmarkTokenOutput = token
+		}
+	}
+}
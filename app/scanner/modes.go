@@ -0,0 +1,267 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/kdeconinck/realign/automata/dfa"
+)
+
+// ErrEmptyModeStack is returned by [ModalLexer.Next] when a rule pops the only mode on the mode stack.
+var ErrEmptyModeStack = errors.New("scanner: pop from an empty mode stack")
+
+// The operations an [Action] performs on the mode stack of a [ModalLexer].
+type actionOp int
+
+const (
+	opPush actionOp = iota + 1
+	opPop
+	opSwitch
+)
+
+// Action changes the mode stack of a [ModalLexer] after the rule it belongs to matched.
+type Action struct {
+	op   actionOp
+	mode string // The mode that's pushed or switched to.
+}
+
+// Push returns an [Action] that enters mode, remembering the current mode, so it can be restored with [Pop].
+func Push(mode string) Action { return Action{op: opPush, mode: mode} }
+
+// Pop returns an [Action] that returns to the mode that was entered before the current one.
+func Pop() Action { return Action{op: opPop} }
+
+// Switch returns an [Action] that replaces the current mode with mode.
+func Switch(mode string) Action { return Action{op: opSwitch, mode: mode} }
+
+// Modes is a set of [Rules], partitioned into named modes (also known as start conditions).
+//
+// Only the rules of the current mode are active. A rule can change the current mode with [Action]s, which makes it
+// possible to recognize context-dependent tokens, such as the contents of a string with interpolations.
+type Modes[S comparable, V any] struct {
+	initial string
+	names   []string // The names of the modes, in the order in which they were declared.
+	rules   map[string]*Rules[S, V]
+	actions map[string][][]Action // The actions of every rule, indexed by the position of the rule in its mode.
+}
+
+// NewModes creates an empty set of [Modes], where a lexer starts in the mode initial.
+func NewModes[S comparable, V any](initial string) *Modes[S, V] {
+	return &Modes[S, V]{
+		initial: initial,
+		rules:   make(map[string]*Rules[S, V]),
+		actions: make(map[string][][]Action),
+	}
+}
+
+// Add registers a rule in mode that produces value when fragment matches and then performs actions (in order).
+// The mode is declared when its first rule is added. It returns the set of modes, so calls can be chained.
+func (modes *Modes[S, V]) Add(mode string, fragment Fragment[S, V], value V, actions ...Action) *Modes[S, V] {
+	rules, ok := modes.rules[mode]
+
+	if !ok {
+		rules = NewRules[S, V]()
+		modes.rules[mode] = rules
+		modes.names = append(modes.names, mode)
+	}
+
+	rules.Add(fragment, value)
+	modes.actions[mode] = append(modes.actions[mode], slices.Clone(actions))
+
+	return modes
+}
+
+// Compile returns a [ModeSet] with a minimal [dfa.Dfa] for every mode.
+// An error is returned if the initial mode, or a mode that an [Action] enters, doesn't have any rules.
+func (modes *Modes[S, V]) Compile() (*ModeSet[S, V], error) {
+	set := &ModeSet[S, V]{
+		names:    slices.Clone(modes.names),
+		indices:  make(map[string]int, len(modes.names)),
+		machines: make([]*dfa.Dfa[S, V], len(modes.names)),
+		actions:  make([][][]resolvedAction, len(modes.names)),
+	}
+
+	for idx, name := range modes.names {
+		set.indices[name] = idx
+	}
+
+	initial, ok := set.indices[modes.initial]
+
+	if !ok {
+		return nil, fmt.Errorf("scanner: unknown initial mode %q", modes.initial)
+	}
+
+	set.initial = initial
+
+	for idx, name := range modes.names {
+		machine, err := modes.rules[name].CompileDfa()
+
+		if err != nil {
+			return nil, fmt.Errorf("scanner: mode %q: %w", name, err)
+		}
+
+		set.machines[idx] = machine
+		set.actions[idx] = make([][]resolvedAction, len(modes.actions[name]))
+
+		for ruleIdx, actions := range modes.actions[name] {
+			for _, action := range actions {
+				target, ok := set.indices[action.mode]
+
+				if action.op != opPop && !ok {
+					return nil, fmt.Errorf("scanner: mode %q: rule %d: unknown mode %q", name, ruleIdx, action.mode)
+				}
+
+				set.actions[idx][ruleIdx] = append(set.actions[idx][ruleIdx], resolvedAction{op: action.op, mode: target})
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// ModeSet is the compiled form of a set of [Modes].
+// Every mode has its own [dfa.Dfa], so a ModeSet can be shared by any number of [ModalLexer]s.
+type ModeSet[S comparable, V any] struct {
+	names    []string
+	indices  map[string]int
+	initial  int
+	machines []*dfa.Dfa[S, V]
+	actions  [][][]resolvedAction // The actions of every rule of every mode, indexed by the acceptance index.
+}
+
+// An [Action] where the name of the mode is replaced by its index in a [ModeSet].
+type resolvedAction struct {
+	op   actionOp
+	mode int
+}
+
+// Names returns the names of the modes, in the order in which they were declared.
+func (set *ModeSet[S, V]) Names() []string { return slices.Clone(set.names) }
+
+// Machine returns the [dfa.Dfa] of mode or nil if there's no such mode.
+func (set *ModeSet[S, V]) Machine(mode string) *dfa.Dfa[S, V] {
+	idx, ok := set.indices[mode]
+
+	if !ok {
+		return nil
+	}
+
+	return set.machines[idx]
+}
+
+// ModalLexer is a [Lexer] that switches between the modes of a [ModeSet].
+//
+// The lexer keeps a stack of modes, starting with the initial mode. Tokens are recognized by the [dfa.Dfa] of the mode
+// on top of the stack, after which the actions of the matching rule are performed on the stack.
+type ModalLexer[S comparable, V any] struct {
+	lexer *Lexer[S, V] // The lexer, which uses the [dfa.Dfa] of the current mode.
+	set   *ModeSet[S, V]
+	stack []int // The indices of the entered modes, where the last one is the current mode.
+}
+
+// NewModalLexer creates a new [ModalLexer] that tokenizes input using the modes of set.
+// See [NewLexer] for more information about opts.
+func NewModalLexer[S comparable, V any](set *ModeSet[S, V], input []S, opts ...Option) *ModalLexer[S, V] {
+	return &ModalLexer[S, V]{
+		lexer: NewLexer(set.machines[set.initial], input, opts...),
+		set:   set,
+		stack: []int{set.initial},
+	}
+}
+
+// Mode returns the name of the current mode.
+func (lexer *ModalLexer[S, V]) Mode() string { return lexer.set.names[lexer.stack[len(lexer.stack)-1]] }
+
+// Offset returns the offset of the first symbol that hasn't been consumed yet.
+func (lexer *ModalLexer[S, V]) Offset() int { return lexer.lexer.Offset() }
+
+// Position returns the [Position] of the first symbol that hasn't been consumed yet.
+// If the tracking of positions isn't enabled, the zero (invalid) position is returned.
+func (lexer *ModalLexer[S, V]) Position() Position { return lexer.lexer.Position() }
+
+// Next returns the next [Token] of the input, recognized by the rules of the current mode.
+//
+// When all the symbols have been consumed, Next returns [io.EOF]. When no rule of the current mode matches at the
+// current offset, Next behaves like [Lexer.Next] and the mode is left untouched. When the matching rule pops the only
+// mode on the stack, Next returns [ErrEmptyModeStack] and both the offset and the mode are left untouched.
+func (lexer *ModalLexer[S, V]) Next() (Token[S, V], error) {
+	if lexer.lexer.offset >= len(lexer.lexer.input) {
+		return Token[S, V]{}, io.EOF
+	}
+
+	mode := lexer.stack[len(lexer.stack)-1]
+	start := lexer.lexer.offset
+	m := longestMatch(lexer.set.machines[mode], lexer.lexer.input, start)
+
+	if m.accepting == nil {
+		return lexer.lexer.fail(start, m)
+	}
+
+	stack, ok := apply(lexer.stack, lexer.set.actions[mode][m.accepting.AcceptIdx()])
+
+	if !ok {
+		return Token[S, V]{}, ErrEmptyModeStack
+	}
+
+	lexer.stack = stack
+
+	return lexer.lexer.token(start, m.tokenEnd(lexer.lexer.input, start), m.accepting, nil), nil
+}
+
+// Returns stack after performing actions or false if an action pops the only mode on the stack.
+// The original stack is only modified if all the actions succeed.
+func apply(stack []int, actions []resolvedAction) ([]int, bool) {
+	depth := len(stack)
+
+	for _, action := range actions {
+		if action.op == opPop && depth == 1 {
+			return stack, false
+		}
+
+		if action.op == opPop {
+			depth--
+		} else if action.op == opPush {
+			depth++
+		}
+	}
+
+	for _, action := range actions {
+		switch action.op {
+		case opPush:
+			stack = append(stack, action.mode)
+		case opPop:
+			stack = stack[:len(stack)-1]
+		case opSwitch:
+			stack[len(stack)-1] = action.mode
+		}
+	}
+
+	return stack, true
+}
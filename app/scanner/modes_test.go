@@ -0,0 +1,234 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// The kinds of tokens of a language with string interpolation (e.g., "a${b}c").
+const (
+	tokName = iota + 1
+	tokQuote
+	tokText
+	tokOpen
+	tokClose
+)
+
+// Returns the modes of a language with string interpolation.
+func newInterpolationModes() *scanner.Modes[rune, int] {
+	text := scanner.Set[int](scanner.NewRuneSet(
+		scanner.RuneRange{Lo: '"', Hi: '"'}, scanner.RuneRange{Lo: '$', Hi: '$'},
+	).Negate())
+
+	return scanner.NewModes[rune, int]("code").
+		Add("code", scanner.RepeatAtLeast(1, scanner.Range[int]('a', 'z')), tokName).
+		Add("code", scanner.Literal[rune, int]('"'), tokQuote, scanner.Push("string")).
+		Add("code", scanner.Literal[rune, int]('}'), tokClose, scanner.Pop()).
+		Add("string", scanner.RepeatAtLeast(1, text), tokText).
+		Add("string", scanner.Literal[rune, int]('$', '{'), tokOpen, scanner.Push("code")).
+		Add("string", scanner.Literal[rune, int]('"'), tokQuote, scanner.Pop())
+}
+
+// UT: Compile a set of 'Modes'.
+func TestModes_Compile(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When the modes are valid, every mode has its own DFA.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		set, err := newInterpolationModes().Compile()
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When the modes are valid, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		assert.EqualSf(t, set.Names(), []string{"code", "string"}, "\n\n"+
+			"UT Name:  When the modes are valid, the modes are returned in order.\n"+
+			"\033[32mExpected: [code string].\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", set.Names())
+
+		assert.Truef(t, set.Machine("code") != set.Machine("string") && set.Machine("other") == nil, "\n\n"+
+			"UT Name:  When the modes are valid, every mode has its own DFA.\n"+
+			"\033[32mExpected: true.\033[0m\n"+
+			"\033[31mActual:   false.\033[0m\n\n")
+	})
+
+	for _, tc := range []struct {
+		name  string
+		modes *scanner.Modes[rune, int]
+		want  string
+	}{
+		{
+			"an unknown initial mode",
+			scanner.NewModes[rune, int]("other").Add("code", scanner.Literal[rune, int]('a'), tokName),
+			`scanner: unknown initial mode "other"`,
+		},
+		{
+			"an action that enters an unknown mode",
+			scanner.NewModes[rune, int]("code").Add("code", scanner.Literal[rune, int]('a'), tokName,
+				scanner.Switch("other")),
+			`scanner: mode "code": rule 0: unknown mode "other"`,
+		},
+	} {
+		// Act.
+		_, err := tc.modes.Compile()
+
+		// Assert.
+		assert.NotNilf(t, err, "\n\n"+
+			"UT Name:  When compiling modes with %s, an error is returned.\n"+
+			"\033[32mExpected: NOT <nil>.\033[0m\n"+
+			"\033[31mActual:   <nil>.\033[0m\n\n", tc.name)
+
+		assert.Equalf(t, err.Error(), tc.want, "\n\n"+
+			"UT Name:  When compiling modes with %s, the error is correct.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", tc.name, tc.want, err.Error())
+	}
+}
+
+// UT: Tokenize input using a 'ModalLexer'.
+func TestModalLexer_Next(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	set, _ := newInterpolationModes().Compile()
+
+	t.Run("When the input is valid, the tokens are recognized by the rules of the current mode.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lexer := scanner.NewModalLexer(set, []rune(`a"x ${b"y"}z"c`))
+
+		// Act & Assert.
+		for _, want := range []struct {
+			value  int
+			lexeme string
+			mode   string
+		}{
+			{tokName, "a", "code"},
+			{tokQuote, `"`, "string"},
+			{tokText, "x ", "string"},
+			{tokOpen, "${", "code"},
+			{tokName, "b", "code"},
+			{tokQuote, `"`, "string"},
+			{tokText, "y", "string"},
+			{tokQuote, `"`, "code"},
+			{tokClose, "}", "string"},
+			{tokText, "z", "string"},
+			{tokQuote, `"`, "code"},
+			{tokName, "c", "code"},
+		} {
+			token, err := lexer.Next()
+
+			assert.Nilf(t, err, "\n\n"+
+				"UT Name:  When the input is valid, NO error is returned.\n"+
+				"\033[32mExpected: <nil>.\033[0m\n"+
+				"\033[31mActual:   %v.\033[0m\n\n", err)
+
+			assert.Equalf(t, token.Value, want.value, "\n\n"+
+				"UT Name:  When the input is valid, the token %q has the correct value.\n"+
+				"\033[32mExpected: %d.\033[0m\n"+
+				"\033[31mActual:   %d.\033[0m\n\n", want.lexeme, want.value, token.Value)
+
+			assert.Equalf(t, string(token.Lexeme), want.lexeme, "\n\n"+
+				"UT Name:  When the input is valid, the token has the correct lexeme.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", want.lexeme, string(token.Lexeme))
+
+			assert.Equalf(t, lexer.Mode(), want.mode, "\n\n"+
+				"UT Name:  When the input is valid, the mode after the token %q is correct.\n"+
+				"\033[32mExpected: %s.\033[0m\n"+
+				"\033[31mActual:   %s.\033[0m\n\n", want.lexeme, want.mode, lexer.Mode())
+		}
+
+		_, err := lexer.Next()
+
+		assert.Equalf(t, err, io.EOF, "\n\n"+
+			"UT Name:  When all the input has been consumed, io.EOF is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", io.EOF, err)
+	})
+
+	t.Run("When a mode only matches after a word, its rules match when it's entered after a word.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		modes := scanner.NewModes[rune, int]("code").
+			Add("code", scanner.Literal[rune, int]('a'), tokName, scanner.Switch("inner")).
+			Add("inner", scanner.Sequence(scanner.NoWordBoundary[rune, int](), scanner.Literal[rune, int]('b')), tokText)
+
+		modeSet, _ := modes.Compile()
+		lexer := scanner.NewModalLexer(modeSet, []rune("ab"))
+
+		// Act.
+		_, _ = lexer.Next()
+		token, err := lexer.Next()
+
+		// Assert.
+		assert.Truef(t, err == nil && string(token.Lexeme) == "b", "\n\n"+
+			"UT Name:  When a mode only matches after a word, its rules match when it's entered after a word.\n"+
+			"\033[32mExpected: \"b\", <nil>.\033[0m\n"+
+			"\033[31mActual:   %q, %v.\033[0m\n\n", string(token.Lexeme), err)
+	})
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"When no rule of the current mode matches, ErrNoMatch is returned.", "$", scanner.ErrNoMatch},
+		{"When a rule pops the only mode, ErrEmptyModeStack is returned.", "}", scanner.ErrEmptyModeStack},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Arrange.
+			lexer := scanner.NewModalLexer(set, []rune(tc.input))
+
+			// Act.
+			_, err := lexer.Next()
+
+			// Assert.
+			assert.Truef(t, errors.Is(err, tc.want), "\n\n"+
+				"UT Name:  %s\n"+
+				"\033[32mExpected: %v.\033[0m\n"+
+				"\033[31mActual:   %v.\033[0m\n\n", tc.name, tc.want, err)
+
+			assert.Truef(t, lexer.Offset() == 0 && lexer.Mode() == "code", "\n\n"+
+				"UT Name:  %s The offset and the mode are left untouched.\n"+
+				"\033[32mExpected: 0, code.\033[0m\n"+
+				"\033[31mActual:   %d, %s.\033[0m\n\n", tc.name, lexer.Offset(), lexer.Mode())
+		})
+	}
+}
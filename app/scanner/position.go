@@ -0,0 +1,204 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// Position describes a location in the input of a lexer.
+//
+// A Position is valid if its line number is greater than 0.
+type Position struct {
+	Filename string // The name of the input (if any).
+	Offset   int    // The offset (in bytes), starting at 0.
+	Line     int    // The line number, starting at 1.
+	Column   int    // The column number (in runes), starting at 1.
+}
+
+// IsValid reports whether the position is valid.
+func (pos Position) IsValid() bool { return pos.Line > 0 }
+
+// String returns a string in one of the following forms (like the positions of Go's "go/token" package):
+//
+//	file:line:column    valid position with filename
+//	line:column         valid position without filename
+//	file                invalid position with filename
+//	-                   invalid position without filename
+func (pos Position) String() string {
+	str := pos.Filename
+
+	if pos.IsValid() {
+		if str != "" {
+			str += ":"
+		}
+
+		str += strconv.Itoa(pos.Line) + ":" + strconv.Itoa(pos.Column)
+	}
+
+	if str == "" {
+		str = "-"
+	}
+
+	return str
+}
+
+// Option configures a lexer.
+type Option func(*options)
+
+// The configuration of a lexer.
+type options struct {
+	positions bool
+	filename  string
+	tabWidth  int
+	recovery  recoveryMode
+
+	maxLookahead int // The maximum number of buffered symbols of a lexer that reads from a stream.
+}
+
+// WithPositions enables the tracking of positions.
+// The tokens returned by the lexer carry the [Position] of their first symbol, using filename as the name of the input.
+func WithPositions(filename string) Option {
+	return func(opts *options) {
+		opts.positions = true
+		opts.filename = filename
+	}
+}
+
+// WithTabWidth sets the width of a tab when tracking positions.
+// A tab advances the column to the next multiple of width (plus one). By default, a tab counts as a single column.
+func WithTabWidth(width int) Option {
+	return func(opts *options) {
+		opts.tabWidth = max(width, 1)
+	}
+}
+
+// Returns the configuration built from opts.
+func newOptions(opts []Option) options {
+	result := options{
+		tabWidth:     1,
+		maxLookahead: DefaultMaxLookahead,
+	}
+
+	for _, opt := range opts {
+		opt(&result)
+	}
+
+	return result
+}
+
+// A 'tracker' keeps track of the [Position] in the input of a lexer.
+//
+// Reasoning:
+// A lexer is generic over its symbols, but only rune and byte symbols have a meaningful size in bytes and can represent
+// a newline. Rather than inspecting every symbol at runtime, the function that advances the position is selected once,
+// based on the type of the symbols (see [newAdvanceFunc]).
+//
+// When position tracking is disabled, a lexer doesn't have a tracker at all, so it costs nothing.
+type tracker struct {
+	pos      Position
+	tabWidth int
+}
+
+// Returns a new [tracker] or nil if the tracking of positions isn't enabled in opts.
+func newTracker(opts options) *tracker {
+	if !opts.positions {
+		return nil
+	}
+
+	return &tracker{
+		pos: Position{
+			Filename: opts.filename,
+			Offset:   0,
+			Line:     1,
+			Column:   1,
+		},
+		tabWidth: opts.tabWidth,
+	}
+}
+
+// Returns a function that advances the position of t past a single symbol of type S.
+//
+// Runes are counted as their size when encoded in UTF-8. Bytes are counted as a single byte, but only the bytes that
+// start a UTF-8 encoded rune advance the column. Symbols of any other type advance the offset and the column by one.
+func newAdvanceFunc[S comparable](t *tracker) func(S) {
+	var zero S
+
+	switch any(zero).(type) {
+	case rune:
+		return any(t.advanceRune).(func(S))
+	case byte:
+		return any(t.advanceByte).(func(S))
+	default:
+		return func(S) {
+			t.pos.Offset++
+			t.pos.Column++
+		}
+	}
+}
+
+// Advances the position past r.
+func (t *tracker) advanceRune(r rune) {
+	size := utf8.RuneLen(r)
+
+	if size < 0 {
+		size = len(string(utf8.RuneError))
+	}
+
+	t.advance(r, size)
+}
+
+// Advances the position past r, which takes size bytes in the input.
+func (t *tracker) advance(r rune, size int) {
+	t.pos.Offset += size
+	t.advanceColumn(r)
+}
+
+// Advances the position past b.
+func (t *tracker) advanceByte(b byte) {
+	t.pos.Offset++
+
+	// NOTE: Continuation bytes of a UTF-8 encoded rune don't start a new column.
+	if !utf8.RuneStart(b) {
+		return
+	}
+
+	t.advanceColumn(rune(b))
+}
+
+// Advances the line or column past the rune r.
+func (t *tracker) advanceColumn(r rune) {
+	switch r {
+	case '\n':
+		t.pos.Line++
+		t.pos.Column = 1
+	case '\t':
+		t.pos.Column = ((t.pos.Column-1)/t.tabWidth+1)*t.tabWidth + 1
+	default:
+		t.pos.Column++
+	}
+}
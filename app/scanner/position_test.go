@@ -0,0 +1,213 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// UT: Format a 'Position'.
+func TestPosition_String(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		pos  scanner.Position
+		want string
+	}{
+		{scanner.Position{Filename: "in.txt", Offset: 4, Line: 2, Column: 3}, "in.txt:2:3"},
+		{scanner.Position{Offset: 4, Line: 2, Column: 3}, "2:3"},
+		{scanner.Position{Filename: "in.txt"}, "in.txt"},
+		{scanner.Position{}, "-"},
+	} {
+		// Act.
+		got := tc.pos.String()
+
+		// Assert.
+		assert.Equalf(t, got, tc.want, "\n\n"+
+			"UT Name:  When formatting the position %#v, the result is correct.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", tc.pos, tc.want, got)
+	}
+}
+
+// Returns a [dfa.Dfa] recognizing words made of the symbols in word, and any single symbol in spaces.
+func newPositionMachine[S ~rune | ~byte](word []S, spaces ...S) *dfa.Dfa[S, int] {
+	letters := make([]scanner.Fragment[S, int], 0, len(word))
+
+	for _, symbol := range word {
+		letters = append(letters, scanner.Literal[S, int](symbol))
+	}
+
+	whitespace := make([]scanner.Fragment[S, int], 0, len(spaces))
+
+	for _, symbol := range spaces {
+		whitespace = append(whitespace, scanner.Literal[S, int](symbol))
+	}
+
+	machine, _ := scanner.NewRules[S, int]().
+		Add(scanner.RepeatAtLeast(1, scanner.AnyOf(letters...)), tokIdent).
+		Add(scanner.AnyOf(whitespace...), tokSpace).
+		CompileDfa()
+
+	return machine
+}
+
+// UT: Track the positions of tokens.
+func TestLexer_Positions(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	const input = "ab é\n\tba"
+
+	want := []string{"in.txt:1:1", "in.txt:1:3", "in.txt:1:4", "in.txt:1:5", "in.txt:2:1", "in.txt:2:5"}
+	wantOffsets := []int{0, 2, 3, 5, 6, 7}
+
+	t.Run("When tokenizing runes, the positions are correct.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newPositionMachine([]rune("abé"), ' ', '\n', '\t')
+		lexer := scanner.NewLexer(machine, []rune(input), scanner.WithPositions("in.txt"), scanner.WithTabWidth(4))
+
+		// Act.
+		var got []string
+		var gotOffsets []int
+
+		for token, err := lexer.Next(); err == nil; token, err = lexer.Next() {
+			got = append(got, token.Pos.String())
+			gotOffsets = append(gotOffsets, token.Pos.Offset)
+		}
+
+		// Assert.
+		assert.EqualSf(t, got, want, "\n\n"+
+			"UT Name:  When tokenizing runes, the positions are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, got)
+
+		assert.EqualSf(t, gotOffsets, wantOffsets, "\n\n"+
+			"UT Name:  When tokenizing runes, the byte offsets are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", wantOffsets, gotOffsets)
+
+		assert.Equalf(t, lexer.Position().String(), "in.txt:2:7", "\n\n"+
+			"UT Name:  When all the input is consumed, the position is directly after the last rune.\n"+
+			"\033[32mExpected: in.txt:2:7.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", lexer.Position())
+	})
+
+	t.Run("When tokenizing bytes, the positions are correct.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newPositionMachine([]byte("abé"), ' ', '\n', '\t')
+		lexer := scanner.NewLexer(machine, []byte(input), scanner.WithPositions("in.txt"), scanner.WithTabWidth(4))
+
+		// Act.
+		var got []string
+		var gotOffsets []int
+
+		for token, err := lexer.Next(); err == nil; token, err = lexer.Next() {
+			got = append(got, token.Pos.String())
+			gotOffsets = append(gotOffsets, token.Pos.Offset)
+		}
+
+		// Assert.
+		assert.EqualSf(t, got, want, "\n\n"+
+			"UT Name:  When tokenizing bytes, the positions are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, got)
+
+		assert.EqualSf(t, gotOffsets, wantOffsets, "\n\n"+
+			"UT Name:  When tokenizing bytes, the byte offsets are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", wantOffsets, gotOffsets)
+	})
+
+	t.Run("When tokenizing a stream, the positions are correct.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newPositionMachine([]rune("abé"), ' ', '\n', '\t')
+		lexer := scanner.NewReaderLexer(machine, strings.NewReader(input), scanner.WithPositions("in.txt"),
+			scanner.WithTabWidth(4))
+
+		// Act.
+		var got []string
+
+		for token, err := lexer.Next(); err == nil; token, err = lexer.Next() {
+			got = append(got, token.Pos.String())
+		}
+
+		// Assert.
+		assert.EqualSf(t, got, want, "\n\n"+
+			"UT Name:  When tokenizing a stream, the positions are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, got)
+	})
+
+	t.Run("When tokenizing a stream with invalid UTF-8, the byte offsets are correct.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newPositionMachine([]rune("ab\uFFFD"), ' ', '\n')
+		lexer := scanner.NewReaderLexer(machine, strings.NewReader("a\xff\xffb a"), scanner.WithPositions("in.txt"))
+
+		// Act.
+		var got []int
+
+		for token, err := lexer.Next(); err == nil; token, err = lexer.Next() {
+			got = append(got, token.Pos.Offset)
+		}
+
+		got = append(got, lexer.Position().Offset)
+
+		// Assert.
+		assert.EqualSf(t, got, []int{0, 4, 5, 6}, "\n\n"+
+			"UT Name:  When tokenizing a stream with invalid UTF-8, every invalid byte counts as a single byte.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", []int{0, 4, 5, 6}, got)
+	})
+
+	t.Run("When the tracking of positions is disabled, the positions are invalid.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lexer := scanner.NewLexer(newPositionMachine([]rune("ab"), ' ', '\n'), []rune("ab"))
+
+		// Act.
+		token, _ := lexer.Next()
+
+		// Assert.
+		assert.Falsef(t, token.Pos.IsValid(), "\n\n"+
+			"UT Name:  When the tracking of positions is disabled, the position of a token is invalid.\n"+
+			"\033[32mExpected: false.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", token.Pos.IsValid())
+	})
+}
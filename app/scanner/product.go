@@ -0,0 +1,147 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// The boolean operations supported by a [fragProduct].
+type productOp int
+
+const (
+	opIntersect productOp = iota
+	opDifference
+	opComplement
+)
+
+// A [Fragment] that matches the result of a boolean operation on the languages of [Fragment]s.
+//
+// Reasoning:
+// Intersection, difference and complement can't be expressed by combining Nfa parts, since an Nfa accepts an input as
+// soon as one of its paths does. Instead, the operands are compiled into a [dfa.Dfa] each, the operation is performed
+// using a product construction and the resulting [dfa.Dfa] is integrated into the Nfa that's being built.
+// An operand that can't be determinized is reported as a [*FragmentError] by [Rules.CompileDfa].
+type fragProduct[S comparable, V any] struct {
+	op       productOp
+	a        Fragment[S, V]
+	b        Fragment[S, V]
+	alphabet []S
+}
+
+// Intersect creates a [Fragment] that matches the inputs that are matched by both a and b.
+//
+// Example: Intersect(identifier, RepeatBetween(1, 8, letterOrDigit)) matches identifiers of at most 8 symbols.
+func Intersect[S comparable, V any](a, b Fragment[S, V]) Fragment[S, V] {
+	return fragProduct[S, V]{
+		op: opIntersect,
+		a:  a,
+		b:  b,
+	}
+}
+
+// Difference creates a [Fragment] that matches the inputs that are matched by a, but NOT by b.
+//
+// Example: Difference(identifier, keyword) matches every identifier that isn't a keyword.
+func Difference[S comparable, V any](a, b Fragment[S, V]) Fragment[S, V] {
+	return fragProduct[S, V]{
+		op: opDifference,
+		a:  a,
+		b:  b,
+	}
+}
+
+// Complement creates a [Fragment] that matches every sequence of symbols of alphabet that's NOT matched by fragment.
+// This includes the empty sequence, unless fragment matches it.
+//
+// Example: Complement(Sequence(anything, Literal('*', '/'), anything), alphabet...) matches any input that doesn't
+// contain "*/".
+func Complement[S comparable, V any](fragment Fragment[S, V], alphabet ...S) Fragment[S, V] {
+	return fragProduct[S, V]{
+		op:       opComplement,
+		a:        fragment,
+		alphabet: alphabet,
+	}
+}
+
+// Build compiles the operands, performs the operation and integrates the result into machine.
+//
+// Panics with a [*FragmentError] if an operand can't be determinized (see [dfa.TryFromNfa]). [Rules.CompileDfa] and
+// [Modes.Compile] return that error instead.
+func (frag fragProduct[S, V]) Build(machine *nfa.Nfa[S, V], startState *nfa.State[S, V]) *nfa.State[S, V] {
+	var result *dfa.Dfa[S, V]
+
+	switch frag.op {
+	case opIntersect:
+		result = dfa.Intersect(compileFragment(frag.a), compileFragment(frag.b), nil)
+	case opDifference:
+		result = dfa.Difference(compileFragment(frag.a), compileFragment(frag.b), nil)
+	case opComplement:
+		var zero V
+
+		result = dfa.Complement(compileFragment(frag.a), frag.alphabet, zero)
+	}
+
+	result, _ = dfa.Minimize(result)
+
+	return result.Build(machine, startState)
+}
+
+// Returns a [dfa.Dfa] that accepts the inputs matched by fragment.
+// Panics with a [*FragmentError] if fragment can't be determinized.
+func compileFragment[S comparable, V any](fragment Fragment[S, V]) *dfa.Dfa[S, V] {
+	var zero V
+
+	machine := nfa.New[S, V]()
+	machine.AddAcceptingEpsilonTransition(fragment.Build(machine, machine.Start()), zero)
+
+	result, err := dfa.TryFromNfa(machine)
+
+	if err != nil {
+		panic(&FragmentError{Err: err})
+	}
+
+	return result
+}
+
+// FragmentError describes a [Fragment] that can't be built, such as an operand of [Intersect], [Difference] or
+// [Complement] that can't be determinized.
+//
+// Reasoning:
+// [Fragment.Build] doesn't return an error, so a fragment that can't be built panics with a FragmentError, which
+// [Rules.CompileDfa] recovers and returns.
+type FragmentError struct {
+	Err error // The reason why the fragment can't be built.
+}
+
+// Error returns a description of the error.
+func (err *FragmentError) Error() string {
+	return "scanner: fragment can't be built: " + err.Err.Error()
+}
+
+// Unwrap returns the reason why the fragment can't be built.
+func (err *FragmentError) Unwrap() error { return err.Err }
@@ -0,0 +1,106 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/nfa"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// Reports whether the complete input is matched by fragment.
+func fullMatch(fragment scanner.Fragment[rune, int], input string) bool {
+	machine := nfa.New[rune, int]()
+	machine.AddAcceptingEpsilonTransition(fragment.Build(machine, machine.Start()), 1)
+
+	runes := []rune(input)
+	match, ok := machine.Match(runes)
+
+	return ok && match.Length == len(runes)
+}
+
+// UT: Combine 'Fragment's using boolean operations.
+func TestProduct(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	identifier := scanner.RepeatAtLeast(1, scanner.SymbolSet[rune, int](unicode.IsLetter))
+	keyword := scanner.AnyOf(scanner.Literal[rune, int]('i', 'f'), scanner.Literal[rune, int]('i', 'n'))
+	anything := scanner.RepeatAtLeast(0, scanner.SymbolSet[rune, int](func(rune) bool { return true }))
+	endOfComment := scanner.Sequence(anything, scanner.Literal[rune, int]('*', '/'), anything)
+
+	for _, tc := range []struct {
+		name     string
+		fragment scanner.Fragment[rune, int]
+		accepted []string
+		rejected []string
+	}{
+		{
+			"an identifier that isn't a keyword",
+			scanner.Difference(identifier, keyword),
+			[]string{"i", "iff", "fi", "é"},
+			[]string{"", "if", "in", "i1"},
+		},
+		{
+			"a short identifier",
+			scanner.Intersect(identifier, scanner.RepeatBetween(1, 2, scanner.SymbolSet[rune, int](unicode.IsLower))),
+			[]string{"a", "ab"},
+			[]string{"", "abc", "A"},
+		},
+		{
+			"anything except */",
+			scanner.Difference(anything, endOfComment),
+			[]string{"", "*", "/*", "a*b/c"},
+			[]string{"*/", "a*/", "*/b"},
+		},
+		{
+			"anything over an alphabet except a keyword",
+			scanner.Complement(keyword, 'i', 'f', 'n'),
+			[]string{"", "i", "ff", "iff", "ni"},
+			[]string{"if", "in", "x"},
+		},
+	} {
+		for _, input := range tc.accepted {
+			got := fullMatch(tc.fragment, input)
+
+			assert.Truef(t, got, "\n\n"+
+				"UT Name:  When matching %s, the input %q is accepted.\n"+
+				"\033[32mExpected: true.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.name, input, got)
+		}
+
+		for _, input := range tc.rejected {
+			got := fullMatch(tc.fragment, input)
+
+			assert.Falsef(t, got, "\n\n"+
+				"UT Name:  When matching %s, the input %q is rejected.\n"+
+				"\033[32mExpected: false.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.name, input, got)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// The initial capacity (in symbols) of the buffer of a [ReaderLexer] or a [ByteReaderLexer].
+const readerLexerBufferSize = 256
+
+// DefaultMaxLookahead is the maximum number of symbols that a [ReaderLexer] or a [ByteReaderLexer] buffers while
+// looking for the longest match, unless another maximum is set with [WithMaxLookahead].
+const DefaultMaxLookahead = 1 << 20
+
+// ErrLookaheadExceeded is returned by [ReaderLexer.Next] and [ByteReaderLexer.Next] when the longest match can't be
+// determined without buffering more symbols than the maximum lookahead (see [WithMaxLookahead]).
+var ErrLookaheadExceeded = errors.New("scanner: the longest match exceeds the maximum lookahead")
+
+// WithMaxLookahead sets the maximum number of symbols that a [ReaderLexer] or a [ByteReaderLexer] buffers while looking
+// for the longest match (the token and the lookahead that's needed to recognize it). A maximum of 0 or less removes the
+// limit. By default, the maximum is [DefaultMaxLookahead].
+//
+// Reasoning:
+// A rule such as `"[^"]*"` keeps matching until the closing quote, so on input without one, the lexer would read all
+// of it into memory. The maximum bounds the memory that a single token can take. Since a [Lexer] holds all its input
+// up front, it doesn't use this option.
+func WithMaxLookahead(n int) Option {
+	return func(opts *options) {
+		opts.maxLookahead = n
+	}
+}
+
+// ReaderLexer splits a stream of runes into [Token]s by driving a [dfa.Dfa] over it.
+//
+// It recognizes tokens the same way as a [Lexer] does, but instead of requiring all the input up front, runes are read
+// from an [io.RuneReader] on demand: the buffer is only refilled when the dfa needs more lookahead to find the longest
+// match. Once a token is returned, the runes it's made of are discarded, so the buffer only ever holds the current
+// token and the lookahead that was needed to recognize it. This makes it possible to tokenize inputs that don't fit in
+// memory. The buffer never holds more runes than the maximum lookahead (see [WithMaxLookahead]).
+//
+// Since the buffer is reused, the Lexeme of a returned [Token] is only valid until the next call to
+// [ReaderLexer.Next]. To tokenize a stream of bytes instead, use a [ByteReaderLexer].
+type ReaderLexer[V any] struct {
+	stream *streamLexer[rune, V]
+}
+
+// NewReaderLexer creates a new [ReaderLexer] that tokenizes the runes read from reader using machine.
+//
+// If reader doesn't implement [io.RuneReader], it's wrapped in a [bufio.Reader].
+func NewReaderLexer[V any](machine *dfa.Dfa[rune, V], reader io.Reader, opts ...Option) *ReaderLexer[V] {
+	runeReader, ok := reader.(io.RuneReader)
+
+	if !ok {
+		runeReader = bufio.NewReader(reader)
+	}
+
+	stream := newStreamLexer(machine, runeReader.ReadRune, opts)
+
+	if stream.tracker != nil {
+		stream.advance = stream.tracker.advance
+	}
+
+	return &ReaderLexer[V]{stream: stream}
+}
+
+// Offset returns the offset (in runes) of the first rune that hasn't been consumed yet.
+func (lexer *ReaderLexer[V]) Offset() int { return lexer.stream.offset }
+
+// Position returns the [Position] of the first rune that hasn't been consumed yet.
+// If the tracking of positions isn't enabled, the zero (invalid) position is returned.
+func (lexer *ReaderLexer[V]) Position() Position { return lexer.stream.position() }
+
+// Next returns the next [Token] of the input.
+//
+// When all the runes have been consumed, Next returns [io.EOF]. When no rule matches at the current offset, Next
+// returns an [*Error] (which wraps [ErrNoMatch]) and the offset is left untouched, unless the lexer recovers from such
+// input by returning an error token (see [WithErrorTokens] and [ReaderLexer.SkipUntil]). An error token never spans
+// more runes than the maximum lookahead.
+//
+// When the longest match can't be determined without exceeding the maximum lookahead (see [WithMaxLookahead]), Next
+// returns [ErrLookaheadExceeded] and the offset is left untouched.
+//
+// When the reader returns an error other than [io.EOF], that error is returned as is, both now and on every subsequent
+// call. Since the reader failed, the longest match can't be determined anymore, so no further tokens are returned.
+func (lexer *ReaderLexer[V]) Next() (Token[rune, V], error) { return lexer.stream.next() }
+
+// ByteReaderLexer splits a stream of bytes into [Token]s by driving a [dfa.Dfa] over it.
+//
+// It's the counterpart of a [ReaderLexer] for a [dfa.Dfa] over bytes: bytes are read from an [io.ByteReader] on demand
+// and discarded once they're part of a returned token.
+//
+// Since the buffer is reused, the Lexeme of a returned [Token] is only valid until the next call to
+// [ByteReaderLexer.Next].
+type ByteReaderLexer[V any] struct {
+	stream *streamLexer[byte, V]
+}
+
+// NewByteReaderLexer creates a new [ByteReaderLexer] that tokenizes the bytes read from reader using machine.
+//
+// If reader doesn't implement [io.ByteReader], it's wrapped in a [bufio.Reader].
+func NewByteReaderLexer[V any](machine *dfa.Dfa[byte, V], reader io.Reader, opts ...Option) *ByteReaderLexer[V] {
+	byteReader, ok := reader.(io.ByteReader)
+
+	if !ok {
+		byteReader = bufio.NewReader(reader)
+	}
+
+	read := func() (byte, int, error) {
+		b, err := byteReader.ReadByte()
+
+		return b, 1, err
+	}
+
+	stream := newStreamLexer(machine, read, opts)
+
+	if stream.tracker != nil {
+		stream.advance = func(b byte, _ int) { stream.tracker.advanceByte(b) }
+	}
+
+	return &ByteReaderLexer[V]{stream: stream}
+}
+
+// Offset returns the offset (in bytes) of the first byte that hasn't been consumed yet.
+func (lexer *ByteReaderLexer[V]) Offset() int { return lexer.stream.offset }
+
+// Position returns the [Position] of the first byte that hasn't been consumed yet.
+// If the tracking of positions isn't enabled, the zero (invalid) position is returned.
+func (lexer *ByteReaderLexer[V]) Position() Position { return lexer.stream.position() }
+
+// Next returns the next [Token] of the input. It behaves like [ReaderLexer.Next].
+func (lexer *ByteReaderLexer[V]) Next() (Token[byte, V], error) { return lexer.stream.next() }
+
+// A 'streamLexer' holds the state of a lexer that reads its symbols from a stream (see [ReaderLexer]).
+type streamLexer[S comparable, V any] struct {
+	machine      *dfa.Dfa[S, V]
+	read         func() (S, int, error) // Reads a single symbol and returns its size (in bytes).
+	buf          []S                    // The symbols that have been read, but haven't been consumed yet.
+	sizes        []int                  // The size (in bytes) of every symbol in buf, as reported by read.
+	used         int                    // The number of symbols at the start of buf that belong to the previous token.
+	offset       int                    // The offset of the first symbol that hasn't been consumed yet.
+	prev         nfa.SymbolKind         // The kind of the last symbol that has been consumed (for the assertions).
+	err          error                  // The error returned by read (if any).
+	maxLookahead int                    // The maximum number of symbols in buf (0 or less if there's no maximum).
+	exceeded     bool                   // True if a symbol couldn't be read, since buf holds maxLookahead symbols.
+	tracker      *tracker               // Tracks the position in the input (nil when disabled).
+	advance      func(S, int)           // Advances the tracker past a single symbol and its size.
+	recovery     recoveryMode
+	sync         map[S]bool // The synchronisation set when skipping input that no rule matches.
+}
+
+// Returns a new 'streamLexer' that tokenizes the symbols returned by read using machine.
+func newStreamLexer[S comparable, V any](
+	machine *dfa.Dfa[S, V], read func() (S, int, error), opts []Option,
+) *streamLexer[S, V] {
+	config := newOptions(opts)
+
+	return &streamLexer[S, V]{
+		machine:      machine,
+		read:         read,
+		buf:          make([]S, 0, readerLexerBufferSize),
+		sizes:        make([]int, 0, readerLexerBufferSize),
+		used:         0,
+		offset:       0,
+		prev:         nfa.KindNone,
+		err:          nil,
+		maxLookahead: config.maxLookahead,
+		tracker:      newTracker(config),
+		recovery:     config.recovery,
+	}
+}
+
+// Returns the [Position] of the first symbol that hasn't been consumed yet.
+func (lexer *streamLexer[S, V]) position() Position {
+	if lexer.tracker == nil {
+		return Position{}
+	}
+
+	return lexer.tracker.pos
+}
+
+// Returns the next [Token] of the input (see [ReaderLexer.Next]).
+func (lexer *streamLexer[S, V]) next() (Token[S, V], error) {
+	lexer.discard()
+	lexer.exceeded = false
+
+	m := match[S, V]{
+		end:   -1,
+		stuck: lexer.machine.StartAfter(lexer.prev),
+	}
+
+	for idx := 0; ; idx++ {
+		if idx == len(lexer.buf) && !lexer.fill() {
+			m.stuckAt = idx
+
+			break
+		}
+
+		state := m.stuck.OutgoingFor(lexer.buf[idx])
+
+		if state == nil {
+			m.stuckAt = idx
+
+			break
+		}
+
+		m.stuck = state
+
+		// NOTE: The next symbol is only read ahead when the acceptance of the state depends on it.
+		next := nfa.KindNone
+
+		if state.IsConditional() && (idx+1 < len(lexer.buf) || lexer.fill()) {
+			next = nfa.KindOf(lexer.buf[idx+1])
+		}
+
+		if accepting := state.AcceptingBefore(next); accepting != nil {
+			m.accepting = accepting
+			m.end = idx + 1
+		}
+	}
+
+	if lexer.err != nil && lexer.err != io.EOF {
+		return Token[S, V]{}, lexer.err
+	}
+
+	if lexer.exceeded {
+		return Token[S, V]{}, ErrLookaheadExceeded
+	}
+
+	if m.accepting != nil {
+		return lexer.token(m.tokenEnd(lexer.buf, 0), m.accepting, nil), nil
+	}
+
+	if len(lexer.buf) == 0 {
+		return Token[S, V]{}, io.EOF
+	}
+
+	// NOTE: The offsets of the match are relative to the start of the buffer.
+	m.stuckAt += lexer.offset
+	err := m.error(lexer.offset, lexer.position())
+
+	if lexer.recovery == recoverAbort {
+		return Token[S, V]{}, err
+	}
+
+	end := 1
+
+	for lexer.recovery == recoverSkip && (end < len(lexer.buf) || lexer.fill()) && !lexer.sync[lexer.buf[end]] {
+		end++
+	}
+
+	if lexer.err != nil && lexer.err != io.EOF {
+		return Token[S, V]{}, lexer.err
+	}
+
+	return lexer.token(end, nil, err), nil
+}
+
+// Consumes the first end symbols of the buffer and returns them as a token, accepted by accepting or described by err.
+func (lexer *streamLexer[S, V]) token(end int, accepting *dfa.State[S, V], err *Error[S]) Token[S, V] {
+	start := lexer.offset
+	pos := lexer.track(end)
+
+	lexer.used = end
+	lexer.offset += end
+
+	if end > 0 {
+		lexer.prev = nfa.KindOf(lexer.buf[end-1])
+	}
+
+	token := Token[S, V]{
+		Start:  start,
+		End:    lexer.offset,
+		Lexeme: lexer.buf[:end:end],
+		Pos:    pos,
+		Err:    err,
+	}
+
+	if accepting != nil {
+		token.Value = accepting.AcceptValue()
+	}
+
+	return token
+}
+
+// Advances the tracker (if any) past the first end symbols of the buffer and returns the position of the first one.
+//
+// Reasoning:
+// The offset is advanced by the number of bytes the reader consumed for every rune, rather than by the size of its
+// UTF-8 encoding, since an invalid byte is read as [utf8.RuneError], which is encoded in 3 bytes.
+func (lexer *streamLexer[S, V]) track(end int) Position {
+	if lexer.tracker == nil {
+		return Position{}
+	}
+
+	pos := lexer.tracker.pos
+
+	for idx, symbol := range lexer.buf[:end] {
+		lexer.advance(symbol, lexer.sizes[idx])
+	}
+
+	return pos
+}
+
+// Removes the symbols of the previous token from the buffer.
+// The remaining symbols (the lookahead) are moved to the start of the buffer, so its capacity is reused.
+func (lexer *streamLexer[S, V]) discard() {
+	if lexer.used == 0 {
+		return
+	}
+
+	n := copy(lexer.buf, lexer.buf[lexer.used:])
+	copy(lexer.sizes, lexer.sizes[lexer.used:])
+
+	lexer.buf = lexer.buf[:n]
+	lexer.sizes = lexer.sizes[:n]
+	lexer.used = 0
+}
+
+// Reads a single symbol and appends it to the buffer.
+// It returns false if the symbol couldn't be read, in which case the error is recorded, or if the buffer already holds
+// the maximum lookahead.
+func (lexer *streamLexer[S, V]) fill() bool {
+	if lexer.err != nil {
+		return false
+	}
+
+	if lexer.maxLookahead > 0 && len(lexer.buf) >= lexer.maxLookahead {
+		lexer.exceeded = true
+
+		return false
+	}
+
+	symbol, size, err := lexer.read()
+
+	if err != nil {
+		lexer.err = err
+
+		return false
+	}
+
+	lexer.buf = append(lexer.buf, symbol)
+	lexer.sizes = append(lexer.sizes, size)
+
+	return true
+}
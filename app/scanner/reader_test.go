@@ -0,0 +1,246 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// Returns the values and lexemes of all the tokens produced by lexer, together with the error that stopped it.
+func collectReaderTokens(lexer *scanner.ReaderLexer[int]) ([]int, []string, error) {
+	var (
+		values  []int
+		lexemes []string
+	)
+
+	for {
+		token, err := lexer.Next()
+
+		if err != nil {
+			return values, lexemes, err
+		}
+
+		values = append(values, token.Value)
+		lexemes = append(lexemes, string(token.Lexeme))
+	}
+}
+
+// UT: Tokenize a stream using a 'ReaderLexer'.
+func TestReaderLexer_Next(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When the input is valid, the correct tokens are returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lexer := scanner.NewReaderLexer(newLexerMachine(), iotest.OneByteReader(strings.NewReader("if iff x")))
+
+		// Act.
+		values, lexemes, err := collectReaderTokens(lexer)
+
+		// Assert.
+		assert.Errorf(t, err, io.EOF, "\n\n"+
+			"UT Name:  When all the input is consumed, 'io.EOF' is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", io.EOF, err)
+
+		assert.EqualSf(t, values, []int{tokKeyword, tokSpace, tokIdent, tokSpace, tokIdent}, "\n\n"+
+			"UT Name:  When the input is valid, the correct values are returned.\n"+
+			"\033[32mExpected: [1 3 2 3 2].\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", values)
+
+		assert.EqualSf(t, lexemes, []string{"if", " ", "iff", " ", "x"}, "\n\n"+
+			"UT Name:  When the input is valid, the correct lexemes are returned.\n"+
+			"\033[32mExpected: [if   iff   x].\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", lexemes)
+
+		assert.Equalf(t, lexer.Offset(), 8, "\n\n"+
+			"UT Name:  When all the input is consumed, the offset equals the number of runes.\n"+
+			"\033[32mExpected: 8.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", lexer.Offset())
+	})
+
+	t.Run("When no rule matches, 'ErrNoMatch' is returned and the offset is unchanged.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lexer := scanner.NewReaderLexer(newLexerMachine(), strings.NewReader("if?"))
+
+		// Act.
+		values, _, err := collectReaderTokens(lexer)
+
+		// Assert.
+		assert.Errorf(t, err, scanner.ErrNoMatch, "\n\n"+
+			"UT Name:  When no rule matches, 'ErrNoMatch' is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", scanner.ErrNoMatch, err)
+
+		assert.EqualSf(t, values, []int{tokKeyword}, "\n\n"+
+			"UT Name:  When no rule matches, the tokens before it are returned.\n"+
+			"\033[32mExpected: [1].\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", values)
+
+		assert.Equalf(t, lexer.Offset(), 2, "\n\n"+
+			"UT Name:  When no rule matches, the offset is unchanged.\n"+
+			"\033[32mExpected: 2.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", lexer.Offset())
+	})
+
+	t.Run("When the reader fails, its error is returned instead of 'ErrNoMatch'.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		errRead := errors.New("read failed")
+		reader := io.MultiReader(strings.NewReader("if i"), iotest.ErrReader(errRead))
+		lexer := scanner.NewReaderLexer(newLexerMachine(), reader)
+
+		// Act.
+		values, _, err := collectReaderTokens(lexer)
+		_, again := lexer.Next()
+
+		// Assert.
+		assert.Errorf(t, err, errRead, "\n\n"+
+			"UT Name:  When the reader fails, its error is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", errRead, err)
+
+		assert.Errorf(t, again, errRead, "\n\n"+
+			"UT Name:  When the reader has failed, its error is returned on every subsequent call.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", errRead, again)
+
+		assert.EqualSf(t, values, []int{tokKeyword, tokSpace}, "\n\n"+
+			"UT Name:  When the reader fails, the tokens that were complete before it are returned.\n"+
+			"\033[32mExpected: [1 3].\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", values)
+	})
+	t.Run("When a token exceeds the maximum lookahead, 'ErrLookaheadExceeded' is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		lexer := scanner.NewReaderLexer(newLexerMachine(), strings.NewReader("if xxxxx"), scanner.WithMaxLookahead(4))
+
+		// Act.
+		values, _, err := collectReaderTokens(lexer)
+
+		// Assert.
+		assert.Errorf(t, err, scanner.ErrLookaheadExceeded, "\n\n"+
+			"UT Name:  When a token exceeds the maximum lookahead, 'ErrLookaheadExceeded' is returned.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", scanner.ErrLookaheadExceeded, err)
+
+		assert.EqualSf(t, values, []int{tokKeyword, tokSpace}, "\n\n"+
+			"UT Name:  When a token exceeds the maximum lookahead, the tokens before it are returned.\n"+
+			"\033[32mExpected: [1 3].\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", values)
+
+		assert.Equalf(t, lexer.Offset(), 3, "\n\n"+
+			"UT Name:  When a token exceeds the maximum lookahead, the offset is unchanged.\n"+
+			"\033[32mExpected: 3.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", lexer.Offset())
+	})
+}
+
+// UT: Tokenize a stream of bytes using a 'ByteReaderLexer'.
+func TestByteReaderLexer_Next(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When the input is valid, the correct tokens are returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		machine := newPositionMachine([]byte("ab"), ' ', '\n')
+		lexer := scanner.NewByteReaderLexer(machine, strings.NewReader("ab\nba"), scanner.WithPositions("f"))
+
+		// Act.
+		got := lexemes(lexer.Next)
+
+		// Assert.
+		want := []string{"ab", "\n", "ba"}
+
+		assert.EqualSf(t, got, want, "\n\n"+
+			"UT Name:  When the input is valid, the correct lexemes are returned.\n"+
+			"\033[32mExpected: %q.\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", want, got)
+
+		assert.Truef(t, lexer.Offset() == 5 && lexer.Position().String() == "f:2:3", "\n\n"+
+			"UT Name:  When all the input is consumed, the offset and the position are after the last byte.\n"+
+			"\033[32mExpected: 5, f:2:3.\033[0m\n"+
+			"\033[31mActual:   %d, %s.\033[0m\n\n", lexer.Offset(), lexer.Position())
+	})
+
+	t.Run("When skipping input that no rule matches, the error token ends before the synchronisation byte.",
+		func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Arrange.
+			machine := newPositionMachine([]byte("ab"), ' ', '\n')
+			lexer := scanner.NewByteReaderLexer(machine, strings.NewReader("a?? b")).SkipUntil(' ')
+
+			// Act.
+			got := lexemes(lexer.Next)
+
+			// Assert.
+			want := []string{"a", "<??>", " ", "b"}
+
+			assert.EqualSf(t, got, want, "\n\n"+
+				"UT Name:  When skipping input that no rule matches, the error token ends before the synchronisation byte.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", want, got)
+		})
+}
+
+var benchmarkReaderTokenOutput scanner.Token[rune, int] // Output of the benchmark(s).
+
+// Benchmark(s): Tokenize a stream.
+func BenchmarkReaderLexer_Next_100(b *testing.B)   { benchmarkReaderLexer_Next(100, b) }
+func BenchmarkReaderLexer_Next_10000(b *testing.B) { benchmarkReaderLexer_Next(10_000, b) }
+
+func benchmarkReaderLexer_Next(count int, b *testing.B) {
+	machine := newLexerMachine()
+	input := strings.Repeat("if x", count)
+
+	for b.Loop() {
+		lexer := scanner.NewReaderLexer(machine, strings.NewReader(input))
+
+		for {
+			token, err := lexer.Next()
+
+			if err != nil {
+				break
+			}
+
+			benchmarkReaderTokenOutput = token
+		}
+	}
+}
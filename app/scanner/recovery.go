@@ -0,0 +1,192 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/kdeconinck/realign/automata/dfa"
+)
+
+// Error describes input that no rule matches.
+// Since it wraps [ErrNoMatch], errors.Is(err, ErrNoMatch) reports whether err is an Error.
+type Error[S comparable] struct {
+	Start int      // The offset at which no rule matches.
+	Pos   Position // The position of Start (only set when the tracking of positions is enabled).
+
+	// The offset of the first symbol that can't be part of any token starting at Start. It equals the length of the
+	// input if the input ends before a token is recognized.
+	Offset int
+
+	// The symbols that would have been accepted at Offset. Symbols that are only accepted through a predicate (e.g., a
+	// [RuneSet]) aren't listed, but are reported by Predicates.
+	Expected   []S
+	Predicates bool // True if symbols other than the Expected ones would have been accepted through a predicate.
+}
+
+// Error returns a description of the error, including its position (if tracked) or its offset.
+func (err *Error[S]) Error() string {
+	if err.Pos.IsValid() {
+		return fmt.Sprintf("scanner: %s: no rule matches the input", err.Pos)
+	}
+
+	return fmt.Sprintf("scanner: offset %d: no rule matches the input", err.Start)
+}
+
+// Unwrap returns [ErrNoMatch].
+func (err *Error[S]) Unwrap() error { return ErrNoMatch }
+
+// The ways in which a lexer can recover from input that no rule matches.
+type recoveryMode int
+
+const (
+	recoverAbort recoveryMode = iota
+	recoverErrorToken
+	recoverSkip
+)
+
+// WithErrorTokens makes a lexer recover from input that no rule matches by returning an error token of a single symbol
+// and continuing with the next symbol.
+// The Err field of an error token describes the error. Without recovery, the lexer returns the [*Error] instead.
+func WithErrorTokens() Option {
+	return func(opts *options) {
+		opts.recovery = recoverErrorToken
+	}
+}
+
+// SkipUntil makes lexer recover from input that no rule matches by returning an error token that spans all the
+// symbols up to (but excluding) the next one in sync, or up to the end of the input. The first symbol is always part of
+// the error token, even if it's in sync.
+// The Err field of an error token describes the error. SkipUntil returns lexer, so it can be chained onto [NewLexer].
+//
+// Reasoning:
+// Unlike an [Option], the synchronisation set holds symbols, so it's set on the lexer itself. That way, the compiler
+// rejects symbols of another type than the ones of the lexer.
+func (lexer *Lexer[S, V]) SkipUntil(sync ...S) *Lexer[S, V] {
+	lexer.recovery = recoverSkip
+	lexer.sync = newSyncSet(sync)
+
+	return lexer
+}
+
+// SkipUntil makes lexer recover from input that no rule matches like [Lexer.SkipUntil] does and returns lexer.
+func (lexer *ReaderLexer[V]) SkipUntil(sync ...rune) *ReaderLexer[V] {
+	lexer.stream.recovery = recoverSkip
+	lexer.stream.sync = newSyncSet(sync)
+
+	return lexer
+}
+
+// SkipUntil makes lexer recover from input that no rule matches like [Lexer.SkipUntil] does and returns lexer.
+func (lexer *ByteReaderLexer[V]) SkipUntil(sync ...byte) *ByteReaderLexer[V] {
+	lexer.stream.recovery = recoverSkip
+	lexer.stream.sync = newSyncSet(sync)
+
+	return lexer
+}
+
+// SkipUntil makes lexer recover from input that no rule matches like [Lexer.SkipUntil] does and returns lexer.
+func (lexer *ModalLexer[S, V]) SkipUntil(sync ...S) *ModalLexer[S, V] {
+	lexer.lexer.SkipUntil(sync...)
+
+	return lexer
+}
+
+// Returns the synchronisation set holding the symbols of sync.
+func newSyncSet[S comparable](sync []S) map[S]bool {
+	set := make(map[S]bool, len(sync))
+
+	for _, symbol := range sync {
+		set[symbol] = true
+	}
+
+	return set
+}
+
+// A 'match' is the result of driving a [dfa.Dfa] over the input of a lexer.
+type match[S comparable, V any] struct {
+	accepting *dfa.State[S, V] // The last accepting state that was reached (nil if there's none).
+	end       int              // The offset directly after the symbols that led to accepting.
+	stuck     *dfa.State[S, V] // The last state that was reached.
+	stuckAt   int              // The offset of the first symbol without a transition from stuck.
+}
+
+// Returns the offset directly after the token of m, which succeeded for input that starts at offset start.
+// This is the end of the match, unless the match ends in trailing context (see [FollowedBy]).
+func (m match[S, V]) tokenEnd(input []S, start int) int {
+	tc := m.accepting.TrailingContext()
+
+	if tc == nil {
+		return m.end
+	}
+
+	return start + tc.TokenLen(input[start:m.end])
+}
+
+// Returns the [Error] for a failed m, for input that starts at offset start and position pos.
+func (m match[S, V]) error(start int, pos Position) *Error[S] {
+	expected := m.stuck.Symbols()
+	sortSymbols(expected)
+
+	return &Error[S]{
+		Start:      start,
+		Pos:        pos,
+		Offset:     m.stuckAt,
+		Expected:   expected,
+		Predicates: m.stuck.HasGuard(),
+	}
+}
+
+// Sorts symbols: ordered types by their value and other types by their string representation.
+func sortSymbols[S comparable](symbols []S) {
+	switch s := any(symbols).(type) {
+	case []rune:
+		slices.Sort(s)
+	case []byte:
+		slices.Sort(s)
+	case []int:
+		slices.Sort(s)
+	case []string:
+		slices.Sort(s)
+	default:
+		slices.SortFunc(symbols, func(a, b S) int { return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b)) })
+	}
+}
+
+// Returns the offset directly after the error token that starts at offset start in input (see [Lexer.SkipUntil]).
+func skipEnd[S comparable](input []S, start int, mode recoveryMode, sync map[S]bool) int {
+	idx := start + 1
+
+	if mode == recoverSkip {
+		for idx < len(input) && !sync[input[idx]] {
+			idx++
+		}
+	}
+
+	return idx
+}
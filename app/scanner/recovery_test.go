@@ -0,0 +1,242 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// Returns a [dfa.Dfa] recognizing the keyword "if", a space and a single digit.
+func newRecoveryMachine() *dfa.Dfa[rune, int] {
+	machine, _ := scanner.NewRules[rune, int]().
+		Add(scanner.Literal[rune, int]('i', 'f'), tokKeyword).
+		Add(scanner.Literal[rune, int](' '), tokSpace).
+		Add(scanner.Range[int]('0', '9'), tokIdent).
+		CompileDfa()
+
+	return machine
+}
+
+// Returns the lexemes of the tokens returned by next until it returns an error, where error tokens are wrapped in
+// angle brackets.
+func lexemes[S rune | byte](next func() (scanner.Token[S, int], error)) []string {
+	var result []string
+
+	for {
+		token, err := next()
+
+		if err != nil {
+			return result
+		}
+
+		if token.Err != nil {
+			result = append(result, "<"+text(token.Lexeme)+">")
+
+			continue
+		}
+
+		result = append(result, text(token.Lexeme))
+	}
+}
+
+// Returns lexeme as a string.
+func text[S rune | byte](lexeme []S) string {
+	if runes, ok := any(lexeme).([]rune); ok {
+		return string(runes)
+	}
+
+	return string(any(lexeme).([]byte))
+}
+
+// UT: Report input that no rule matches.
+func TestError(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		opts  []scanner.Option
+		want  scanner.Error[rune]
+		msg   string
+	}{
+		{
+			"a partial match", "if i?", nil,
+			scanner.Error[rune]{Start: 3, Offset: 4, Expected: []rune{'f'}},
+			"scanner: offset 3: no rule matches the input",
+		},
+		{
+			"a symbol without any match", "if ?", []scanner.Option{scanner.WithPositions("main.x")},
+			scanner.Error[rune]{
+				Start: 3, Pos: scanner.Position{Filename: "main.x", Offset: 3, Line: 1, Column: 4}, Offset: 3,
+				Expected: []rune{' ', 'i'}, Predicates: true,
+			},
+			"scanner: main.x:1:4: no rule matches the input",
+		},
+		{
+			"a partial match at the end of the input", "if i", nil,
+			scanner.Error[rune]{Start: 3, Offset: 4, Expected: []rune{'f'}},
+			"scanner: offset 3: no rule matches the input",
+		},
+	} {
+		t.Run("When the input ends in "+tc.name+", an 'Error' is returned.", func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Arrange.
+			lexer := scanner.NewLexer(newRecoveryMachine(), []rune(tc.input), tc.opts...)
+
+			// Act.
+			_, _ = lexer.Next()
+			_, _ = lexer.Next()
+			_, err := lexer.Next()
+
+			// Assert.
+			var got *scanner.Error[rune]
+
+			assert.Truef(t, errors.As(err, &got) && errors.Is(err, scanner.ErrNoMatch), "\n\n"+
+				"UT Name:  When the input ends in %s, an 'Error' wrapping 'ErrNoMatch' is returned.\n"+
+				"\033[32mExpected: *scanner.Error.\033[0m\n"+
+				"\033[31mActual:   %T.\033[0m\n\n", tc.name, err)
+
+			assert.Truef(t, got.Start == tc.want.Start && got.Pos == tc.want.Pos && got.Offset == tc.want.Offset &&
+				string(got.Expected) == string(tc.want.Expected) && got.Predicates == tc.want.Predicates, "\n\n"+
+				"UT Name:  When the input ends in %s, the 'Error' is correct.\n"+
+				"\033[32mExpected: %+v.\033[0m\n"+
+				"\033[31mActual:   %+v.\033[0m\n\n", tc.name, tc.want, *got)
+
+			assert.Equalf(t, err.Error(), tc.msg, "\n\n"+
+				"UT Name:  When the input ends in %s, the message of the 'Error' is correct.\n"+
+				"\033[32mExpected: %s.\033[0m\n"+
+				"\033[31mActual:   %s.\033[0m\n\n", tc.name, tc.msg, err.Error())
+		})
+	}
+}
+
+// UT: Recover from input that no rule matches.
+func TestRecovery(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	input := "if ?? if i@ 7#"
+
+	for _, tc := range []struct {
+		name string
+		opts []scanner.Option
+		sync []rune // The synchronisation set (nil if the lexer doesn't skip input).
+		want []string
+	}{
+		{
+			"error tokens", []scanner.Option{scanner.WithErrorTokens()}, nil,
+			[]string{"if", " ", "<?>", "<?>", " ", "if", " ", "<i>", "<@>", " ", "7", "<#>"},
+		},
+		{
+			"skipping until a space", nil, []rune{' '},
+			[]string{"if", " ", "<??>", " ", "if", " ", "<i@>", " ", "7", "<#>"},
+		},
+	} {
+		t.Run("When recovering using "+tc.name+", all the input is tokenized.", func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Arrange.
+			lexer := scanner.NewLexer(newRecoveryMachine(), []rune(input), tc.opts...)
+			readerLexer := scanner.NewReaderLexer(newRecoveryMachine(), strings.NewReader(input), tc.opts...)
+
+			if tc.sync != nil {
+				lexer.SkipUntil(tc.sync...)
+				readerLexer.SkipUntil(tc.sync...)
+			}
+
+			// Act.
+			got := lexemes(lexer.Next)
+
+			// Assert.
+			assert.EqualSf(t, got, tc.want, "\n\n"+
+				"UT Name:  When recovering using %s with a 'Lexer', all the input is tokenized.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+
+			// Act.
+			got = lexemes(readerLexer.Next)
+
+			// Assert.
+			assert.EqualSf(t, got, tc.want, "\n\n"+
+				"UT Name:  When recovering using %s with a 'ReaderLexer', all the input is tokenized.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+		})
+	}
+
+	t.Run("When recovering with a 'ModalLexer', the mode is left untouched.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		set, _ := newInterpolationModes().Compile()
+		lexer := scanner.NewModalLexer(set, []rune(`a1"b`), scanner.WithErrorTokens())
+
+		// Act.
+		got := lexemes(lexer.Next)
+
+		// Assert.
+		want := []string{"a", "<1>", `"`, "b"}
+
+		assert.EqualSf(t, got, want, "\n\n"+
+			"UT Name:  When recovering with a 'ModalLexer', all the input is tokenized.\n"+
+			"\033[32mExpected: %q.\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", want, got)
+
+		_, err := lexer.Next()
+
+		assert.Truef(t, err == io.EOF && lexer.Mode() == "string", "\n\n"+
+			"UT Name:  When recovering with a 'ModalLexer', the actions of the rules are performed.\n"+
+			"\033[32mExpected: EOF in mode string.\033[0m\n"+
+			"\033[31mActual:   %v in mode %s.\033[0m\n\n", err, lexer.Mode())
+	})
+
+	t.Run("When skipping input with a 'ModalLexer', the error token ends before the synchronisation symbol.",
+		func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Arrange.
+			set, _ := newInterpolationModes().Compile()
+			lexer := scanner.NewModalLexer(set, []rune(`a12"b`)).SkipUntil('"')
+
+			// Act.
+			got := lexemes(lexer.Next)
+
+			// Assert.
+			want := []string{"a", "<12>", `"`, "b"}
+
+			assert.EqualSf(t, got, want, "\n\n"+
+				"UT Name:  When skipping input with a 'ModalLexer', the error token ends before the synchronisation symbol.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", want, got)
+		})
+}
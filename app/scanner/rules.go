@@ -0,0 +1,127 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// Rules is an ordered set of rules, where each rule associates a [Fragment] with the value it produces.
+//
+// The order in which rules are added determines their priority: when multiple rules match the same input, the rule
+// that was added first wins. This is implemented by feeding the declaration order into the acceptance index of the
+// accepting [nfa.State] of every rule.
+//
+// The zero value is an empty set of rules ready to use.
+type Rules[S comparable, V any] struct {
+	rules []rule[S, V]
+}
+
+// A single rule of a [Rules] set.
+type rule[S comparable, V any] struct {
+	fragment Fragment[S, V]
+	value    V
+}
+
+// NewRules creates an empty set of [Rules].
+func NewRules[S comparable, V any]() *Rules[S, V] {
+	return &Rules[S, V]{
+		rules: nil,
+	}
+}
+
+// Add registers a rule that produces value when fragment matches.
+// It returns the set of rules, so calls can be chained.
+func (rules *Rules[S, V]) Add(fragment Fragment[S, V], value V) *Rules[S, V] {
+	rules.rules = append(rules.rules, rule[S, V]{
+		fragment: fragment,
+		value:    value,
+	})
+
+	return rules
+}
+
+// Len returns the number of rules in the set.
+func (rules *Rules[S, V]) Len() int { return len(rules.rules) }
+
+// Compile returns an [nfa.Nfa] that combines all the rules.
+//
+// Every rule is built on its own branch, starting with an epsilon transition from the start state of the nfa and
+// ending in an accepting state with the value of the rule. The acceptance index of that state equals the position of
+// the rule in the set. When the fragment of a rule has trailing context (see [FollowedBy]), the accepting state records
+// it, so a lexer can find the end of the token.
+//
+// Panics with a [*FragmentError] if a fragment can't be built (see [Rules.CompileDfa], which returns it instead).
+func (rules *Rules[S, V]) Compile() *nfa.Nfa[S, V] {
+	machine := nfa.New[S, V]()
+
+	for _, r := range rules.rules {
+		branchStart := machine.AddEpsilonTransition(machine.Start())
+
+		// NOTE: Trailing context applies to the complete rule, so it's recorded in the accepting state of the rule.
+		if trailing, ok := r.fragment.(fragTrailing[S, V]); ok {
+			accepting := machine.AddAcceptingEpsilonTransition(trailing.build(machine, branchStart), r.value)
+			machine.SetTrailingContext(accepting, trailing.context())
+
+			continue
+		}
+
+		branchEnd := r.fragment.Build(machine, branchStart)
+
+		machine.AddAcceptingEpsilonTransition(branchEnd, r.value)
+	}
+
+	return machine
+}
+
+// CompileDfa returns a minimal [dfa.Dfa] that combines all the rules.
+// See [Rules.Compile] for more information.
+// An error is returned if the rules can't be determinized (see [dfa.TryFromNfa]) or if a fragment can't be built (a
+// [*FragmentError]).
+func (rules *Rules[S, V]) CompileDfa() (machine *dfa.Dfa[S, V], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fragmentErr, ok := r.(*FragmentError)
+
+			if !ok {
+				panic(r)
+			}
+
+			machine, err = nil, fragmentErr
+		}
+	}()
+
+	machine, err = dfa.TryFromNfa(rules.Compile())
+
+	if err != nil {
+		return nil, err
+	}
+
+	machine, _ = dfa.Minimize(machine)
+
+	return machine, nil
+}
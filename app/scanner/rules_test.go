@@ -0,0 +1,200 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// UT: Add rules to a set of 'Rules'.
+func TestRules_Add(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	rules := scanner.NewRules[rune, int]()
+
+	// Act.
+	rules.Add(scanner.Literal[rune, int]('a'), 1).Add(scanner.Literal[rune, int]('b'), 2)
+
+	// Assert.
+	assert.Equalf(t, rules.Len(), 2, "\n\n"+
+		"UT Name:  When adding 2 rules, the set contains 2 rules.\n"+
+		"\033[32mExpected: 2.\033[0m\n"+
+		"\033[31mActual:   %d.\033[0m\n\n", rules.Len())
+}
+
+// UT: Compile a set of 'Rules' into an NFA.
+func TestRules_Compile(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When multiple rules match the same input, the rule that was added first wins.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		letters := scanner.RepeatAtLeast(1, scanner.AnyOf(scanner.Literal[rune, int]('i'),
+			scanner.Literal[rune, int]('f')))
+
+		rules := scanner.NewRules[rune, int]().
+			Add(scanner.Literal[rune, int]('i', 'f'), tokKeyword).
+			Add(letters, tokIdent)
+
+		// Act.
+		machine := rules.Compile()
+
+		// Assert.
+		for _, tc := range []struct {
+			input     string
+			value     int
+			acceptIdx int
+		}{
+			{"if", tokKeyword, 0},
+			{"iff", tokIdent, 1},
+			{"fi", tokIdent, 1},
+		} {
+			got, ok := machine.Match([]rune(tc.input))
+
+			assert.Truef(t, ok, "\n\n"+
+				"UT Name:  When matching %q, a match is found.\n"+
+				"\033[32mExpected: true.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.input, ok)
+
+			assert.Equalf(t, got.Value, tc.value, "\n\n"+
+				"UT Name:  When matching %q, the value of the highest priority rule is returned.\n"+
+				"\033[32mExpected: %d.\033[0m\n"+
+				"\033[31mActual:   %d.\033[0m\n\n", tc.input, tc.value, got.Value)
+
+			assert.Equalf(t, got.AcceptIdx, tc.acceptIdx, "\n\n"+
+				"UT Name:  When matching %q, the acceptance index equals the position of the rule.\n"+
+				"\033[32mExpected: %d.\033[0m\n"+
+				"\033[31mActual:   %d.\033[0m\n\n", tc.input, tc.acceptIdx, got.AcceptIdx)
+		}
+	})
+
+	t.Run("When the set is empty, the NFA does NOT match anything.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		var rules scanner.Rules[rune, int]
+
+		// Act.
+		_, ok := rules.Compile().Match([]rune("a"))
+
+		// Assert.
+		assert.Falsef(t, ok, "\n\n"+
+			"UT Name:  When the set is empty, the NFA does NOT match anything.\n"+
+			"\033[32mExpected: false.\033[0m\n"+
+			"\033[31mActual:   %t.\033[0m\n\n", ok)
+	})
+}
+
+// UT: Compile a set of 'Rules' into a DFA.
+func TestRules_CompileDfa(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When tokenizing with a compiled DFA, the value of the matching rule is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		rules := scanner.NewRules[rune, int]().
+			Add(scanner.Literal[rune, int]('a', 'b'), 1).
+			Add(scanner.RepeatAtLeast(1, scanner.Literal[rune, int]('a')), 2)
+
+		machine, _ := rules.CompileDfa()
+		lexer := scanner.NewLexer(machine, []rune("abaa"))
+
+		// Act.
+		var got []int
+
+		for token, err := lexer.Next(); err == nil; token, err = lexer.Next() {
+			got = append(got, token.Value)
+		}
+
+		// Assert.
+		assert.EqualSf(t, got, []int{1, 2}, "\n\n"+
+			"UT Name:  When tokenizing with a compiled DFA, the value of the matching rule is returned.\n"+
+			"\033[32mExpected: [1 2].\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", got)
+	})
+
+	t.Run("When rules have many opaque predicates, only the combinations that hold are built.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		rules := scanner.NewRules[rune, int]()
+
+		for idx := range 20 {
+			rules.Add(scanner.SymbolSet[rune, int](func(r rune) bool { return r == 'a'+rune(idx) }), idx)
+		}
+
+		// Act.
+		machine, err := rules.CompileDfa()
+
+		// Assert.
+		assert.Nilf(t, err, "\n\n"+
+			"UT Name:  When rules have many opaque predicates, NO error is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", err)
+
+		assert.Equalf(t, len(machine.States()), 21, "\n\n"+
+			"UT Name:  When rules have many opaque predicates, only the combinations that hold are built.\n"+
+			"\033[32mExpected: 21.\033[0m\n"+
+			"\033[31mActual:   %d.\033[0m\n\n", len(machine.States()))
+	})
+
+	t.Run("When an operand of a product can't be determinized, an error is returned.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		var fragments []scanner.Fragment[rune, int]
+
+		for idx := range 70 {
+			fragments = append(fragments, scanner.SymbolSet[rune, int](func(r rune) bool { return r == 'a'+rune(idx) }))
+		}
+
+		rules := scanner.NewRules[rune, int]().
+			Add(scanner.Intersect(scanner.AnyOf(fragments...), scanner.Literal[rune, int]('a')), 1)
+
+		// Act.
+		machine, err := rules.CompileDfa()
+
+		// Assert.
+		var fragmentErr *scanner.FragmentError
+
+		assert.Truef(t, errors.As(err, &fragmentErr), "\n\n"+
+			"UT Name:  When an operand of a product can't be determinized, a '*FragmentError' is returned.\n"+
+			"\033[32mExpected: true.\033[0m\n"+
+			"\033[31mActual:   false (%v).\033[0m\n\n", err)
+
+		assert.Nilf(t, machine, "\n\n"+
+			"UT Name:  When an operand of a product can't be determinized, NO DFA is returned.\n"+
+			"\033[32mExpected: <nil>.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", machine)
+	})
+}
@@ -0,0 +1,311 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// RuneRange is an inclusive range of runes.
+type RuneRange struct {
+	Lo rune // The first rune of the range.
+	Hi rune // The last rune of the range.
+}
+
+// RuneSet is an immutable set of runes, represented by sorted, non-overlapping ranges.
+//
+// Unlike a function passed to [SymbolSet], a RuneSet can be inspected. It implements [nfa.Class], so the fragments that
+// are built from it (see [Set]) can be determinized exactly and printed.
+type RuneSet struct {
+	ranges  []RuneRange
+	name    string // The name of the Unicode table the set was built from (if any).
+	negated bool   // True if the set is the negation of another set (see [RuneSet.Negate]).
+}
+
+// NewRuneSet returns the [RuneSet] holding the runes of ranges, which may overlap and be given in any order.
+// Panics if a range has a Hi that's less than its Lo.
+func NewRuneSet(ranges ...RuneRange) RuneSet {
+	for _, r := range ranges {
+		if r.Hi < r.Lo {
+			panic("NewRuneSet: Hi cannot be less than Lo")
+		}
+	}
+
+	return RuneSet{ranges: normalizeRanges(slices.Clone(ranges))}
+}
+
+// TableSet returns the [RuneSet] holding the runes of table.
+func TableSet(table *unicode.RangeTable) RuneSet {
+	var ranges []RuneRange
+
+	for _, r := range table.R16 {
+		ranges = appendStrided(ranges, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+
+	for _, r := range table.R32 {
+		ranges = appendStrided(ranges, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+
+	return RuneSet{ranges: normalizeRanges(ranges)}
+}
+
+// CategorySet returns the [RuneSet] holding the runes of the Unicode category name (e.g., "L" or "Nd").
+// It returns false if there's no such category in [unicode.Categories].
+func CategorySet(name string) (RuneSet, bool) {
+	return namedSet(unicode.Categories, name)
+}
+
+// ScriptSet returns the [RuneSet] holding the runes of the Unicode script name (e.g., "Latin" or "Greek").
+// It returns false if there's no such script in [unicode.Scripts].
+func ScriptSet(name string) (RuneSet, bool) {
+	return namedSet(unicode.Scripts, name)
+}
+
+// PropertySet returns the [RuneSet] holding the runes of the Unicode property name (e.g., "White_Space" or
+// "Other_ID_Start"). It returns false if there's no such property in [unicode.Properties].
+func PropertySet(name string) (RuneSet, bool) {
+	return namedSet(unicode.Properties, name)
+}
+
+// Returns the [RuneSet] for the table called name in tables.
+func namedSet(tables map[string]*unicode.RangeTable, name string) (RuneSet, bool) {
+	table, ok := tables[name]
+
+	if !ok {
+		return RuneSet{}, false
+	}
+
+	set := TableSet(table)
+	set.name = name
+
+	return set, true
+}
+
+// Appends the runes from lo to hi (inclusive) that are a multiple of stride away from lo to ranges.
+func appendStrided(ranges []RuneRange, lo, hi, stride rune) []RuneRange {
+	if stride == 1 {
+		return append(ranges, RuneRange{Lo: lo, Hi: hi})
+	}
+
+	for r := lo; r <= hi; r += stride {
+		ranges = append(ranges, RuneRange{Lo: r, Hi: r})
+	}
+
+	return ranges
+}
+
+// Sorts ranges and merges overlapping and adjacent ones.
+func normalizeRanges(ranges []RuneRange) []RuneRange {
+	slices.SortFunc(ranges, func(a, b RuneRange) int { return int(a.Lo - b.Lo) })
+
+	merged := ranges[:0]
+
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Lo <= merged[n-1].Hi+1 {
+			merged[n-1].Hi = max(merged[n-1].Hi, r.Hi)
+
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	return slices.Clip(merged)
+}
+
+// Union returns the [RuneSet] holding the runes that are in set or in any of others.
+func (set RuneSet) Union(others ...RuneSet) RuneSet {
+	ranges := slices.Clone(set.ranges)
+
+	for _, other := range others {
+		ranges = append(ranges, other.ranges...)
+	}
+
+	return RuneSet{ranges: normalizeRanges(ranges)}
+}
+
+// Negate returns the [RuneSet] holding all the runes (up to [unicode.MaxRune]) that are NOT in set.
+func (set RuneSet) Negate() RuneSet {
+	var ranges []RuneRange
+
+	next := rune(0)
+
+	for _, r := range set.ranges {
+		if r.Lo > next {
+			ranges = append(ranges, RuneRange{Lo: next, Hi: r.Lo - 1})
+		}
+
+		next = r.Hi + 1
+	}
+
+	if next <= unicode.MaxRune {
+		ranges = append(ranges, RuneRange{Lo: next, Hi: unicode.MaxRune})
+	}
+
+	return RuneSet{ranges: ranges, negated: !set.negated}
+}
+
+// Intersect returns the [RuneSet] holding the runes that are in both set and other.
+func (set RuneSet) Intersect(other RuneSet) RuneSet {
+	return RuneSet{ranges: set.Negate().Union(other.Negate()).Negate().ranges}
+}
+
+// Contains reports whether r is a member of the set.
+func (set RuneSet) Contains(r rune) bool {
+	idx := sort.Search(len(set.ranges), func(i int) bool { return set.ranges[i].Hi >= r })
+
+	return idx < len(set.ranges) && set.ranges[idx].Lo <= r
+}
+
+// IsEmpty reports whether the set doesn't hold any rune.
+func (set RuneSet) IsEmpty() bool { return len(set.ranges) == 0 }
+
+// Ranges returns the ranges of the set, sorted and without overlapping or adjacent ranges.
+func (set RuneSet) Ranges() []RuneRange { return slices.Clone(set.ranges) }
+
+// Boundaries returns 0, followed by the first rune of every range and the rune directly after every range (see
+// [nfa.Class]).
+func (set RuneSet) Boundaries() []rune {
+	boundaries := make([]rune, 0, 2*len(set.ranges)+1)
+
+	if len(set.ranges) == 0 || set.ranges[0].Lo != 0 {
+		boundaries = append(boundaries, 0)
+	}
+
+	for _, r := range set.ranges {
+		boundaries = append(boundaries, r.Lo)
+
+		if r.Hi < unicode.MaxRune {
+			boundaries = append(boundaries, r.Hi+1)
+		}
+	}
+
+	return boundaries
+}
+
+// String returns the set in the syntax of a character class of a regular expression (e.g., "[0-9A-Z_a-z]"). A set that
+// was built from a named Unicode table is returned as "\p{name}".
+func (set RuneSet) String() string {
+	if set.name != "" {
+		return `\p{` + set.name + `}`
+	}
+
+	var sb strings.Builder
+
+	sb.WriteByte('[')
+
+	for _, r := range set.ranges {
+		sb.WriteString(quoteClassRune(r.Lo))
+
+		if r.Hi != r.Lo {
+			if r.Hi > r.Lo+1 {
+				sb.WriteByte('-')
+			}
+
+			sb.WriteString(quoteClassRune(r.Hi))
+		}
+	}
+
+	sb.WriteByte(']')
+
+	return sb.String()
+}
+
+// Returns r as it's written inside a character class.
+func quoteClassRune(r rune) string {
+	switch {
+	case strings.ContainsRune(`\]-^[`, r):
+		return `\` + string(r)
+	case r < 0x80 && unicode.IsPrint(r):
+		return string(r)
+	case r <= 0xFF:
+		return fmt.Sprintf(`\x%02X`, r)
+	default:
+		return fmt.Sprintf(`\x{%X}`, r)
+	}
+}
+
+// A [Fragment] that matches any single rune of a [RuneSet].
+type fragRuneSet[V any] struct {
+	set RuneSet
+}
+
+// Range creates a [Fragment] that matches any single rune from lo to hi (inclusive).
+// Panics if hi is less than lo.
+func Range[V any](lo, hi rune) Fragment[rune, V] {
+	if hi < lo {
+		panic("Range: hi cannot be less than lo")
+	}
+
+	return Set[V](NewRuneSet(RuneRange{Lo: lo, Hi: hi}))
+}
+
+// Set creates a [Fragment] that matches any single rune of set.
+func Set[V any](set RuneSet) Fragment[rune, V] {
+	return fragRuneSet[V]{
+		set: set,
+	}
+}
+
+// Category creates a [Fragment] that matches any single rune of the Unicode category name (see [CategorySet]).
+// Panics if there's no such category.
+func Category[V any](name string) Fragment[rune, V] {
+	set, ok := CategorySet(name)
+
+	if !ok {
+		panic("Category: unknown category " + name)
+	}
+
+	return Set[V](set)
+}
+
+// Script creates a [Fragment] that matches any single rune of the Unicode script name (see [ScriptSet]).
+// Panics if there's no such script.
+func Script[V any](name string) Fragment[rune, V] {
+	set, ok := ScriptSet(name)
+
+	if !ok {
+		panic("Script: unknown script " + name)
+	}
+
+	return Set[V](set)
+}
+
+// Build adds a class transition for the set, which (unlike the transition of a [SymbolSet]) can be determinized
+// exactly.
+func (frag fragRuneSet[V]) Build(machine *nfa.Nfa[rune, V], startState *nfa.State[rune, V]) *nfa.State[rune, V] {
+	endState := machine.NewState()
+
+	machine.AddClassTransition(startState, endState, frag.set)
+
+	return endState
+}
@@ -0,0 +1,266 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// UT: Create a new 'RuneSet'.
+func TestNewRuneSet(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	t.Run("When ranges overlap or are adjacent, they are merged.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		got := scanner.NewRuneSet(
+			scanner.RuneRange{Lo: 'x', Hi: 'z'}, scanner.RuneRange{Lo: 'a', Hi: 'f'}, scanner.RuneRange{Lo: 'g', Hi: 'k'},
+			scanner.RuneRange{Lo: 'c', Hi: 'd'},
+		).Ranges()
+
+		// Assert.
+		want := []scanner.RuneRange{{Lo: 'a', Hi: 'k'}, {Lo: 'x', Hi: 'z'}}
+
+		assert.EqualSf(t, got, want, "\n\n"+
+			"UT Name:  When ranges overlap or are adjacent, they are merged.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", want, got)
+	})
+
+	t.Run("When a range is invalid, the function panics.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		fn := func() { scanner.NewRuneSet(scanner.RuneRange{Lo: 'z', Hi: 'a'}) }
+
+		// Assert.
+		assert.Panicf(t, fn, "\n\n"+
+			"UT Name:  When a range is invalid, the function panics.\n"+
+			"\033[32mExpected: panic.\033[0m\n"+
+			"\033[31mActual:   NOT panic.\033[0m\n\n")
+	})
+}
+
+// UT: Combine 'RuneSet's.
+func TestRuneSet_Operations(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	lower := scanner.NewRuneSet(scanner.RuneRange{Lo: 'a', Hi: 'z'})
+	hex := scanner.NewRuneSet(scanner.RuneRange{Lo: '0', Hi: '9'}, scanner.RuneRange{Lo: 'a', Hi: 'f'})
+
+	for _, tc := range []struct {
+		name string
+		set  scanner.RuneSet
+		want []scanner.RuneRange
+	}{
+		{"the union", lower.Union(hex), []scanner.RuneRange{{Lo: '0', Hi: '9'}, {Lo: 'a', Hi: 'z'}}},
+		{"the intersection", lower.Intersect(hex), []scanner.RuneRange{{Lo: 'a', Hi: 'f'}}},
+		{"the negation", lower.Negate(), []scanner.RuneRange{{Lo: 0, Hi: 'a' - 1}, {Lo: 'z' + 1, Hi: unicode.MaxRune}}},
+		{"the double negation", hex.Negate().Negate(), hex.Ranges()},
+	} {
+		// Act.
+		got := tc.set.Ranges()
+
+		// Assert.
+		assert.EqualSf(t, got, tc.want, "\n\n"+
+			"UT Name:  When computing %s of 'RuneSet's, the ranges are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Get the boundaries of a 'RuneSet'.
+func TestRuneSet_Boundaries(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for _, tc := range []struct {
+		name string
+		set  scanner.RuneSet
+		want []rune
+	}{
+		{"an empty set", scanner.NewRuneSet(), []rune{0}},
+		{"a set of ranges", scanner.NewRuneSet(scanner.RuneRange{Lo: 'a', Hi: 'c'}), []rune{0, 'a', 'd'}},
+		{"a negated set", scanner.NewRuneSet(scanner.RuneRange{Lo: 'a', Hi: 'c'}).Negate(), []rune{0, 'a', 'd'}},
+	} {
+		// Act.
+		got := tc.set.Boundaries()
+
+		// Assert.
+		assert.EqualSf(t, got, tc.want, "\n\n"+
+			"UT Name:  When getting the boundaries of %s, the boundaries are correct.\n"+
+			"\033[32mExpected: %v.\033[0m\n"+
+			"\033[31mActual:   %v.\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Get the string representation of a 'RuneSet'.
+func TestRuneSet_String(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	greek, _ := scanner.ScriptSet("Greek")
+
+	for _, tc := range []struct {
+		set  scanner.RuneSet
+		want string
+	}{
+		{scanner.NewRuneSet(), "[]"},
+		{scanner.NewRuneSet(scanner.RuneRange{Lo: '_', Hi: '_'}, scanner.RuneRange{Lo: 'a', Hi: 'z'}), "[_a-z]"},
+		{scanner.NewRuneSet(scanner.RuneRange{Lo: '-', Hi: '-'}, scanner.RuneRange{Lo: ']', Hi: '^'}), `[\-\]\^]`},
+		{
+			scanner.NewRuneSet(scanner.RuneRange{Lo: '\n', Hi: '\n'}, scanner.RuneRange{Lo: 'é', Hi: '€'}),
+			`[\x0A\xE9-\x{20AC}]`,
+		},
+		{greek, `\p{Greek}`},
+	} {
+		// Act.
+		got := tc.set.String()
+
+		// Assert.
+		assert.Equalf(t, got, tc.want, "\n\n"+
+			"UT Name:  When converting a 'RuneSet' to a string, the result is a character class.\n"+
+			"\033[32mExpected: %s.\033[0m\n"+
+			"\033[31mActual:   %s.\033[0m\n\n", tc.want, got)
+	}
+}
+
+// UT: Create a 'RuneSet' from a Unicode table.
+func TestTableSet(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	for name, table := range map[string]*unicode.RangeTable{"Lu": unicode.Lu, "Nd": unicode.Nd, "Han": unicode.Han} {
+		// Arrange.
+		set := scanner.TableSet(table)
+
+		// Act & Assert.
+		for r := rune(0); r <= unicode.MaxRune; r += 7 {
+			if got := set.Contains(r); got != unicode.Is(table, r) {
+				assert.Equalf(t, got, unicode.Is(table, r), "\n\n"+
+					"UT Name:  When creating a 'RuneSet' from the table %s, it contains the same runes.\n"+
+					"\033[32mExpected: %t for %U.\033[0m\n"+
+					"\033[31mActual:   %t.\033[0m\n\n", name, unicode.Is(table, r), r, got)
+
+				break
+			}
+		}
+	}
+}
+
+// UT: Match runes using rune set 'Fragment's.
+func TestSet(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	// Arrange.
+	idStart, _ := scanner.PropertySet("Other_ID_Start")
+	letters, _ := scanner.CategorySet("L")
+	digits, _ := scanner.CategorySet("Nd")
+	start := letters.Union(idStart, scanner.NewRuneSet(scanner.RuneRange{Lo: '_', Hi: '_'}))
+	identifier := scanner.Sequence(
+		scanner.Set[int](start),
+		scanner.RepeatAtLeast(0, scanner.Set[int](start.Union(digits))),
+	)
+
+	for _, tc := range []struct {
+		name     string
+		fragment scanner.Fragment[rune, int]
+		accepted []string
+		rejected []string
+	}{
+		{"a range", scanner.Range[int]('a', 'c'), []string{"a", "b", "c"}, []string{"", "d", "ab"}},
+		{"a category", scanner.Category[int]("Lu"), []string{"A", "Ä", "Σ"}, []string{"a", "1"}},
+		{"a script", scanner.Script[int]("Greek"), []string{"α", "Σ"}, []string{"a", "Я"}},
+		{"an identifier", identifier, []string{"x", "_1", "été", "変数", "x٣"}, []string{"", "1x", "a-b"}},
+	} {
+		for _, input := range tc.accepted {
+			got := fullMatch(tc.fragment, input)
+
+			assert.Truef(t, got, "\n\n"+
+				"UT Name:  When matching %s, the input %q is accepted.\n"+
+				"\033[32mExpected: true.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.name, input, got)
+		}
+
+		for _, input := range tc.rejected {
+			got := fullMatch(tc.fragment, input)
+
+			assert.Falsef(t, got, "\n\n"+
+				"UT Name:  When matching %s, the input %q is rejected.\n"+
+				"\033[32mExpected: false.\033[0m\n"+
+				"\033[31mActual:   %t.\033[0m\n\n", tc.name, input, got)
+		}
+	}
+
+	t.Run("When compiling overlapping categories into a DFA, the rule that was added first wins.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		rules := scanner.NewRules[rune, int]().
+			Add(scanner.Category[int]("Lu"), 1).
+			Add(scanner.Category[int]("L"), 2).
+			Add(scanner.Script[int]("Greek"), 3)
+
+		// Act.
+		machine, _ := rules.CompileDfa()
+
+		// Assert.
+		for _, tc := range []struct {
+			symbol rune
+			want   int
+		}{
+			{'A', 1}, {'a', 2}, {'Σ', 1}, {'α', 2}, {'͵', 3},
+		} {
+			state := machine.Start().OutgoingFor(tc.symbol)
+
+			assert.NotNilf(t, state, "\n\n"+
+				"UT Name:  When compiling overlapping categories into a DFA, %q has a transition.\n"+
+				"\033[32mExpected: NOT <nil>.\033[0m\n"+
+				"\033[31mActual:   <nil>.\033[0m\n\n", tc.symbol)
+
+			assert.Equalf(t, state.AcceptValue(), tc.want, "\n\n"+
+				"UT Name:  When compiling overlapping categories into a DFA, %q is matched by rule %d.\n"+
+				"\033[32mExpected: %d.\033[0m\n"+
+				"\033[31mActual:   %d.\033[0m\n\n", tc.symbol, tc.want, tc.want, state.AcceptValue())
+		}
+	})
+
+	t.Run("When the category is unknown, the function panics.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Act.
+		fn := func() { scanner.Category[int]("Unknown") }
+
+		// Assert.
+		assert.Panicf(t, fn, "\n\n"+
+			"UT Name:  When the category is unknown, the function panics.\n"+
+			"\033[32mExpected: panic.\033[0m\n"+
+			"\033[31mActual:   NOT panic.\033[0m\n\n")
+	})
+}
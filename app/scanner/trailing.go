@@ -0,0 +1,190 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner
+
+import (
+	"github.com/kdeconinck/realign/automata/dfa"
+	"github.com/kdeconinck/realign/automata/nfa"
+)
+
+// A [Fragment] that matches a [Fragment] only when it's followed by another one, which isn't part of the token.
+type fragTrailing[S comparable, V any] struct {
+	head Fragment[S, V]
+	tail Fragment[S, V]
+}
+
+// FollowedBy creates a [Fragment] that matches head, but only when it's followed by tail (like flex's head/tail
+// rules). The symbols matched by tail must be present, but they aren't part of the token: a lexer rewinds to the end of
+// head after the match.
+//
+// Both head and tail may have a variable length. When the end of head is ambiguous, the longest head is taken.
+//
+// Trailing context applies to a complete rule, so the fragment must be the top-level fragment of a rule (see [Rules]).
+// Panics if head matches the empty input, since that would result in a token without symbols.
+func FollowedBy[S comparable, V any](head, tail Fragment[S, V]) Fragment[S, V] {
+	if fixedLength(head) == 0 {
+		panic("FollowedBy: head cannot match the empty input")
+	}
+
+	return fragTrailing[S, V]{
+		head: head,
+		tail: tail,
+	}
+}
+
+// Build panics, since trailing context can only be used as the top-level fragment of a rule.
+func (frag fragTrailing[S, V]) Build(_ *nfa.Nfa[S, V], _ *nfa.State[S, V]) *nfa.State[S, V] {
+	panic("FollowedBy: trailing context must be the top-level fragment of a rule")
+}
+
+// Builds the head, followed by the tail, and returns the final state of the constructed part.
+func (frag fragTrailing[S, V]) build(machine *nfa.Nfa[S, V], startState *nfa.State[S, V]) *nfa.State[S, V] {
+	return frag.tail.Build(machine, frag.head.Build(machine, startState))
+}
+
+// Returns the trailing context of the accepting state of a rule built by frag.
+// Panics if head matches the empty input.
+func (frag fragTrailing[S, V]) context() *nfa.TrailingContext[S] {
+	tc := &nfa.TrailingContext[S]{
+		HeadLen: fixedLength(frag.head),
+		TailLen: fixedLength(frag.tail),
+	}
+
+	if tc.HeadLen >= 0 {
+		return tc
+	}
+
+	head := compileFragment(frag.head)
+
+	if head.Start().IsAccepting() {
+		panic("FollowedBy: head cannot match the empty input")
+	}
+
+	if tc.TailLen >= 0 {
+		return tc
+	}
+
+	tail := compileFragment(frag.tail)
+
+	tc.Split = func(match []S) int {
+		var ends []int
+
+		state := head.Start()
+
+		for idx := 0; idx < len(match) && state != nil; idx++ {
+			if state = state.OutgoingFor(match[idx]); state != nil && state.IsAccepting() {
+				ends = append(ends, idx+1)
+			}
+		}
+
+		for idx := len(ends) - 1; idx >= 0; idx-- {
+			if accepts(tail, match[ends[idx]:]) {
+				return ends[idx]
+			}
+		}
+
+		// NOTE: Every match of the rule consists of a head and a tail, so this is only reached for other input.
+		return len(match)
+	}
+
+	return tc
+}
+
+// Reports whether machine accepts input completely.
+func accepts[S comparable, V any](machine *dfa.Dfa[S, V], input []S) bool {
+	state := machine.Start()
+
+	for _, symbol := range input {
+		if state = state.OutgoingFor(symbol); state == nil {
+			return false
+		}
+	}
+
+	return state.IsAccepting()
+}
+
+// Returns the number of symbols that every match of fragment consists of or -1 if the length of the matches varies (or
+// can't be determined).
+func fixedLength[S comparable, V any](fragment Fragment[S, V]) int {
+	switch frag := fragment.(type) {
+	case fragLiteral[S, V]:
+		return len(frag.symbols)
+
+	case fragSymbolSet[S, V]:
+		return 1
+
+	case fragAssertion[S, V]:
+		return 0
+
+	case fragCapture[S, V]:
+		return fixedLength(frag.fragment)
+
+	case fragSequence[S, V]:
+		total := 0
+
+		for _, sub := range frag.fragments {
+			n := fixedLength(sub)
+
+			if n < 0 {
+				return -1
+			}
+
+			total += n
+		}
+
+		return total
+
+	case fragAnyOf[S, V]:
+		n := fixedLength(frag.fragments[0])
+
+		for _, sub := range frag.fragments[1:] {
+			if fixedLength(sub) != n {
+				return -1
+			}
+		}
+
+		return n
+
+	case fragRepeat[S, V]:
+		n := fixedLength(frag.fragment)
+
+		if n < 0 || (n > 0 && (!frag.hasMax || frag.minOccurence != frag.maxOccurence)) {
+			return -1
+		}
+
+		return n * frag.minOccurence
+	}
+
+	// NOTE: The remaining fragments over runes are checked separately, since they can't be matched with a type S.
+	switch frag := any(fragment).(type) {
+	case fragRuneSet[V]:
+		return 1
+	case fragFoldedLiteral[V]:
+		return len(frag.runes)
+	}
+
+	return -1
+}
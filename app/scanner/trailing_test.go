@@ -0,0 +1,157 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2025 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package scanner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/realign/assert"
+	"github.com/kdeconinck/realign/scanner"
+)
+
+// The kinds of tokens of a language with trailing context.
+const (
+	tokInt = iota + 1
+	tokFloat
+	tokRange
+	tokCall
+	tokWord
+	tokBlank
+	tokParen
+)
+
+// Returns the rules of a language where trailing context is required to recognize its tokens.
+func newTrailingRules() *scanner.Rules[rune, int] {
+	digit := scanner.Range[int]('0', '9')
+	digits := scanner.RepeatAtLeast(1, digit)
+	float := scanner.Sequence(digits, scanner.Literal[rune, int]('.'), scanner.RepeatAtLeast(0, digit))
+	word := scanner.RepeatAtLeast(1, scanner.Range[int]('a', 'z'))
+	blanks := scanner.RepeatAtLeast(0, scanner.Literal[rune, int](' '))
+
+	return scanner.NewRules[rune, int]().
+		Add(scanner.FollowedBy(digits, scanner.Literal[rune, int]('.', '.')), tokInt).
+		Add(digits, tokInt).
+		Add(float, tokFloat).
+		Add(scanner.Literal[rune, int]('.', '.'), tokRange).
+		Add(scanner.FollowedBy(word, scanner.Sequence(blanks, scanner.Literal[rune, int]('('))), tokCall).
+		Add(word, tokWord).
+		Add(scanner.RepeatAtLeast(1, scanner.Literal[rune, int](' ')), tokBlank).
+		Add(scanner.Literal[rune, int]('('), tokParen)
+}
+
+// UT: Tokenize input with rules that have trailing context.
+func TestFollowedBy(t *testing.T) {
+	t.Parallel() // Enable parallel execution.
+
+	machine, _ := newTrailingRules().CompileDfa()
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"a fixed trailing context", "1..25 1.5 7.", []string{"1", "..", "25", " ", "1.5", " ", "7."}},
+		{"a variable trailing context", "f  (x) go", []string{"f", "  ", "(", "x", "<)>", " ", "go"}},
+	} {
+		t.Run("When the input has "+tc.name+", the lexer rewinds to the end of the token.", func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Act.
+			got := lexemes(scanner.NewLexer(machine, []rune(tc.input), scanner.WithErrorTokens()).Next)
+
+			// Assert.
+			assert.EqualSf(t, got, tc.want, "\n\n"+
+				"UT Name:  When the input has %s, the 'Lexer' rewinds to the end of the token.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+
+			// Act.
+			reader := strings.NewReader(tc.input)
+			got = lexemes(scanner.NewReaderLexer(machine, reader, scanner.WithErrorTokens()).Next)
+
+			// Assert.
+			assert.EqualSf(t, got, tc.want, "\n\n"+
+				"UT Name:  When the input has %s, the 'ReaderLexer' rewinds to the end of the token.\n"+
+				"\033[32mExpected: %q.\033[0m\n"+
+				"\033[31mActual:   %q.\033[0m\n\n", tc.name, tc.want, got)
+		})
+	}
+
+	t.Run("When the end of the head is ambiguous, the longest head is taken.", func(t *testing.T) {
+		t.Parallel() // Enable parallel execution.
+
+		// Arrange.
+		as := scanner.RepeatAtLeast(1, scanner.Literal[rune, int]('a'))
+		b := scanner.Literal[rune, int]('b')
+		tail := scanner.Sequence(scanner.RepeatAtLeast(0, scanner.Literal[rune, int]('a')), b)
+		rules := scanner.NewRules[rune, int]().
+			Add(scanner.FollowedBy(as, tail), 1).
+			Add(b, 2)
+
+		// Act.
+		machine, _ := rules.CompileDfa()
+		got := lexemes(scanner.NewLexer(machine, []rune("aaab")).Next)
+
+		// Assert.
+		want := []string{"aaa", "b"}
+
+		assert.EqualSf(t, got, want, "\n\n"+
+			"UT Name:  When the end of the head is ambiguous, the longest head is taken.\n"+
+			"\033[32mExpected: %q.\033[0m\n"+
+			"\033[31mActual:   %q.\033[0m\n\n", want, got)
+	})
+
+	for _, tc := range []struct {
+		name string
+		fn   func()
+	}{
+		{
+			"the head matches the empty input",
+			func() {
+				head := scanner.RepeatAtLeast(0, scanner.Range[int]('a', 'z'))
+				scanner.NewRules[rune, int]().Add(scanner.FollowedBy(head, scanner.Range[int]('0', '9')), 1).Compile()
+			},
+		},
+		{
+			"the trailing context isn't the top-level fragment of a rule",
+			func() {
+				trailing := scanner.FollowedBy(scanner.Range[int]('a', 'z'), scanner.Range[int]('0', '9'))
+				scanner.NewRules[rune, int]().Add(scanner.Sequence(trailing, trailing), 1).Compile()
+			},
+		},
+	} {
+		t.Run("When "+tc.name+", the function panics.", func(t *testing.T) {
+			t.Parallel() // Enable parallel execution.
+
+			// Assert.
+			assert.Panicf(t, tc.fn, "\n\n"+
+				"UT Name:  When %s, the function panics.\n"+
+				"\033[32mExpected: panic.\033[0m\n"+
+				"\033[31mActual:   NOT panic.\033[0m\n\n", tc.name)
+		})
+	}
+}